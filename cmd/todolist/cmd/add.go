@@ -3,10 +3,10 @@ package cmd
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/quickadd"
 	"github.com/user/todolist/internal/ui"
 )
 
@@ -15,17 +15,45 @@ var (
 	addDescription string
 	addPriority    string
 	addCategory    string
+	addProject     string
 	addDueDate     string
 	addReminder    string
+	addParent      string
+	addRepeat      string
+	addTags        []string
+	addContext     string
+	addEstimate    string
+	addEnergy      string
+	addBlockedBy   []string
+	addRaw         bool
 
 	addCmd = &cobra.Command{
 		Use:   "add",
 		Short: "Add a new task",
-		Long:  `Add a new task to your to-do list with title, description, priority, category, due date, and reminder.`,
-		RunE:  runAddCmd,
+		Long: `Add a new task to your to-do list with title, description, priority, category, due date, and reminder.
+
+Metadata can also be written inline in the title (quick-add syntax):
+  !high !medium !low   priority
+  #name                category
+  +name                tag
+  @name                context (e.g. @phone)
+  ~15m                 time estimate
+  %low %medium %high   energy the task takes
+and a due date such as "tomorrow 3pm" at the end of the title.
+Flags take precedence over inline metadata. Use --raw to keep the title as typed.`,
+		RunE: runAddCmd,
 		Example: `  todolist add "Complete project report" --priority high --category work --due tomorrow
   todolist add "Read book" --priority medium
-  todolist add --title "Call doctor" --due "next week" --priority high`,
+  todolist add --title "Call doctor" --due "next week" --priority high
+  todolist add 'Call dentist tomorrow 3pm !high #health @phone ~15m'
+  todolist add "Sort receipts" --energy low --estimate 15m
+  todolist add "Send invoice" --due "friday 5pm" --reminder "in 2h"
+  todolist add "Find insurance card" --parent 1741359296120413000
+  todolist add "Submit claim" --blocked-by 1741359296120413000
+  todolist add "Draft landing page copy" --project "website relaunch"
+  todolist add "Take meds" --due "2025-01-01 09:00" --repeat daily
+  todolist add "Pay rent" --due 2025-01-01 --repeat monthly
+  todolist add "Water plants" --repeat "3 days after completion"`,
 	}
 )
 
@@ -34,127 +62,132 @@ func init() {
 	addCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Task description")
 	addCmd.Flags().StringVarP(&addPriority, "priority", "p", "medium", "Task priority (low, medium, high)")
 	addCmd.Flags().StringVarP(&addCategory, "category", "c", "inbox", "Task category")
-	addCmd.Flags().StringVar(&addDueDate, "due", "", "Due date (YYYY-MM-DD, today, tomorrow, next week)")
-	addCmd.Flags().StringVar(&addReminder, "reminder", "", "Reminder time (YYYY-MM-DD, today, tomorrow, next week)")
+	addCmd.Flags().StringVar(&addProject, "project", "", "Name or ID of the project the task belongs to")
+	addCmd.Flags().StringVar(&addDueDate, "due", "", "Due date (YYYY-MM-DD, tomorrow 3pm, friday, in 3 days, end of month, ...)")
+	addCmd.Flags().StringVar(&addReminder, "reminder", "", "Reminder time (YYYY-MM-DD HH:MM, in 2h, tomorrow morning, eod, ...)")
+	addCmd.Flags().StringVar(&addParent, "parent", "", "ID of the parent task to add this task as a subtask of")
+	addCmd.Flags().StringVar(&addRepeat, "repeat", "", "Repeat rule (daily, weekly, monthly, yearly, weekdays, 'every 2 weeks', 'every mon,fri', '3 days after completion')")
+	addCmd.Flags().StringSliceVar(&addTags, "tag", nil, "Tag to attach to the task (can be repeated)")
+	addCmd.Flags().StringVar(&addContext, "context", "", "Context the task needs (e.g. phone, computer, errands)")
+	addCmd.Flags().StringVar(&addEstimate, "estimate", "", "Time estimate (e.g. 15m, 1h30m)")
+	addCmd.Flags().StringVar(&addEnergy, "energy", "", "Energy the task takes (low, medium, high)")
+	addCmd.Flags().StringSliceVar(&addBlockedBy, "blocked-by", nil, "ID of a task that must be completed first (can be repeated)")
+	addCmd.Flags().BoolVar(&addRaw, "raw", false, "Do not parse quick-add syntax in the title")
+	structured(addCmd)
 }
 
 func runAddCmd(cmd *cobra.Command, args []string) error {
-	title := strings.Join(args, " ")
-	if title == "" {
-		return fmt.Errorf("title cannot be empty")
+	input := strings.Join(args, " ")
+	if input == "" {
+		input = addTitle
+	}
+	if input == "" {
+		return models.Invalidf("title cannot be empty")
 	}
 
+	// Extract inline metadata from the title
+	spec := models.TaskSpec{Title: input}
+	if !addRaw {
+		var err error
+		spec, err = quickadd.Parse(input, dateParser())
+		if err != nil {
+			return models.Invalidf("invalid quick-add syntax: %w", err)
+		}
+	}
+	parsedInline := spec.Title != input
+	spec.Description = addDescription
+
 	// Parse due date if provided
-	var dueDate time.Time
 	if addDueDate != "" {
-		var err error
-		dueDate, err = parseDateTime(addDueDate)
+		dueDate, err := dateParser().Parse(addDueDate)
 		if err != nil {
-			return fmt.Errorf("invalid due date format: %w", err)
+			return models.Invalidf("invalid due date format: %w", err)
 		}
+		spec.DueDate = dueDate
 	}
 
 	// Parse reminder time if provided
-	var reminderAt time.Time
 	if addReminder != "" {
-		var err error
-		reminderAt, err = parseDateTime(addReminder)
+		reminderAt, err := dateParser().Parse(addReminder)
 		if err != nil {
-			return fmt.Errorf("invalid reminder time format: %w", err)
+			return models.Invalidf("invalid reminder time format: %w", err)
 		}
+		spec.ReminderAt = reminderAt
 	}
 
 	// Validate priority
-	priority := models.Priority(strings.ToLower(addPriority))
-	if priority != models.PriorityLow && priority != models.PriorityMedium && priority != models.PriorityHigh {
-		return fmt.Errorf("invalid priority: %s (must be low, medium, or high)", addPriority)
+	if spec.Priority == "" || cmd.Flags().Changed("priority") {
+		priority := models.Priority(strings.ToLower(addPriority))
+		if priority != models.PriorityLow && priority != models.PriorityMedium && priority != models.PriorityHigh {
+			return models.Invalidf("invalid priority: %s (must be low, medium, or high)", addPriority)
+		}
+		spec.Priority = priority
 	}
 
 	// Validate or create category
-	category := models.Category(strings.ToLower(addCategory))
-
-	var task *models.Task
-	var err error
+	if spec.Category == "" || cmd.Flags().Changed("category") {
+		spec.Category = models.Category(strings.ToLower(addCategory))
+	}
 
-	if todoClient != nil {
-		// Use the client to add the task
-		task, err = todoClient.AddTask(
-			title,
-			addDescription,
-			priority,
-			category,
-			dueDate,
-			reminderAt,
-		)
-	} else {
-		// Use the app directly (legacy mode)
-		err = todoApp.AddTask(
-			title,
-			addDescription,
-			priority,
-			category,
-			dueDate,
-			reminderAt,
-		)
-		if err == nil {
-			// Get the task that was just added (this is a bit of a hack)
-			tasks, _ := todoApp.GetAllTasks()
-			if len(tasks) > 0 {
-				task = tasks[len(tasks)-1]
-			}
-		}
+	for _, tag := range addTags {
+		spec.Tags = append(spec.Tags, strings.ToLower(tag))
 	}
 
-	if err != nil {
-		return fmt.Errorf("failed to add task: %w", err)
+	if addContext != "" {
+		spec.Context = strings.ToLower(strings.TrimPrefix(addContext, "@"))
 	}
 
-	ui.PrintSuccess("Task added successfully!")
-	if task != nil {
-		fmt.Println(task.String())
+	if addEstimate != "" {
+		estimate, err := quickadd.ParseEstimate(addEstimate)
+		if err != nil {
+			return err
+		}
+		spec.Estimate = estimate
 	}
-	return nil
-}
 
-// parseDateTime parses a date or date-time string
-func parseDateTime(input string) (time.Time, error) {
-	// Handle empty input
-	if input == "" {
-		return time.Time{}, fmt.Errorf("empty date string")
+	if addEnergy != "" {
+		energy, err := models.ParseEnergy(addEnergy)
+		if err != nil {
+			return err
+		}
+		spec.Energy = energy
 	}
 
-	// Try parsing as date-time
-	t, err := time.Parse("2006-01-02 15:04", input)
-	if err == nil {
-		return t, nil
+	spec.BlockedBy = addBlockedBy
+	spec.Project = addProject
+
+	// Show what was understood from the inline syntax before saving
+	if parsedInline {
+		ui.PrintInfo("Parsed:")
+		ui.PrintTaskPreview(spec)
 	}
 
-	// Try parsing as date
-	t, err = time.Parse("2006-01-02", input)
-	if err == nil {
-		// Set time to end of day
-		return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, time.Local), nil
+	// Parse repeat rule if provided
+	if addRepeat != "" {
+		recurrence, err := models.ParseRecurrence(addRepeat)
+		if err != nil {
+			return models.Invalidf("invalid repeat rule: %w", err)
+		}
+		spec.Recurrence = recurrence
 	}
 
-	// Try parsing as relative time
-	return parseRelativeTime(input)
-}
+	var task *models.Task
+	var err error
+
+	if addParent != "" {
+		task, err = todoService.AddSubtask(addParent, spec)
+	} else {
+		task, err = todoService.AddTask(spec)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add task: %w", err)
+	}
 
-// parseRelativeTime parses relative time expressions like "today", "tomorrow", "next week"
-func parseRelativeTime(input string) (time.Time, error) {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, time.Local)
-
-	switch strings.ToLower(input) {
-	case "today":
-		return today, nil
-	case "tomorrow":
-		return today.AddDate(0, 0, 1), nil
-	case "next week":
-		return today.AddDate(0, 0, 7), nil
-	case "next month":
-		return today.AddDate(0, 1, 0), nil
-	default:
-		return time.Time{}, fmt.Errorf("unknown date format: %s (use YYYY-MM-DD or 'today', 'tomorrow', 'next week')", input)
+	if isStructured() {
+		return printTaskOutput(task)
 	}
+
+	ui.PrintSuccess("Task added successfully!")
+	fmt.Println(task.String())
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/output"
 	"github.com/user/todolist/internal/ui"
 )
 
@@ -17,11 +18,21 @@ var (
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if listBackups {
 				// List backups
-				backups, err := todoApp.ListBackups()
+				backups, err := todoService.ListBackups()
 				if err != nil {
 					return fmt.Errorf("failed to list backups: %w", err)
 				}
 
+				if isStructured() {
+					data := make([]output.Backup, len(backups))
+					table := output.Table{Columns: []string{"path"}}
+					for i, backup := range backups {
+						data[i] = output.Backup{Path: backup}
+						table.Add(backup)
+					}
+					return printOutput(output.KindBackups, data, table)
+				}
+
 				if len(backups) == 0 {
 					ui.PrintInfo("No backups found.")
 					return nil
@@ -35,11 +46,17 @@ var (
 			}
 
 			// Create backup
-			backupFile, err := todoApp.BackupTasks()
+			backupFile, err := todoService.BackupTasks()
 			if err != nil {
 				return fmt.Errorf("failed to backup tasks: %w", err)
 			}
 
+			if isStructured() {
+				table := output.Table{Columns: []string{"path"}}
+				table.Add(backupFile)
+				return printOutput(output.KindBackup, output.Backup{Path: backupFile}, table)
+			}
+
 			ui.PrintSuccess("Tasks backed up to: %s", backupFile)
 			return nil
 		},
@@ -48,4 +65,5 @@ var (
 
 func init() {
 	backupCmd.Flags().BoolVarP(&listBackups, "list", "l", false, "List available backups")
+	structured(backupCmd)
 }
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	blockCmd = &cobra.Command{
+		Use:   "block [task_id] [blocked_by_id]",
+		Short: "Mark a task as blocked by another task",
+		Long: `Mark a task as blocked by another task: it cannot start until the other task
+is completed. Blocked tasks are left out of focus mode, and they are unblocked
+automatically when the task they wait for is completed.`,
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task, err := todoService.BlockTask(args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("failed to block task: %w", err)
+			}
+
+			if isStructured() {
+				return printTaskOutput(task)
+			}
+
+			blocker, err := todoService.GetTask(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to get task: %w", err)
+			}
+
+			ui.PrintSuccess("'%s' is now blocked by '%s'.", task.Title, blocker.Title)
+			return nil
+		},
+		Example: `  todolist block 1741359296120413000 1741359296120400000`,
+	}
+
+	unblockCmd = &cobra.Command{
+		Use:   "unblock [task_id] [blocked_by_id]",
+		Short: "Remove a blocker from a task",
+		Long: `Remove a "blocked by" link from a task. Without a blocker ID, all of the
+task's blockers are removed.`,
+		Args:          cobra.RangeArgs(1, 2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			blockerID := ""
+			if len(args) == 2 {
+				blockerID = args[1]
+			}
+
+			task, err := todoService.UnblockTask(args[0], blockerID)
+			if err != nil {
+				return fmt.Errorf("failed to unblock task: %w", err)
+			}
+
+			if isStructured() {
+				return printTaskOutput(task)
+			}
+
+			if len(task.BlockedBy) == 0 {
+				ui.PrintSuccess("'%s' is no longer blocked.", task.Title)
+			} else {
+				ui.PrintSuccess("Blocker removed; '%s' still waits for %d task(s).", task.Title, len(task.BlockedBy))
+			}
+			return nil
+		},
+		Example: `  todolist unblock 1741359296120413000 1741359296120400000
+  todolist unblock 1741359296120413000`,
+	}
+)
+
+func init() {
+	structured(blockCmd)
+	structured(unblockCmd)
+}
@@ -1,42 +1,318 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/client"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/protocol"
+	"github.com/user/todolist/internal/quickadd"
+	"github.com/user/todolist/internal/storage"
 	"github.com/user/todolist/internal/ui"
 )
 
+// pendingDumpFile holds brain dump lines that could not be sent to the server
+const pendingDumpFile = "braindump-pending.jsonl"
+
 var brainDumpCmd = &cobra.Command{
 	Use:   "dump",
 	Short: "Enter brain dump mode",
-	Long:  `Enter brain dump mode to quickly add multiple tasks without interruption.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Set up signal handling for graceful exit with Ctrl+C
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-
-		go func() {
-			<-sigCh
-			fmt.Println("\nBrain dump mode interrupted. Tasks entered so far have been saved.")
-			os.Exit(0)
-		}()
+	Long: `Enter brain dump mode to quickly add multiple tasks without interruption.
 
+Every line is saved as soon as it is entered. If the connection to the server
+drops, lines are kept and sent once it is back; anything that still cannot be
+sent when you finish is stored locally and sent the next time you run dump.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		ui.PrintInfo("🧠 BRAIN DUMP MODE")
 		ui.PrintInfo("Quickly add tasks without interruption. Enter one task per line.")
+		ui.PrintInfo("Add metadata inline if you like: %s", quickadd.Syntax)
 		ui.PrintInfo("Leave a line empty when you're done.")
 		ui.PrintInfo("Press Ctrl+C at any time to exit and save tasks entered so far.")
-		fmt.Println()
+		fmt.Fprintln(ui.Messages)
 
-		err := todoApp.BrainDump()
-		if err != nil {
+		dump := &brainDump{pendingFile: filepath.Join(localConfig().DataDir, pendingDumpFile)}
+		if err := dump.loadPending(); err != nil {
+			return err
+		}
+
+		if err := dump.capture(); err != nil {
 			return fmt.Errorf("brain dump mode error: %w", err)
 		}
 
-		return nil
+		return dump.finish()
 	},
-	Example: `  todolist dump`,
+	Example: `  todolist dump
+  todolist dump -o json < ideas.txt`,
+}
+
+func init() {
+	structured(brainDumpCmd)
+}
+
+// brainDump collects brain dump lines and sends them to the task service,
+// buffering them while the server cannot be reached
+type brainDump struct {
+	pendingFile string
+	pending     []protocol.AddTaskRequest
+	stored      int // leading pending lines that are in the pending file
+	saved       []*models.Task
+	skipped     int
+	offline     bool
+}
+
+// capture reads lines until an empty line, EOF or Ctrl+C
+func (d *brainDump) capture() error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		readErr <- scanner.Err()
+		close(lines)
+	}()
+
+	parser := dateParser()
+	for {
+		fmt.Fprint(ui.Messages, "> ")
+
+		var line string
+		var ok bool
+		select {
+		case <-sigCh:
+			fmt.Fprintln(ui.Messages, "\nBrain dump mode interrupted.")
+			return nil
+		case line, ok = <-lines:
+		}
+		if !ok {
+			fmt.Fprintln(ui.Messages)
+			return <-readErr
+		}
+
+		title := strings.TrimSpace(line)
+		if title == "" {
+			return nil
+		}
+
+		// Handle special commands
+		if title == "q" || title == "quit" || title == "exit" {
+			ui.PrintInfo("Exiting brain dump mode")
+			return nil
+		}
+
+		if title == "help" || title == "--help" || title == "-h" {
+			ui.PrintInfo("Brain Dump Mode Help:")
+			ui.PrintInfo("- Enter task titles one per line")
+			ui.PrintInfo("- Add metadata inline: %s", quickadd.Syntax)
+			ui.PrintInfo("- Leave a line empty to finish")
+			ui.PrintInfo("- Type 'q', 'quit', or 'exit' to exit")
+			ui.PrintInfo("- Press Ctrl+C to exit at any time")
+			continue
+		}
+
+		spec, err := quickadd.Parse(title, parser)
+		if err != nil {
+			ui.PrintWarning("Skipped: %v", err)
+			d.skipped++
+			continue
+		}
+		if spec.Priority == "" {
+			spec.Priority = models.PriorityMedium
+		}
+		if spec.Category == "" {
+			spec.Category = models.Category("inbox")
+		}
+
+		ui.PrintTaskPreview(spec)
+		d.pending = append(d.pending, protocol.NewAddTaskRequest(spec))
+		if err := d.flush(); err != nil {
+			return err
+		}
+		if d.offline {
+			ui.PrintInfo("(buffered, %d line(s) waiting for the server)", len(d.pending))
+		}
+	}
+}
+
+// flush sends all pending lines in one batch. Connection problems switch the
+// dump to offline mode instead of failing; each later flush makes one attempt
+// to reconnect. Lines are only dropped from the queue once they are saved, or
+// when the service rejects them as invalid.
+func (d *brainDump) flush() error {
+	if len(d.pending) == 0 {
+		return nil
+	}
+
+	if d.offline {
+		todoClient, ok := todoService.(*client.Client)
+		if !ok || todoClient.Reconnect() != nil {
+			return nil
+		}
+	}
+
+	specs := make([]models.TaskSpec, len(d.pending))
+	for i, req := range d.pending {
+		specs[i] = req.Spec()
+	}
+
+	tasks, err := todoService.AddTasks(specs)
+	var connErr *client.ConnectionError
+	if errors.As(err, &connErr) {
+		if !d.offline {
+			ui.PrintWarning("Lost connection to the server. Keep going, your lines are buffered.")
+		}
+		d.offline = true
+		return nil
+	}
+
+	done := len(tasks)
+	if err != nil {
+		ui.PrintWarning("Could not save %d line(s): %v", len(d.pending)-len(tasks), err)
+		// The whole batch is checked before anything is added, so invalid
+		// lines would be rejected again; other failures are retried later
+		if errors.As(err, new(*models.ValidationError)) {
+			d.skipped += len(d.pending) - len(tasks)
+			done = len(d.pending)
+		}
+	}
+	d.saved = append(d.saved, tasks...)
+	d.pending = d.pending[done:]
+
+	// Keep the pending file in step, so a crash does not lose or resend
+	// lines from an earlier dump
+	if d.stored > 0 {
+		d.stored -= done
+		if d.stored < 0 {
+			d.stored = 0
+		}
+		if err := d.storePending(d.pending[:d.stored]); err != nil {
+			return err
+		}
+	}
+
+	if d.offline {
+		ui.PrintSuccess("Reconnected to the server, saved %d buffered line(s).", len(tasks))
+		d.offline = false
+	}
+	return nil
+}
+
+// loadPending picks up lines left over from an earlier brain dump. The file
+// is only rewritten as those lines are sent.
+func (d *brainDump) loadPending() error {
+	data, err := os.ReadFile(d.pendingFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read buffered brain dump: %w", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var req protocol.AddTaskRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			return fmt.Errorf("failed to read buffered brain dump %s: %w", d.pendingFile, err)
+		}
+		d.pending = append(d.pending, req)
+	}
+	d.stored = len(d.pending)
+
+	if len(d.pending) > 0 {
+		ui.PrintInfo("Sending %d line(s) left over from an earlier brain dump.", len(d.pending))
+	}
+	return d.flush()
+}
+
+// storePending replaces the pending file with the given lines, removing it
+// when there are none
+func (d *brainDump) storePending(pending []protocol.AddTaskRequest) error {
+	if len(pending) == 0 {
+		if err := os.Remove(d.pendingFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove buffered brain dump: %w", err)
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	for _, req := range pending {
+		line, err := json.Marshal(req)
+		if err != nil {
+			return fmt.Errorf("failed to buffer brain dump: %w", err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.pendingFile), 0755); err != nil {
+		return fmt.Errorf("failed to buffer brain dump: %w", err)
+	}
+	if err := storage.WriteFileAtomic(d.pendingFile, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to buffer brain dump: %w", err)
+	}
+	return nil
+}
+
+// finish sends what is left, stores anything that still cannot be sent and
+// prints a summary
+func (d *brainDump) finish() error {
+	if err := d.flush(); err != nil {
+		return err
+	}
+	if len(d.pending) > 0 {
+		if err := d.storePending(d.pending); err != nil {
+			return err
+		}
+	}
+
+	if isStructured() {
+		data := output.BrainDump{Added: output.NewTasks(d.saved, projectNames()), Skipped: d.skipped, Pending: len(d.pending)}
+		return printOutput(output.KindBrainDump, data, output.TaskTable(data.Added))
+	}
+
+	fmt.Println()
+	ui.PrintSuccess("Added %d tasks.", len(d.saved))
+
+	counts := make(map[models.Category]int)
+	for _, task := range d.saved {
+		counts[task.Category]++
+	}
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, string(category))
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		fmt.Printf("  %-12s %d\n", category, counts[models.Category(category)])
+	}
+
+	if d.skipped > 0 {
+		ui.PrintWarning("Skipped %d line(s).", d.skipped)
+	}
+
+	if len(d.pending) == 0 {
+		return nil
+	}
+	ui.PrintWarning("%d line(s) could not be sent to the server and were saved to %s.", len(d.pending), d.pendingFile)
+	ui.PrintWarning("They will be sent the next time you run 'todolist dump'.")
+	return nil
 }
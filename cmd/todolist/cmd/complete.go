@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
 	"github.com/user/todolist/internal/storage"
 	"github.com/user/todolist/internal/ui"
 )
@@ -12,40 +16,129 @@ import (
 var completeCmd = &cobra.Command{
 	Use:   "complete [task_id]",
 	Short: "Mark a task as completed",
-	Long:  `Mark a task as completed by its ID.`,
+	Long:  `Mark a task as completed by its ID. Completing a task also completes all of its subtasks and unblocks the tasks that were waiting for it.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		taskID := args[0]
 
 		// Validate task ID format
 		if strings.Contains(taskID, "[") || strings.Contains(taskID, "]") {
-			return fmt.Errorf("invalid task ID format: %s (do not include square brackets)", taskID)
+			return models.Invalidf("invalid task ID format: %s (do not include square brackets)", taskID)
 		}
 
 		// Get the task first to check if it exists
-		task, err := todoApp.GetTask(taskID)
+		task, err := todoService.GetTask(taskID)
 		if err != nil {
 			// Provide a more helpful error message for task not found
-			if _, ok := err.(storage.ErrTaskNotFound); ok {
-				return fmt.Errorf("task not found with ID: %s (use 'todolist list' to see all tasks)", taskID)
+			if errors.As(err, new(storage.ErrTaskNotFound)) {
+				return fmt.Errorf("%w (use 'todolist list' to see all tasks)", storage.ErrTaskNotFound{ID: taskID})
 			}
 			return fmt.Errorf("failed to get task: %w", err)
 		}
 
 		// Check if task is already completed
 		if task.Completed {
+			if isStructured() {
+				return printCompletionOutput(task, true, nil, nil)
+			}
 			ui.PrintInfo("Task '%s' is already marked as completed", task.Title)
 			return nil
 		}
 
+		// Work out which tasks this unblocks before their blockers are removed
+		unblocked := unblockedBy(task)
+
 		// Mark as completed
-		err = todoApp.CompleteTask(taskID)
+		err = todoService.CompleteTask(taskID)
 		if err != nil {
 			return fmt.Errorf("failed to complete task: %w", err)
 		}
 
+		next := models.NextOccurrence(task, ui.Clock.Now())
+		if isStructured() {
+			completed, err := todoService.GetTask(taskID)
+			if err != nil {
+				return fmt.Errorf("failed to get task: %w", err)
+			}
+			// The unblocked tasks no longer list the completed one as a blocker
+			for i, dependent := range unblocked {
+				if unblocked[i], err = todoService.GetTask(dependent.ID); err != nil {
+					return fmt.Errorf("failed to get task: %w", err)
+				}
+			}
+			return printCompletionOutput(completed, false, next, unblocked)
+		}
+
 		ui.PrintSuccess("Task completed: %s", task.Title)
+		if next != nil {
+			ui.PrintInfo("Next occurrence (%s): %s", task.Recurrence, nextOccurrenceTime(next).Format("2006-01-02 15:04"))
+		}
+		for _, dependent := range unblocked {
+			ui.PrintInfo("Unblocked: %s (ID: %s)", dependent.Title, dependent.ID)
+		}
 		return nil
 	},
 	Example: `  todolist complete 1741359296120413000`,
 }
+
+func init() {
+	structured(completeCmd)
+}
+
+// printCompletionOutput writes a completed task in a structured --output
+// format. next is the next occurrence of a recurring task, if any.
+func printCompletionOutput(task *models.Task, alreadyCompleted bool, next *models.Task, unblocked []*models.Task) error {
+	names := projectNames()
+	data := output.Completion{
+		AlreadyCompleted: alreadyCompleted,
+		Unblocked:        output.NewTasks(unblocked, names),
+		Task:             output.NewTask(task, names),
+	}
+	if next != nil {
+		data.NextOccurrence = output.FormatTime(nextOccurrenceTime(next))
+	}
+	return printOutput(output.KindCompletion, data, output.TaskTable([]output.Task{data.Task}))
+}
+
+// nextOccurrenceTime returns when the next occurrence of a recurring task is
+// due, or its reminder time if it has no due date
+func nextOccurrenceTime(next *models.Task) time.Time {
+	if next.DueDate.IsZero() {
+		return next.ReminderAt
+	}
+	return next.DueDate
+}
+
+// unblockedBy returns the open tasks that only wait for the given task or its
+// open subtasks, and so can start once it is completed
+func unblockedBy(task *models.Task) []*models.Task {
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return nil
+	}
+
+	completing := map[string]bool{task.ID: true}
+	for _, subtask := range models.Descendants(tasks, task.ID) {
+		completing[subtask.ID] = true
+	}
+
+	deps := models.NewDependencies(tasks)
+	var unblocked []*models.Task
+	for _, candidate := range tasks {
+		if candidate.Completed || completing[candidate.ID] {
+			continue
+		}
+
+		blockers := deps.Blockers(candidate)
+		waitsOnlyForTask := len(blockers) > 0
+		for _, blocker := range blockers {
+			if !completing[blocker.ID] {
+				waitsOnlyForTask = false
+			}
+		}
+		if waitsOnlyForTask {
+			unblocked = append(unblocked, candidate)
+		}
+	}
+	return unblocked
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
 	"github.com/user/todolist/internal/storage"
 	"github.com/user/todolist/internal/ui"
 )
@@ -15,44 +19,60 @@ var (
 	deleteCmd = &cobra.Command{
 		Use:   "delete [task_id]",
 		Short: "Delete a task",
-		Long:  `Delete a task by its ID.`,
+		Long:  `Delete a task by its ID. Any subtasks of the task are deleted as well.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			taskID := args[0]
 
 			// Validate task ID format
 			if strings.Contains(taskID, "[") || strings.Contains(taskID, "]") {
-				return fmt.Errorf("invalid task ID format: %s (do not include square brackets)", taskID)
+				return models.Invalidf("invalid task ID format: %s (do not include square brackets)", taskID)
 			}
 
 			// Get the task first to check if it exists and to show the title in the success message
-			task, err := todoApp.GetTask(taskID)
+			task, err := todoService.GetTask(taskID)
 			if err != nil {
 				// Provide a more helpful error message for task not found
-				if _, ok := err.(storage.ErrTaskNotFound); ok {
-					return fmt.Errorf("task not found with ID: %s (use 'todolist list' to see all tasks)", taskID)
+				if errors.As(err, new(storage.ErrTaskNotFound)) {
+					return fmt.Errorf("%w (use 'todolist list' to see all tasks)", storage.ErrTaskNotFound{ID: taskID})
 				}
 				return fmt.Errorf("failed to get task: %w", err)
 			}
 
+			subtasks := 0
+			if tasks, err := todoService.GetAllTasks(); err == nil {
+				subtasks = len(models.Descendants(tasks, taskID))
+			}
+
 			// Confirm deletion unless --force flag is used
 			if !deleteForce {
+				if subtasks > 0 {
+					ui.PrintWarning("This will also delete %d subtask(s).", subtasks)
+				}
+
 				ui.PrintWarning("Are you sure you want to delete task: %s? (y/N): ", task.Title)
 				var confirm string
 				fmt.Scanln(&confirm)
 
 				if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
 					ui.PrintInfo("Task deletion cancelled")
+					if isStructured() {
+						return printDeletionOutput(task, false, subtasks)
+					}
 					return nil
 				}
 			}
 
 			// Delete the task
-			err = todoApp.DeleteTask(taskID)
+			err = todoService.DeleteTask(taskID)
 			if err != nil {
 				return fmt.Errorf("failed to delete task: %w", err)
 			}
 
+			if isStructured() {
+				return printDeletionOutput(task, true, subtasks)
+			}
+
 			ui.PrintSuccess("Task deleted: %s", task.Title)
 			return nil
 		},
@@ -63,4 +83,14 @@ var (
 
 func init() {
 	deleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "Delete without confirmation")
+	structured(deleteCmd)
+}
+
+// printDeletionOutput writes a deleted task in a structured --output format.
+// deleted is false when the deletion was cancelled.
+func printDeletionOutput(task *models.Task, deleted bool, subtasks int) error {
+	data := output.Deletion{Deleted: deleted, Subtasks: subtasks, Task: output.NewTask(task, projectNames())}
+	table := output.Table{Columns: append([]string{"deleted", "subtasks"}, output.TaskColumns...)}
+	table.Add(append([]string{strconv.FormatBool(deleted), strconv.Itoa(subtasks)}, output.TaskRow(data.Task)...)...)
+	return printOutput(output.KindDeletion, data, table)
 }
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/quickadd"
+	"github.com/user/todolist/internal/storage"
+	"github.com/user/todolist/internal/ui"
+)
+
+// editFields lists the task fields that can be edited, in the order they are
+// shown in the editor. The description is edited as the document body.
+var editFields = []string{"title", "priority", "category", "project", "due", "reminder", "repeat", "tags", "context", "estimate", "energy"}
+
+var (
+	editValues    = make(map[string]*string)
+	editUseEditor bool
+
+	editCmd = &cobra.Command{
+		Use:   "edit [task_id]",
+		Short: "Edit an existing task",
+		Long: `Edit the fields of an existing task.
+
+Only the fields given as flags are changed, so edits made by someone else to
+other fields in the meantime are kept. Use "none" to clear a date, repeat rule,
+project, context, estimate or energy level.
+
+Without any field flags (or with --editor) the task is opened in $VISUAL or
+$EDITOR as a Markdown document with a front-matter header; only the lines you
+change are saved.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runEditCmd,
+		Example: `  todolist edit 1741359296120413000 --title "Call dentist about filling"
+  todolist edit 1741359296120413000 --priority high --due "friday 5pm"
+  todolist edit 1741359296120413000 --reminder none --repeat none
+  todolist edit 1741359296120413000 --tags work,urgent
+  todolist edit 1741359296120413000`,
+	}
+)
+
+func init() {
+	usage := map[string]string{
+		"title":       "New task title",
+		"description": "New task description",
+		"priority":    "New priority (low, medium, high)",
+		"category":    "New category",
+		"project":     "Name or ID of the new project, or none",
+		"due":         "New due date (YYYY-MM-DD, tomorrow 3pm, friday, ...) or none",
+		"reminder":    "New reminder time (in 2h, tomorrow morning, ...) or none",
+		"repeat":      "New repeat rule (daily, 'every mon,fri', ...) or none",
+		"tags":        "Comma-separated tags replacing the current ones",
+		"context":     "New context (e.g. phone) or none",
+		"estimate":    "New time estimate (e.g. 15m, 1h30m) or none",
+		"energy":      "New energy level (low, medium, high) or none",
+	}
+
+	for _, name := range append([]string{"description"}, editFields...) {
+		value := new(string)
+		editValues[name] = value
+		editCmd.Flags().StringVar(value, name, "", usage[name])
+	}
+	editCmd.Flags().BoolVarP(&editUseEditor, "editor", "e", false, "Open the task in $EDITOR even when field flags are given")
+	structured(editCmd)
+}
+
+func runEditCmd(cmd *cobra.Command, args []string) error {
+	taskID := args[0]
+
+	task, err := todoService.GetTask(taskID)
+	if err != nil {
+		if errors.As(err, new(storage.ErrTaskNotFound)) {
+			return fmt.Errorf("%w (use 'todolist list' to see all tasks)", storage.ErrTaskNotFound{ID: taskID})
+		}
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	var patch models.TaskPatch
+	for name, value := range editValues {
+		if !cmd.Flags().Changed(name) {
+			continue
+		}
+		if err := setPatchField(&patch, name, *value); err != nil {
+			return err
+		}
+	}
+
+	if editUseEditor || patch.IsEmpty() {
+		if err := editInEditor(task, &patch); err != nil {
+			return err
+		}
+	}
+
+	if patch.IsEmpty() {
+		ui.PrintInfo("No changes.")
+		if isStructured() {
+			return printTaskOutput(task)
+		}
+		return nil
+	}
+
+	updated, err := todoService.PatchTask(taskID, patch)
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	if isStructured() {
+		return printTaskOutput(updated)
+	}
+
+	ui.PrintSuccess("Task updated successfully!")
+	ui.PrintTask(updated, taskDependencies())
+	return nil
+}
+
+// setPatchField parses the value of an editable field into the patch
+func setPatchField(patch *models.TaskPatch, name, value string) error {
+	value = strings.TrimSpace(value)
+	unset := value == "" || strings.EqualFold(value, "none")
+
+	switch name {
+	case "title":
+		patch.Title = &value
+
+	case "description":
+		patch.Description = &value
+
+	case "priority":
+		priority := models.Priority(strings.ToLower(value))
+		if priority != models.PriorityLow && priority != models.PriorityMedium && priority != models.PriorityHigh {
+			return models.Invalidf("invalid priority: %s (must be low, medium, or high)", value)
+		}
+		patch.Priority = &priority
+
+	case "category":
+		if value == "" {
+			return models.Invalidf("category cannot be empty")
+		}
+		category := models.Category(strings.ToLower(value))
+		patch.Category = &category
+
+	case "project":
+		project := ""
+		if !unset {
+			project = value
+		}
+		patch.Project = &project
+
+	case "due", "reminder":
+		var when time.Time
+		if !unset {
+			var err error
+			when, err = dateParser().Parse(value)
+			if err != nil {
+				return models.Invalidf("invalid %s date format: %w", name, err)
+			}
+		}
+		if name == "due" {
+			patch.DueDate = &when
+		} else {
+			patch.ReminderAt = &when
+		}
+
+	case "repeat":
+		if unset {
+			patch.ClearRecurrence = true
+			return nil
+		}
+		recurrence, err := models.ParseRecurrence(value)
+		if err != nil {
+			return models.Invalidf("invalid repeat rule: %w", err)
+		}
+		patch.Recurrence = recurrence
+
+	case "tags":
+		tags := []string{}
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+			if tag != "" && tag != "none" {
+				tags = append(tags, tag)
+			}
+		}
+		patch.Tags = &tags
+
+	case "context":
+		context := ""
+		if !unset {
+			context = strings.ToLower(strings.TrimPrefix(value, "@"))
+		}
+		patch.Context = &context
+
+	case "estimate":
+		var estimate time.Duration
+		if !unset {
+			var err error
+			estimate, err = quickadd.ParseEstimate(value)
+			if err != nil {
+				return err
+			}
+		}
+		patch.Estimate = &estimate
+
+	case "energy":
+		var energy models.Energy
+		if !unset {
+			var err error
+			energy, err = models.ParseEnergy(value)
+			if err != nil {
+				return err
+			}
+		}
+		patch.Energy = &energy
+
+	default:
+		return fmt.Errorf("unknown field: %s", name)
+	}
+
+	return nil
+}
+
+// taskFieldValues returns the editable fields of a task in their text form
+func taskFieldValues(task *models.Task) map[string]string {
+	values := map[string]string{
+		"title":    task.Title,
+		"priority": string(task.Priority),
+		"category": string(task.Category),
+		"tags":     strings.Join(task.Tags, ", "),
+		"context":  task.Context,
+		"energy":   string(task.Energy),
+	}
+	if task.Project != "" {
+		values["project"] = task.Project
+		if name, ok := projectNames()[task.Project]; ok {
+			values["project"] = name
+		}
+	}
+	if !task.DueDate.IsZero() {
+		values["due"] = task.DueDate.Format("2006-01-02 15:04")
+	}
+	if !task.ReminderAt.IsZero() {
+		values["reminder"] = task.ReminderAt.Format("2006-01-02 15:04")
+	}
+	if task.Recurrence != nil {
+		values["repeat"] = task.Recurrence.Expression()
+	}
+	if task.Estimate > 0 {
+		values["estimate"] = ui.FormatEstimate(task.Estimate)
+	}
+	return values
+}
+
+// renderTaskDocument renders a task as Markdown with a front-matter header
+func renderTaskDocument(task *models.Task) string {
+	values := taskFieldValues(task)
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, name := range editFields {
+		b.WriteString(strings.TrimSpace(name + ": " + values[name]))
+		b.WriteString("\n")
+	}
+	b.WriteString("---\n")
+	b.WriteString(task.Description)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// parseTaskDocument splits an edited document into its header fields and body
+func parseTaskDocument(doc string) (map[string]string, string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(doc))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+		return nil, "", models.Invalidf("document must start with a '---' header line")
+	}
+
+	values := make(map[string]string)
+	closed := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "---" {
+			closed = true
+			break
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", models.Invalidf("invalid header line: %q (expected 'field: value')", line)
+		}
+		values[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	if !closed {
+		return nil, "", fmt.Errorf("header is missing its closing '---' line")
+	}
+
+	var body []string
+	for scanner.Scan() {
+		body = append(body, scanner.Text())
+	}
+	return values, strings.TrimSpace(strings.Join(body, "\n")), scanner.Err()
+}
+
+// editInEditor opens the task in the user's editor and adds every field that
+// was changed to the patch
+func editInEditor(task *models.Task, patch *models.TaskPatch) error {
+	file, err := os.CreateTemp("", "todolist-"+task.ID+"-*.md")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	path := file.Name()
+
+	_, err = file.WriteString(renderTaskDocument(task))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := runEditor(path); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to read edited task: %w", err)
+	}
+
+	// Keep the file around on errors so the edits are not lost
+	values, body, err := parseTaskDocument(string(data))
+	if err != nil {
+		return fmt.Errorf("%w (your edits are saved in %s)", err, path)
+	}
+
+	original := taskFieldValues(task)
+	for name, value := range values {
+		if !isEditField(name) {
+			return fmt.Errorf("unknown field: %s (your edits are saved in %s)", name, path)
+		}
+		if value == original[name] {
+			continue
+		}
+		if err := setPatchField(patch, name, value); err != nil {
+			return fmt.Errorf("%w (your edits are saved in %s)", err, path)
+		}
+	}
+	if body != strings.TrimSpace(task.Description) {
+		patch.Description = &body
+	}
+
+	os.Remove(path)
+	return nil
+}
+
+func isEditField(name string) bool {
+	for _, field := range editFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runEditor opens a file in $VISUAL or $EDITOR, falling back to vi
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor variable may contain arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	editorCmd := exec.Command(parts[0], append(parts[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %q: %w", editor, err)
+	}
+	return nil
+}
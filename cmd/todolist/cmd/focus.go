@@ -2,36 +2,337 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/quickadd"
 	"github.com/user/todolist/internal/ui"
 )
 
-var focusCmd = &cobra.Command{
-	Use:   "focus",
-	Short: "Enter focus mode",
-	Long:  `Enter focus mode to get a suggestion for the next task to work on based on priority and urgency.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		task, err := todoApp.FocusMode()
+var (
+	focusExplain bool
+	focusTop     int
+	focusEnergy  string
+	focusTime    string
+	focusTags    tagFilterFlags
+	focusProject string
+
+	focusSkipReason string
+	focusSkipFor    time.Duration
+	focusSnoozeFor  time.Duration
+	focusUntil      string
+	focusClear      bool
+
+	focusCmd = &cobra.Command{
+		Use:   "focus",
+		Short: "Enter focus mode",
+		Long: `Enter focus mode to get a suggestion for the next task to work on based on priority and urgency.
+
+Each task gets points for its priority, how soon it is due, whether its
+reminder is due and how long it has been waiting. The weights can be changed
+in the "focus" section of config.json in the data directory.
+
+Tell focus mode how you are doing with --energy and --time: tasks that need
+more energy or time than you have are left out, and tasks that match your
+energy level or fit in your time are preferred.
+
+Not the right task? Skip it to see the next one, or snooze it until later.`,
+		Args: cobra.NoArgs,
+		RunE: runFocusCmd,
+		Example: `  todolist focus
+  todolist focus --explain
+  todolist focus --top 5
+  todolist focus --energy low --time 15m
+  todolist focus --tag work --not-tag someday
+  todolist focus skip 1741359296120413000 --reason "too tired"
+  todolist focus snooze 1741359296120413000 --until monday`,
+	}
+
+	focusSkipCmd = &cobra.Command{
+		Use:   "skip [task_id]",
+		Short: "Skip a focus suggestion and show the next one",
+		Long: `Skip a focus suggestion and show the next one. The skipped task is left out of
+focus mode for a while (an hour by default). Skips are counted, so tasks you
+keep avoiding show up in 'todolist stats skips'.`,
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runFocusSkip,
+	}
+
+	focusSnoozeCmd = &cobra.Command{
+		Use:           "snooze [task_id]",
+		Short:         "Keep a task out of focus mode until later",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runFocusSnooze,
+		Example: `  todolist focus snooze 1741359296120413000 --until "next monday"
+  todolist focus snooze 1741359296120413000 --for 3h
+  todolist focus snooze 1741359296120413000 --clear`,
+	}
+)
+
+func init() {
+	focusCmd.Flags().BoolVarP(&focusExplain, "explain", "e", false, "Show how each factor contributes to the score")
+	focusCmd.Flags().IntVarP(&focusTop, "top", "n", 1, "Show the N best suggestions as a ranked queue")
+	focusCmd.Flags().StringVar(&focusEnergy, "energy", "", "Your current energy level (low, medium, high)")
+	focusCmd.Flags().StringVar(&focusTime, "time", "", "Time you have available (e.g. 15m, 1h)")
+	focusCmd.Flags().StringVar(&focusProject, "project", "", "Only suggest tasks from this project (name or ID)")
+	focusTags.register(focusCmd)
+
+	focusSkipCmd.Flags().StringVarP(&focusSkipReason, "reason", "r", "", "Why you are skipping the task")
+	focusSkipCmd.Flags().DurationVar(&focusSkipFor, "for", time.Hour, "How long to leave the task out of focus mode (0 to keep suggesting it)")
+	focusSkipCmd.Flags().StringVar(&focusUntil, "until", "", "Leave the task out until a time (tonight, tomorrow, ...)")
+
+	focusSnoozeCmd.Flags().DurationVar(&focusSnoozeFor, "for", 0, "How long to snooze")
+	focusSnoozeCmd.Flags().StringVar(&focusUntil, "until", "", "Snooze until a time (tomorrow morning, monday, ...)")
+	focusSnoozeCmd.Flags().BoolVar(&focusClear, "clear", false, "End the snooze")
+
+	structured(focusCmd)
+	structured(focusSkipCmd)
+	structured(focusSnoozeCmd)
+
+	focusCmd.AddCommand(focusSkipCmd)
+	focusCmd.AddCommand(focusSnoozeCmd)
+}
+
+func runFocusCmd(cmd *cobra.Command, args []string) error {
+	if focusTop < 1 {
+		return models.Invalidf("--top must be at least 1")
+	}
+
+	options := models.FocusOptions{Limit: focusTop}
+	if focusEnergy != "" {
+		energy, err := models.ParseEnergy(focusEnergy)
 		if err != nil {
-			return fmt.Errorf("failed to enter focus mode: %w", err)
+			return err
 		}
+		options.Energy = energy
+	}
+	if focusTime != "" {
+		available, err := quickadd.ParseEstimate(focusTime)
+		if err != nil {
+			return err
+		}
+		options.Available = available
+	}
+	tags, err := focusTags.filter()
+	if err != nil {
+		return err
+	}
+	options.Tags = tags
+	options.Project = focusProject
+
+	suggestions, err := todoService.FocusMode(options)
+	if err != nil {
+		return fmt.Errorf("failed to enter focus mode: %w", err)
+	}
+
+	if isStructured() {
+		return printFocusOutput(suggestions)
+	}
 
-		if task == nil {
-			ui.PrintInfo("No tasks to focus on. Add some tasks first!")
+	if len(suggestions) == 0 {
+		if options.Energy != "" || options.Available > 0 {
+			ui.PrintInfo("No task fits your energy and time right now. Try without --energy or --time.")
+			return nil
+		}
+		if !options.Tags.IsEmpty() {
+			ui.PrintInfo("No tasks to focus on with these tags.")
+			return nil
+		}
+		if options.Project != "" {
+			ui.PrintInfo("No tasks to focus on in this project.")
 			return nil
 		}
+		ui.PrintInfo("No tasks to focus on. Add some tasks first!")
+		return nil
+	}
 
-		fmt.Println()
-		ui.PrintInfo("🎯 FOCUS MODE")
+	fmt.Println()
+	ui.PrintInfo("🎯 FOCUS MODE")
+
+	if focusTop == 1 {
 		ui.PrintInfo("Here's the task you should focus on next:")
 		fmt.Println()
-		ui.PrintTask(task)
-		fmt.Println()
-		ui.PrintInfo("To start a Pomodoro timer for this task, run:")
-		ui.PrintInfo("  todolist pomodoro %s", task.ID)
+		ui.PrintTask(suggestions[0].Task, taskDependencies())
+		if focusExplain {
+			printScoreFactors(suggestions[0])
+		}
+		printSkipWarning(suggestions[0].Task)
+	} else {
+		ui.PrintInfo("Your next %d tasks, best first:", len(suggestions))
 		fmt.Println()
+		for i, suggestion := range suggestions {
+			fmt.Printf("%d. [%.0f] %s (ID: %s)\n", i+1, suggestion.Score, suggestion.Task.Title, suggestion.Task.ID)
+			if focusExplain {
+				printScoreFactors(suggestion)
+			}
+		}
+	}
 
+	fmt.Println()
+	ui.PrintInfo("To start a Pomodoro timer for this task, run:")
+	ui.PrintInfo("  todolist pomodoro start %s", suggestions[0].Task.ID)
+	ui.PrintInfo("Not now? Run 'todolist focus skip %s' or 'todolist focus snooze %s --until ...'", suggestions[0].Task.ID, suggestions[0].Task.ID)
+	fmt.Println()
+
+	return nil
+}
+
+// printFocusOutput writes focus suggestions in a structured --output format
+func printFocusOutput(suggestions []models.TaskScore) error {
+	names := projectNames()
+	data := make([]output.FocusSuggestion, len(suggestions))
+	table := output.Table{Columns: append([]string{"rank", "score"}, output.TaskColumns...)}
+	for i, suggestion := range suggestions {
+		factors := suggestion.Factors
+		if factors == nil {
+			factors = []models.ScoreFactor{}
+		}
+		data[i] = output.FocusSuggestion{
+			Rank:    i + 1,
+			Score:   suggestion.Score,
+			Factors: factors,
+			Task:    output.NewTask(suggestion.Task, names),
+		}
+		row := []string{strconv.Itoa(i + 1), strconv.FormatFloat(suggestion.Score, 'f', 2, 64)}
+		table.Add(append(row, output.TaskRow(data[i].Task)...)...)
+	}
+	return printOutput(output.KindFocus, data, table)
+}
+
+func runFocusSkip(cmd *cobra.Command, args []string) error {
+	if focusSkipFor < 0 {
+		return models.Invalidf("--for must not be negative")
+	}
+
+	var until time.Time
+	if focusSkipFor > 0 {
+		until = ui.Clock.Now().Add(focusSkipFor)
+	}
+	if focusUntil != "" {
+		var err error
+		until, err = dateParser().Parse(focusUntil)
+		if err != nil {
+			return models.Invalidf("invalid skip time: %w", err)
+		}
+	}
+
+	task, err := todoService.SkipFocusTask(args[0], focusSkipReason, until)
+	if err != nil {
+		return fmt.Errorf("failed to skip task: %w", err)
+	}
+
+	suggestions, err := todoService.FocusMode(models.FocusOptions{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to enter focus mode: %w", err)
+	}
+	var next *models.Task
+	if len(suggestions) > 0 && suggestions[0].Task.ID != task.ID {
+		next = suggestions[0].Task
+	}
+
+	if isStructured() {
+		return printFocusSkipOutput(task, until, next)
+	}
+
+	if until.IsZero() {
+		ui.PrintSuccess("Skipped '%s'.", task.Title)
+	} else {
+		ui.PrintSuccess("Skipped '%s' until %s.", task.Title, until.Format("2006-01-02 15:04"))
+	}
+	printSkipWarning(task)
+
+	if next == nil {
+		ui.PrintInfo("No other tasks to focus on right now.")
 		return nil
-	},
+	}
+
+	fmt.Println()
+	ui.PrintInfo("Next up:")
+	fmt.Println()
+	ui.PrintTask(next, taskDependencies())
+	return nil
+}
+
+// printFocusSkipOutput writes a skipped task and the next suggestion, if
+// any, in a structured --output format
+func printFocusSkipOutput(task *models.Task, until time.Time, next *models.Task) error {
+	names := projectNames()
+	data := output.FocusSkip{
+		Until: output.FormatTime(until),
+		Skips: task.FocusSkipCount(),
+		Task:  output.NewTask(task, names),
+	}
+	if next != nil {
+		nextData := output.NewTask(next, names)
+		data.Next = &nextData
+	}
+
+	table := output.Table{Columns: append([]string{"until", "skips"}, output.TaskColumns...)}
+	table.Add(append([]string{data.Until, strconv.Itoa(data.Skips)}, output.TaskRow(data.Task)...)...)
+	return printOutput(output.KindFocusSkip, data, table)
+}
+
+func runFocusSnooze(cmd *cobra.Command, args []string) error {
+	var until time.Time
+	switch {
+	case focusClear:
+		if focusUntil != "" || focusSnoozeFor != 0 {
+			return models.Invalidf("--clear cannot be combined with --until or --for")
+		}
+	case focusUntil != "":
+		var err error
+		until, err = dateParser().Parse(focusUntil)
+		if err != nil {
+			return models.Invalidf("invalid snooze time: %w", err)
+		}
+	case focusSnoozeFor > 0:
+		until = ui.Clock.Now().Add(focusSnoozeFor)
+	default:
+		return models.Invalidf("give --until, --for or --clear")
+	}
+
+	task, err := todoService.SnoozeFocusTask(args[0], until)
+	if err != nil {
+		return fmt.Errorf("failed to snooze task: %w", err)
+	}
+
+	if isStructured() {
+		data := output.FocusSnooze{Until: output.FormatTime(until), Task: output.NewTask(task, projectNames())}
+		table := output.Table{Columns: append([]string{"until"}, output.TaskColumns...)}
+		table.Add(append([]string{data.Until}, output.TaskRow(data.Task)...)...)
+		return printOutput(output.KindFocusSnooze, data, table)
+	}
+
+	if until.IsZero() {
+		ui.PrintSuccess("'%s' is back in focus mode.", task.Title)
+		return nil
+	}
+	ui.PrintSuccess("'%s' is left out of focus mode until %s.", task.Title, until.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// printSkipWarning nudges towards breaking a task down once it has been
+// skipped a few times
+func printSkipWarning(task *models.Task) {
+	const chronicSkips = 3
+
+	if skips := task.FocusSkipCount(); skips >= chronicSkips {
+		ui.PrintWarning("You have skipped this task %d times. Breaking it into smaller steps may help:", skips)
+		ui.PrintWarning("  todolist add \"first small step\" --parent %s", task.ID)
+	}
+}
+
+// printScoreFactors prints how a focus score adds up
+func printScoreFactors(suggestion models.TaskScore) {
+	for _, factor := range suggestion.Factors {
+		fmt.Printf("   %+7.1f  %s\n", factor.Points, factor.Name)
+	}
+	fmt.Printf("   %7.1f  total score\n", suggestion.Score)
 }
@@ -2,71 +2,170 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/query"
+	"github.com/user/todolist/internal/ui"
 )
 
 var (
 	listCategory string
 	listPriority string
+	listStatus   string
+	listProject  string
 	listAll      bool
 	listVerbose  bool
+	listTree     bool
+	listTags     tagFilterFlags
+	listWhere    string
+	listSort     string
+	listLimit    int
+	listPage     int
 
 	listCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List tasks",
-		Long:  `List tasks with optional filtering by category or priority.`,
-		RunE:  runListCmd,
+		Long: `List tasks with optional filtering by category, priority, status, project or tags.
+
+--where takes a query expression. Terms separated by spaces must all match;
+OR, a leading "-" (or NOT) and parentheses combine them further:
+
+  priority:high              also priority:>=medium, !high
+  due:<friday                due:today, due:<=2025-03-01, due:none
+  created:>"last monday"     closed:today
+  status:open                status:closed, status:waiting, ...
+  tag:someday                +someday, tag:none
+  category:work  #work       project:"website relaunch"  project:none
+  context:phone  @phone      energy:<=medium  estimate:<30m  estimate:none
+  is:overdue                 is:blocked, is:recurring, is:subtask, is:snoozed
+  text~"report"              title~draft, description~invoice, or just a word
+
+Dates are compared by day. Closed tasks are hidden unless the query filters on
+status or --all is given. --sort takes a comma-separated list of due, priority,
+created, closed, title, status, estimate, energy and category; a leading "-"
+reverses the order.`,
+		RunE: runListCmd,
+		Example: `  todolist list --where 'priority:high due:<friday -tag:someday'
+  todolist list --where '(+errand OR @phone) estimate:<=15m'
+  todolist list --where 'status:done closed:>="7 days ago"' --sort -closed
+  todolist list --where 'text~"report"' --sort due,-priority --limit 10 --page 2`,
 	}
 )
 
 func init() {
 	listCmd.Flags().StringVarP(&listCategory, "category", "c", "", "Filter tasks by category")
 	listCmd.Flags().StringVarP(&listPriority, "priority", "p", "", "Filter tasks by priority (low, medium, high)")
+	listCmd.Flags().StringVar(&listProject, "project", "", "Filter tasks by project (name or ID)")
+	listCmd.Flags().StringVarP(&listStatus, "status", "s", "", "Filter tasks by status (todo, in_progress, waiting, blocked, done, cancelled)")
 	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Show all tasks, including completed ones")
 	listCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Show detailed task information")
+	listCmd.Flags().BoolVar(&listTree, "tree", false, "Show tasks as a tree with subtasks nested under their parents")
+	listCmd.Flags().StringVarP(&listWhere, "where", "w", "", "Only tasks matching a query expression (see above)")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "Sort by these fields, e.g. due,-priority")
+	listCmd.Flags().IntVarP(&listLimit, "limit", "n", 0, "Show at most this many tasks")
+	listCmd.Flags().IntVar(&listPage, "page", 1, "Page to show with --limit")
+	listTags.register(listCmd)
+	structured(listCmd)
 }
 
 func runListCmd(cmd *cobra.Command, args []string) error {
+	if listWhere != "" || listSort != "" || listLimit != 0 || cmd.Flags().Changed("page") {
+		return runListQuery()
+	}
+
 	var tasks []*models.Task
 	var err error
 
-	if todoClient != nil {
-		// Use the client to get tasks
-		if listCategory != "" {
-			tasks, err = todoClient.GetTasksByCategory(models.Category(listCategory))
-		} else if listPriority != "" {
-			priority := models.Priority(strings.ToLower(listPriority))
-			if priority != models.PriorityLow && priority != models.PriorityMedium && priority != models.PriorityHigh {
-				return fmt.Errorf("invalid priority: %s (must be low, medium, or high)", listPriority)
-			}
-			tasks, err = todoClient.GetTasksByPriority(priority)
-		} else {
-			tasks, err = todoClient.GetAllTasks()
+	if listCategory != "" {
+		tasks, err = todoService.GetTasksByCategory(models.Category(listCategory))
+	} else if listPriority != "" {
+		priority := models.Priority(strings.ToLower(listPriority))
+		if priority != models.PriorityLow && priority != models.PriorityMedium && priority != models.PriorityHigh {
+			return models.Invalidf("invalid priority: %s (must be low, medium, or high)", listPriority)
 		}
+		tasks, err = todoService.GetTasksByPriority(priority)
 	} else {
-		// Use the app directly (legacy mode)
-		if listCategory != "" {
-			tasks, err = todoApp.GetTasksByCategory(models.Category(listCategory))
-		} else if listPriority != "" {
-			priority := models.Priority(strings.ToLower(listPriority))
-			if priority != models.PriorityLow && priority != models.PriorityMedium && priority != models.PriorityHigh {
-				return fmt.Errorf("invalid priority: %s (must be low, medium, or high)", listPriority)
-			}
-			tasks, err = todoApp.GetTasksByPriority(priority)
-		} else {
-			tasks, err = todoApp.GetAllTasks()
-		}
+		tasks, err = todoService.GetAllTasks()
 	}
 
 	if err != nil {
 		return fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	// Filter completed tasks if requested
-	if !listAll {
+	// Project names are also needed to show the project of each task
+	names := projectNames()
+	if listProject != "" {
+		projects, err := todoService.GetProjects(true)
+		if err != nil {
+			return fmt.Errorf("failed to get projects: %w", err)
+		}
+		project := models.FindProject(projects, listProject)
+		if project == nil {
+			return models.Invalidf("no project named %q (use 'todolist project list' to see all projects)", listProject)
+		}
+
+		var filteredTasks []*models.Task
+		for _, task := range tasks {
+			if task.Project == project.ID {
+				filteredTasks = append(filteredTasks, task)
+			}
+		}
+		tasks = filteredTasks
+	}
+
+	tagFilter, err := listTags.filter()
+	if err != nil {
+		return err
+	}
+	if !tagFilter.IsEmpty() {
+		var filteredTasks []*models.Task
+		for _, task := range tasks {
+			if tagFilter.Matches(task) {
+				filteredTasks = append(filteredTasks, task)
+			}
+		}
+		tasks = filteredTasks
+	}
+
+	// Blockers may be in another category or project, have another priority
+	// or other tags
+	deps := models.NewDependencies(tasks)
+	if listCategory != "" || listPriority != "" || listProject != "" || !tagFilter.IsEmpty() {
+		if all := taskDependencies(); all != nil {
+			deps = all
+		}
+	}
+
+	// The tree view needs completed subtasks to calculate progress, so it
+	// handles hiding them itself
+	if listTree && !isStructured() {
+		ui.PrintTaskTree(tasks, listAll, deps)
+		return nil
+	}
+
+	// Calculate subtask progress before completed tasks are filtered out
+	progress := taskProgress(tasks)
+
+	// Filter by status, or hide closed tasks unless all tasks are requested
+	if listStatus != "" {
+		status, err := models.ParseStatus(listStatus)
+		if err != nil {
+			return err
+		}
+
+		var filteredTasks []*models.Task
+		for _, task := range tasks {
+			if task.Status == status {
+				filteredTasks = append(filteredTasks, task)
+			}
+		}
+		tasks = filteredTasks
+	} else if !listAll {
 		var filteredTasks []*models.Task
 		for _, task := range tasks {
 			if !task.Completed {
@@ -76,6 +175,13 @@ func runListCmd(cmd *cobra.Command, args []string) error {
 		tasks = filteredTasks
 	}
 
+	if isStructured() {
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		})
+		return printTaskListOutput(tasks, len(tasks), 0, 0, names)
+	}
+
 	// Display tasks
 	if len(tasks) == 0 {
 		fmt.Println("No tasks found.")
@@ -83,20 +189,222 @@ func runListCmd(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Found %d tasks:\n\n", len(tasks))
+	printTaskLines(tasks, 1, progress, deps, names)
+	return nil
+}
+
+// runListQuery lists the tasks selected by --where together with the other
+// filter flags, sorted and paged on the server side
+func runListQuery() error {
+	if listLimit < 0 {
+		return models.Invalidf("--limit must not be negative")
+	}
+	if listPage < 1 {
+		return models.Invalidf("--page must be at least 1")
+	}
+
+	where, err := listQueryExpression()
+	if err != nil {
+		return err
+	}
+
+	page, err := todoService.QueryTasks(models.TaskQuery{
+		Where:  where,
+		Sort:   listSort,
+		Offset: (listPage - 1) * listLimit,
+		Limit:  listLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query tasks: %w", err)
+	}
+
+	if isStructured() {
+		return printTaskListOutput(page.Tasks, page.Total, page.Offset, page.Limit, projectNames())
+	}
+
+	if listTree {
+		ui.PrintTaskTree(page.Tasks, true, taskDependencies())
+		return nil
+	}
+
+	if page.Total == 0 {
+		fmt.Println("No tasks found.")
+		return nil
+	}
+	if len(page.Tasks) == 0 {
+		fmt.Printf("Found %d tasks, but page %d is empty.\n", page.Total, listPage)
+		return nil
+	}
+
+	// Progress and blockers take tasks outside the page into account
+	all, err := todoService.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	if len(page.Tasks) < page.Total {
+		pages := (page.Total + listLimit - 1) / listLimit
+		fmt.Printf("Found %d tasks, showing %d-%d (page %d of %d):\n\n",
+			page.Total, page.Offset+1, page.Offset+len(page.Tasks), listPage, pages)
+	} else {
+		fmt.Printf("Found %d tasks:\n\n", page.Total)
+	}
+	printTaskLines(page.Tasks, page.Offset+1, taskProgress(all), models.NewDependencies(all), projectNames())
+	return nil
+}
+
+// listQueryExpression combines --where with the other filter flags into one
+// query expression
+func listQueryExpression() (string, error) {
+	var terms []string
+	showsClosed := listAll || listStatus != ""
+	if listWhere != "" {
+		// Parse locally as well to fail early and see which fields are used
+		expr, err := query.Parse(listWhere, ui.Clock.Now())
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, "("+listWhere+")")
+		showsClosed = showsClosed || query.HasField(expr, query.FieldStatus, query.FieldClosed)
+	}
+
+	if listCategory != "" {
+		terms = append(terms, "category:"+strconv.Quote(listCategory))
+	}
+	if listPriority != "" {
+		terms = append(terms, "priority:"+strconv.Quote(listPriority))
+	}
+	if listProject != "" {
+		terms = append(terms, "project:"+strconv.Quote(listProject))
+	}
+	if listStatus != "" {
+		terms = append(terms, "status:"+strconv.Quote(listStatus))
+	}
+	if !showsClosed {
+		terms = append(terms, "status:open")
+	}
+
+	tags, err := listTags.filter()
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags.All {
+		terms = append(terms, "tag:"+strconv.Quote(tag))
+	}
+	if len(tags.Any) > 0 {
+		anyTags := make([]string, len(tags.Any))
+		for i, tag := range tags.Any {
+			anyTags[i] = "tag:" + strconv.Quote(tag)
+		}
+		terms = append(terms, "("+strings.Join(anyTags, " OR ")+")")
+	}
+	for _, tag := range tags.None {
+		terms = append(terms, "-tag:"+strconv.Quote(tag))
+	}
+
+	return strings.Join(terms, " "), nil
+}
+
+// printTaskListOutput writes tasks in a structured --output format
+func printTaskListOutput(tasks []*models.Task, total, offset, limit int, names map[string]string) error {
+	converted := output.NewTasks(tasks, names)
+	list := output.TaskList{Tasks: converted, Total: total, Offset: offset, Limit: limit}
+	return printOutput(output.KindTaskList, list, output.TaskTable(converted))
+}
+
+// printTaskLines prints tasks one per line, numbered from first, with their
+// details if --verbose is set
+func printTaskLines(tasks []*models.Task, first int, progress map[string]string, deps *models.Dependencies, names map[string]string) {
 	for i, task := range tasks {
-		fmt.Printf("%d. %s\n", i+1, task.String())
+		line := task.String()
+		if p, ok := progress[task.ID]; ok {
+			line += fmt.Sprintf(" [%s]", p)
+		}
+		if blockers := deps.Blockers(task); len(blockers) > 0 && !task.Completed {
+			line += fmt.Sprintf(" [blocked by %d]", len(blockers))
+		}
+		fmt.Printf("%d. %s\n", first+i, line)
 		if listVerbose {
 			if task.Description != "" {
 				fmt.Printf("   Description: %s\n", task.Description)
 			}
 			fmt.Printf("   ID: %s\n", task.ID)
+			if task.Project != "" {
+				name, ok := names[task.Project]
+				if !ok {
+					name = task.Project
+				}
+				fmt.Printf("   Project: %s\n", name)
+			}
+			if task.ParentID != "" {
+				fmt.Printf("   Parent: %s\n", task.ParentID)
+			}
+			if blockers := deps.Blockers(task); len(blockers) > 0 && !task.Completed {
+				fmt.Printf("   Blocked by: %s\n", ui.TaskRefs(blockers))
+			}
+			if dependents := deps.Blocking(task); len(dependents) > 0 {
+				fmt.Printf("   Blocking: %s\n", ui.TaskRefs(dependents))
+			}
 			fmt.Printf("   Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04"))
+			if task.Status != models.StatusTodo {
+				fmt.Printf("   Status: %s%s\n", task.Status, statusSince(task))
+			}
 			if !task.ReminderAt.IsZero() {
 				fmt.Printf("   Reminder: %s\n", task.ReminderAt.Format("2006-01-02 15:04"))
 			}
+			if task.Recurrence != nil {
+				fmt.Printf("   Repeats: %s\n", task.Recurrence)
+			}
+			if len(task.Tags) > 0 {
+				fmt.Printf("   Tags: %s\n", strings.Join(task.Tags, ", "))
+			}
+			if task.Context != "" {
+				fmt.Printf("   Context: @%s\n", task.Context)
+			}
+			if task.Estimate > 0 {
+				fmt.Printf("   Estimate: %s\n", ui.FormatEstimate(task.Estimate))
+			}
+			if task.Energy != "" {
+				fmt.Printf("   Energy: %s\n", task.Energy)
+			}
+			if task.TimeSpent > 0 {
+				fmt.Printf("   Time spent: %s\n", ui.FormatEstimate(task.TimeSpent))
+			}
+			if focus := ui.FocusSummary(task); focus != "" {
+				fmt.Printf("   Focus: %s\n", focus)
+			}
+			for _, note := range task.Notes {
+				fmt.Printf("   Note (%s): %s\n", note.CreatedAt.Format("2006-01-02 15:04"), note.Text)
+			}
 			fmt.Println()
 		}
 	}
+}
 
-	return nil
+// taskProgress maps the IDs of tasks that have subtasks to their progress summary
+func taskProgress(tasks []*models.Task) map[string]string {
+	progress := make(map[string]string)
+
+	var walk func(nodes []*models.TaskNode)
+	walk = func(nodes []*models.TaskNode) {
+		for _, node := range nodes {
+			if p := node.ProgressString(); p != "" {
+				progress[node.Task.ID] = p
+			}
+			walk(node.Children)
+		}
+	}
+	walk(models.BuildTaskTree(tasks))
+
+	return progress
+}
+
+// taskDependencies indexes the dependencies between all tasks for display,
+// or returns nil if the tasks cannot be loaded
+func taskDependencies() *models.Dependencies {
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return nil
+	}
+	return models.NewDependencies(tasks)
 }
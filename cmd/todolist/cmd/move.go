@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/storage"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	moveParent string
+	moveRoot   bool
+
+	moveCmd = &cobra.Command{
+		Use:   "move [task_id]",
+		Short: "Move a task under another task",
+		Long:  `Move a task (with all of its subtasks) under a new parent task, or back to the top level.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID := args[0]
+
+			// Validate task ID format
+			if strings.Contains(taskID, "[") || strings.Contains(taskID, "]") {
+				return models.Invalidf("invalid task ID format: %s (do not include square brackets)", taskID)
+			}
+
+			if moveParent == "" && !moveRoot {
+				return models.Invalidf("specify a new parent with --parent or use --root")
+			}
+			if moveParent != "" && moveRoot {
+				return models.Invalidf("--parent and --root cannot be used together")
+			}
+
+			// Get the task first to check if it exists
+			task, err := todoService.GetTask(taskID)
+			if err != nil {
+				// Provide a more helpful error message for task not found
+				if errors.As(err, new(storage.ErrTaskNotFound)) {
+					return fmt.Errorf("%w (use 'todolist list' to see all tasks)", storage.ErrTaskNotFound{ID: taskID})
+				}
+				return fmt.Errorf("failed to get task: %w", err)
+			}
+
+			if err := todoService.MoveTask(taskID, moveParent); err != nil {
+				return fmt.Errorf("failed to move task: %w", err)
+			}
+
+			if isStructured() {
+				moved, err := todoService.GetTask(taskID)
+				if err != nil {
+					return fmt.Errorf("failed to get task: %w", err)
+				}
+				return printTaskOutput(moved)
+			}
+
+			if moveRoot {
+				ui.PrintSuccess("Task moved to the top level: %s", task.Title)
+			} else {
+				ui.PrintSuccess("Task moved: %s", task.Title)
+			}
+			return nil
+		},
+		Example: `  todolist move 1741359296120413000 --parent 1741359296120400000
+  todolist move 1741359296120413000 --root`,
+	}
+)
+
+func init() {
+	moveCmd.Flags().StringVar(&moveParent, "parent", "", "ID of the new parent task")
+	moveCmd.Flags().BoolVar(&moveRoot, "root", false, "Move the task to the top level")
+	structured(moveCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/ui"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note [task_id] [text...]",
+	Short: "Add a note to a task",
+	Long: `Add a timestamped note to a task, such as a phone number or the outcome of a
+call. Notes are shown by 'todolist list -v' and are searched by 'todolist search'.`,
+	Args:          cobra.MinimumNArgs(2),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		task, err := todoService.AddNote(args[0], strings.Join(args[1:], " "))
+		if err != nil {
+			return fmt.Errorf("failed to add note: %w", err)
+		}
+
+		if isStructured() {
+			return printTaskOutput(task)
+		}
+
+		ui.PrintSuccess("Note added to '%s' (%d notes).", task.Title, len(task.Notes))
+		return nil
+	},
+	Example: `  todolist note 1741359296120413000 "Left a voicemail, call back after 3pm"`,
+}
+
+func init() {
+	structured(noteCmd)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/client"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/storage"
+	"github.com/user/todolist/internal/ui"
+)
+
+// Exit codes, documented in README_OUTPUT.md
+const (
+	ExitOK         = 0
+	ExitError      = 1 // any other failure
+	ExitValidation = 2 // invalid arguments, flags or input
+	ExitNotFound   = 3 // no task or project with the given ID or name
+	ExitConnection = 4 // the server could not be reached or the connection was lost
+)
+
+// structuredAnnotation marks commands that can write their result in every
+// --output format. Other commands only accept --output plain.
+const structuredAnnotation = "structured-output"
+
+var (
+	outputFlag   string
+	outputFormat = output.FormatPlain
+)
+
+// structured marks a command as supporting every --output format
+func structured(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[structuredAnnotation] = "true"
+}
+
+// setupOutput applies the --output flag before a command runs
+func setupOutput(cmd *cobra.Command) error {
+	format, err := output.ParseFormat(outputFlag)
+	if err != nil {
+		return err
+	}
+	outputFormat = format
+	if format == output.FormatPlain {
+		return nil
+	}
+
+	// Keep standard output for the result
+	color.NoColor = true
+	ui.Messages = os.Stderr
+
+	if cmd.Annotations[structuredAnnotation] == "" {
+		return models.Invalidf("'todolist %s' does not support --output %s (use --output plain)",
+			strings.TrimPrefix(cmd.CommandPath(), "todolist "), format)
+	}
+	return nil
+}
+
+// isStructured reports whether the result goes to another program rather
+// than a person
+func isStructured() bool {
+	return outputFormat != output.FormatPlain
+}
+
+// printOutput writes a command result in the --output format
+func printOutput(kind string, data interface{}, table output.Table) error {
+	if err := output.Write(os.Stdout, outputFormat, kind, data, table); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// printTaskOutput writes a single task in a structured --output format
+func printTaskOutput(task *models.Task) error {
+	data := output.NewTask(task, projectNames())
+	return printOutput(output.KindTask, data, output.TaskTable([]output.Task{data}))
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	code, _ := classifyError(err)
+	return code
+}
+
+// classifyError returns the exit code and error code for an error
+func classifyError(err error) (int, string) {
+	if err == nil {
+		return ExitOK, ""
+	}
+
+	switch {
+	case errors.As(err, new(*client.ConnectionError)):
+		return ExitConnection, "connection"
+	case errors.As(err, new(storage.ErrTaskNotFound)), errors.As(err, new(storage.ErrProjectNotFound)):
+		return ExitNotFound, "not_found"
+	case errors.As(err, new(*models.ValidationError)):
+		return ExitValidation, "invalid_input"
+	}
+	return ExitError, "error"
+}
+
+// wrapArgsValidation marks argument count errors of a command and its
+// subcommands as invalid input
+func wrapArgsValidation(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return models.Invalidf("%w", err)
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		wrapArgsValidation(sub)
+	}
+}
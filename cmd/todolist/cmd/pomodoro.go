@@ -1,64 +1,538 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/app"
+	"github.com/user/todolist/internal/client"
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/quickadd"
 	"github.com/user/todolist/internal/storage"
 	"github.com/user/todolist/internal/ui"
+	"github.com/user/todolist/internal/utils"
 )
 
+// pomodoroKeys describes the keyboard controls of a running timer
+const pomodoroKeys = "Keys: [p] pause/resume  [s] skip  [f] finish after this phase  [i] log interruption  [q] stop"
+
 var (
 	pomodoroDuration int
+	pomodoroCycles   int
+	pomodoroDetach   bool
+	pomodoroFollow   bool
+	pomodoroReason   string
 
 	pomodoroCmd = &cobra.Command{
 		Use:   "pomodoro [task_id]",
+		Short: "Start and control a Pomodoro timer for a task",
+		Long: `Start a Pomodoro timer for a task to help you focus on it.
+
+While the timer is shown it can be controlled from the keyboard: pause and
+resume, skip a phase, finish after the current phase, or log an interruption
+as a new inbox task without leaving the timer.
+
+When connected to a server the timer runs on the server, so it can be checked
+and controlled from any terminal with the status, pause, resume, skip, finish,
+interrupt and stop subcommands. In local mode the timer runs while the command
+is open.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			return runPomodoroStart(cmd, args)
+		},
+		Example: `  todolist pomodoro start 1741359296120413000
+  todolist pomodoro start 1741359296120413000 --duration 30
+  todolist pomodoro start 1741359296120413000 --cycles 4
+  todolist pomodoro status --follow
+  todolist pomodoro pause
+  todolist pomodoro skip
+  todolist pomodoro interrupt "call back Sam"
+  todolist pomodoro stop --reason "phone call"`,
+	}
+
+	pomodoroStartCmd = &cobra.Command{
+		Use:   "start [task_id]",
 		Short: "Start a Pomodoro timer for a task",
-		Long:  `Start a Pomodoro timer for a task to help you focus on it.`,
 		Args:  cobra.ExactArgs(1),
+		RunE:  runPomodoroStart,
+	}
+
+	pomodoroStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the running Pomodoro timer",
+		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			taskID := args[0]
+			if pomodoroFollow && isStructured() {
+				return models.Invalidf("--follow cannot be used with --output %s", outputFormat)
+			}
+
+			status, err := todoService.PomodoroStatus()
+			if err != nil {
+				return fmt.Errorf("failed to get pomodoro status: %w", err)
+			}
+
+			if isStructured() {
+				return printPomodoroOutput(status)
+			}
+
+			if !status.Active {
+				ui.PrintInfo("No Pomodoro timer is running.")
+				return nil
+			}
 
-			// Validate task ID format
-			if strings.Contains(taskID, "[") || strings.Contains(taskID, "]") {
-				return fmt.Errorf("invalid task ID format: %s (do not include square brackets)", taskID)
+			if pomodoroFollow {
+				return followPomodoro(status)
 			}
+			fmt.Println(pomodoroLine(status))
+			return nil
+		},
+	}
+
+	pomodoroPauseCmd = pomodoroControlCmd("pause", "Pause the running Pomodoro timer", "Paused", func() (*models.PomodoroStatus, error) {
+		return todoService.PausePomodoro()
+	})
+
+	pomodoroResumeCmd = pomodoroControlCmd("resume", "Resume a paused Pomodoro timer", "Resumed", func() (*models.PomodoroStatus, error) {
+		return todoService.ResumePomodoro()
+	})
+
+	pomodoroSkipCmd = pomodoroControlCmd("skip", "Skip the rest of the current work or break phase", "Skipped to the next phase", func() (*models.PomodoroStatus, error) {
+		return todoService.SkipPomodoroPhase()
+	})
 
-			// Get the task first to check if it exists
-			task, err := todoApp.GetTask(taskID)
+	pomodoroFinishCmd = pomodoroControlCmd("finish", "End the session when the current phase is over", "The session will end after this phase", func() (*models.PomodoroStatus, error) {
+		return todoService.FinishPomodoroCycle()
+	})
+
+	pomodoroInterruptCmd = &cobra.Command{
+		Use:   "interrupt [note]",
+		Short: "Log an interruption as a new inbox task without stopping the timer",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task, err := logInterruption(strings.Join(args, " "))
 			if err != nil {
-				// Provide a more helpful error message for task not found
-				if _, ok := err.(storage.ErrTaskNotFound); ok {
-					return fmt.Errorf("task not found with ID: %s (use 'todolist list' to see all tasks)", taskID)
-				}
-				return fmt.Errorf("failed to get task: %w", err)
+				return err
 			}
 
-			// Validate duration
-			if pomodoroDuration < 0 {
-				return fmt.Errorf("invalid duration: %d (must be a positive number)", pomodoroDuration)
+			if isStructured() {
+				return printTaskOutput(task)
 			}
 
-			// Inform user about how to exit
-			ui.PrintInfo("Starting Pomodoro timer for task: %s", task.Title)
-			ui.PrintInfo("Press Ctrl+C at any time to exit the timer")
-			fmt.Println()
+			ui.PrintSuccess("Interruption noted as task: %s (ID: %s)", task.Title, task.ID)
+			return nil
+		},
+	}
+
+	pomodoroStopCmd = &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running Pomodoro timer",
+		Long: `Stop the running Pomodoro timer.
 
-			// Start Pomodoro timer
-			var duration time.Duration
-			if pomodoroDuration > 0 {
-				duration = time.Duration(pomodoroDuration) * time.Minute
+A work cycle that is stopped early is recorded as interrupted in the Pomodoro
+history, together with the reason given with --reason.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := todoService.StopPomodoro(pomodoroReason)
+			if err != nil {
+				return fmt.Errorf("failed to stop pomodoro: %w", err)
 			}
 
-			return todoApp.StartPomodoro(taskID, duration)
+			if isStructured() {
+				return printPomodoroOutput(status)
+			}
+
+			printPomodoroSummary(status)
+			return nil
 		},
-		Example: `  todolist pomodoro 1741359296120413000
-  todolist pomodoro 1741359296120413000 --duration 30`,
 	}
 )
 
 func init() {
-	pomodoroCmd.Flags().IntVarP(&pomodoroDuration, "duration", "d", 0, "Custom work duration in minutes (default: 25)")
+	for _, c := range []*cobra.Command{pomodoroCmd, pomodoroStartCmd} {
+		c.Flags().IntVarP(&pomodoroDuration, "duration", "d", 0, "Custom work duration in minutes (default: 25)")
+		c.Flags().IntVarP(&pomodoroCycles, "cycles", "n", 0, "Number of work cycles before the session ends (default: until stopped)")
+		c.Flags().BoolVar(&pomodoroDetach, "detach", false, "Return right away and leave the timer running on the server")
+	}
+	pomodoroStatusCmd.Flags().BoolVarP(&pomodoroFollow, "follow", "f", false, "Keep showing the remaining time")
+	pomodoroStopCmd.Flags().StringVarP(&pomodoroReason, "reason", "r", "", "Why the work cycle was interrupted (e.g. \"phone call\")")
+
+	for _, c := range []*cobra.Command{pomodoroStartCmd, pomodoroStatusCmd, pomodoroPauseCmd, pomodoroResumeCmd, pomodoroSkipCmd, pomodoroFinishCmd, pomodoroInterruptCmd, pomodoroStopCmd} {
+		c.SilenceErrors = true
+		c.SilenceUsage = true
+		structured(c)
+		pomodoroCmd.AddCommand(c)
+	}
+	structured(pomodoroCmd)
+}
+
+// pomodoroControlCmd creates a subcommand that applies an action to the running timer
+func pomodoroControlCmd(use, short, done string, action func() (*models.PomodoroStatus, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := action()
+			if err != nil {
+				return fmt.Errorf("failed to %s pomodoro: %w", use, err)
+			}
+
+			if isStructured() {
+				return printPomodoroOutput(status)
+			}
+
+			ui.PrintSuccess("%s: %s", done, pomodoroLine(status))
+			return nil
+		},
+	}
+}
+
+func runPomodoroStart(cmd *cobra.Command, args []string) error {
+	taskID := args[0]
+
+	// Validate task ID format
+	if strings.Contains(taskID, "[") || strings.Contains(taskID, "]") {
+		return models.Invalidf("invalid task ID format: %s (do not include square brackets)", taskID)
+	}
+
+	// Get the task first to check if it exists
+	task, err := todoService.GetTask(taskID)
+	if err != nil {
+		// Provide a more helpful error message for task not found
+		if errors.As(err, new(storage.ErrTaskNotFound)) {
+			return fmt.Errorf("%w (use 'todolist list' to see all tasks)", storage.ErrTaskNotFound{ID: taskID})
+		}
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	// Validate duration and cycles
+	if pomodoroDuration < 0 {
+		return models.Invalidf("invalid duration: %d (must be a positive number)", pomodoroDuration)
+	}
+	if pomodoroCycles < 0 {
+		return models.Invalidf("invalid number of cycles: %d (must be a positive number)", pomodoroCycles)
+	}
+
+	var duration time.Duration
+	if pomodoroDuration > 0 {
+		duration = time.Duration(pomodoroDuration) * time.Minute
+	}
+
+	status, err := todoService.StartPomodoro(taskID, duration, pomodoroCycles)
+	if err != nil {
+		return fmt.Errorf("failed to start pomodoro: %w", err)
+	}
+
+	ui.PrintInfo("Starting Pomodoro timer for task: %s", task.Title)
+
+	// Structured output has no live display: a server timer is written as
+	// it starts, a local one when the session ends
+	todoApp, local := todoService.(*app.App)
+	if isStructured() {
+		if local {
+			return waitPomodoro(todoApp)
+		}
+		return printPomodoroOutput(status)
+	}
+
+	_, remote := todoService.(*client.Client)
+	if remote && pomodoroDetach {
+		ui.PrintInfo("The timer is running on the server. Use 'todolist pomodoro status' to check it.")
+		return nil
+	}
+	return followPomodoro(status)
+}
+
+// followPomodoro shows the remaining time of the running timer until it ends
+// and lets the keyboard control it. Ctrl+C stops the timer in local mode and
+// only stops watching in server mode.
+func followPomodoro(status *models.PomodoroStatus) error {
+	var eventCh <-chan events.Event
+	switch svc := todoService.(type) {
+	case *client.Client:
+		var err error
+		eventCh, err = svc.Subscribe()
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to pomodoro updates: %w", err)
+		}
+	case *app.App:
+		var unsubscribe func()
+		eventCh, unsubscribe = svc.Events.Subscribe()
+		defer unsubscribe()
+	}
+
+	_, remote := todoService.(*client.Client)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	// Keys work without Enter when stdin is a terminal
+	restoreInput, err := ui.KeyInput()
+	if err == nil {
+		defer restoreInput()
+	}
+	keys := readKeys(os.Stdin)
+
+	if remote {
+		ui.PrintInfo("Press Ctrl+C to stop watching; the timer keeps running on the server")
+	} else {
+		ui.PrintInfo("Press Ctrl+C at any time to stop the timer")
+	}
+	ui.PrintInfo(pomodoroKeys)
+	fmt.Println()
+	printPomodoroPhase(status)
+
+	current := status
+	noting := false // reading an interruption note
+	var note strings.Builder
+
+	for {
+		select {
+		case <-interrupt:
+			fmt.Println()
+			if remote {
+				ui.PrintInfo("Stopped watching. Use 'todolist pomodoro stop' to end the timer.")
+				return nil
+			}
+			return stopFollowedPomodoro("interrupted")
+
+		case key, ok := <-keys:
+			if !ok {
+				// Stdin is closed; keep showing the timer without keyboard controls
+				keys = nil
+				continue
+			}
+
+			if noting {
+				if key != '\n' && key != '\r' {
+					note.WriteRune(key)
+					continue
+				}
+
+				noting = false
+				if restoreInput != nil {
+					ui.KeyInput()
+				}
+				if text := strings.TrimSpace(note.String()); text != "" {
+					if task, err := logInterruption(text); err != nil {
+						ui.PrintError("%v", err)
+					} else {
+						ui.PrintSuccess("Noted '%s' in your inbox. Back to work!", task.Title)
+					}
+				}
+				note.Reset()
+				continue
+			}
+
+			switch key {
+			case 'p', 'P', ' ':
+				action := todoService.PausePomodoro
+				if current.Paused {
+					action = todoService.ResumePomodoro
+				}
+				if _, err := action(); err != nil {
+					fmt.Println()
+					ui.PrintError("%v", err)
+				}
+			case 's', 'S':
+				if _, err := todoService.SkipPomodoroPhase(); err != nil {
+					fmt.Println()
+					ui.PrintError("%v", err)
+				}
+			case 'f', 'F':
+				if _, err := todoService.FinishPomodoroCycle(); err != nil {
+					fmt.Println()
+					ui.PrintError("%v", err)
+				}
+			case 'i', 'I':
+				// Show what is typed while the note is entered
+				noting = true
+				if restoreInput != nil {
+					restoreInput()
+				}
+				fmt.Print("\r\033[KInterruption (quick-add syntax works): ")
+			case 'q', 'Q':
+				fmt.Println()
+				return stopFollowedPomodoro("stopped")
+			case '?', 'h', 'H':
+				fmt.Println()
+				ui.PrintInfo(pomodoroKeys)
+			}
+
+		case event, ok := <-eventCh:
+			if !ok {
+				fmt.Println()
+				return fmt.Errorf("lost connection to server")
+			}
+			if event.Pomodoro == nil {
+				continue
+			}
+			current = event.Pomodoro
+
+			switch event.Type {
+			case events.PomodoroPhase:
+				fmt.Print("\a")
+				if !noting {
+					fmt.Println()
+					printPomodoroPhase(event.Pomodoro)
+				}
+			case events.PomodoroStopped:
+				fmt.Println()
+				printPomodoroSummary(event.Pomodoro)
+				return nil
+			}
+			if !noting {
+				fmt.Printf("\r\033[K%s", pomodoroLine(event.Pomodoro))
+			}
+		}
+	}
+}
+
+// waitPomodoro waits for a local timer to end without showing it, and writes
+// the session in a structured --output format. Ctrl+C stops the timer.
+func waitPomodoro(todoApp *app.App) error {
+	eventCh, unsubscribe := todoApp.Events.Subscribe()
+	defer unsubscribe()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	for {
+		select {
+		case <-interrupt:
+			final, err := todoService.StopPomodoro("interrupted")
+			if err != nil {
+				return fmt.Errorf("failed to stop pomodoro: %w", err)
+			}
+			return printPomodoroOutput(final)
+
+		case event := <-eventCh:
+			if event.Type == events.PomodoroStopped && event.Pomodoro != nil {
+				return printPomodoroOutput(event.Pomodoro)
+			}
+		}
+	}
+}
+
+// printPomodoroOutput writes the state of the timer in a structured --output
+// format
+func printPomodoroOutput(status *models.PomodoroStatus) error {
+	data := output.NewPomodoro(status)
+	return printOutput(output.KindPomodoro, data, output.PomodoroTable(data))
+}
+
+// readKeys sends the characters read from r until it is closed
+func readKeys(r io.Reader) <-chan rune {
+	keys := make(chan rune)
+	go func() {
+		defer close(keys)
+		reader := bufio.NewReader(r)
+		for {
+			key, _, err := reader.ReadRune()
+			if err != nil {
+				return
+			}
+			keys <- key
+		}
+	}()
+	return keys
+}
+
+// stopFollowedPomodoro stops the timer and prints the session summary
+func stopFollowedPomodoro(reason string) error {
+	final, err := todoService.StopPomodoro(reason)
+	if err != nil {
+		return fmt.Errorf("failed to stop pomodoro: %w", err)
+	}
+	printPomodoroSummary(final)
+	return nil
+}
+
+// logInterruption adds a quick note taken during the timer as an inbox task
+func logInterruption(note string) (*models.Task, error) {
+	spec, err := quickadd.Parse(note, dateParser())
+	if err != nil {
+		return nil, models.Invalidf("invalid interruption note: %w", err)
+	}
+	if spec.Priority == "" {
+		spec.Priority = models.PriorityMedium
+	}
+	if spec.Category == "" {
+		spec.Category = models.Category("inbox")
+	}
+
+	task, err := todoService.LogInterruption(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log interruption: %w", err)
+	}
+	return task, nil
+}
+
+// printPomodoroPhase announces the phase the timer is in
+func printPomodoroPhase(status *models.PomodoroStatus) {
+	switch status.Phase {
+	case models.PhaseShortBreak:
+		ui.PrintInfo("☕ SHORT BREAK: Take a break for %s", utils.FormatDuration(status.Remaining))
+	case models.PhaseLongBreak:
+		ui.PrintInfo("☕ LONG BREAK: Take a break for %s", utils.FormatDuration(status.Remaining))
+	default:
+		ui.PrintInfo("🍅 WORK CYCLE %d: Focus on '%s' for %s", status.Cycle, status.TaskTitle, utils.FormatDuration(status.Remaining))
+	}
+}
+
+// pomodoroLine formats the state of the timer on one line
+func pomodoroLine(status *models.PomodoroStatus) string {
+	phase := fmt.Sprintf("🍅 Work cycle %d", status.Cycle)
+	if status.Phase.IsBreak() {
+		phase = "☕ " + strings.ReplaceAll(string(status.Phase), "_", " ")
+	}
+
+	if status.Cycles > 0 && !status.Phase.IsBreak() {
+		phase += fmt.Sprintf(" of %d", status.Cycles)
+	}
+
+	minutes := int(status.Remaining.Minutes())
+	seconds := int(status.Remaining.Seconds()) % 60
+	line := fmt.Sprintf("%s: %02d:%02d remaining (%s)", phase, minutes, seconds, status.TaskTitle)
+	if status.Paused {
+		line += " [paused]"
+	}
+	if status.StopAfterPhase {
+		line += " [last phase]"
+	}
+	return line
+}
+
+// printPomodoroSummary prints how a finished session went
+func printPomodoroSummary(status *models.PomodoroStatus) {
+	end := status.EndedAt
+	if end.IsZero() {
+		end = ui.Clock.Now()
+	}
+	elapsed := end.Sub(status.StartedAt)
+
+	ui.PrintSuccess("Pomodoro session for '%s' ended: %d work cycle(s) completed in %s.",
+		status.TaskTitle, status.CompletedCycles, ui.FormatEstimate(elapsed))
+
+	details := []string{fmt.Sprintf("worked %s", ui.FormatEstimate(status.WorkTime))}
+	if status.Interrupted > 0 {
+		details = append(details, fmt.Sprintf("%d cycle(s) cut short", status.Interrupted))
+	}
+	if status.Interruptions > 0 {
+		details = append(details, fmt.Sprintf("%d interruption(s) noted in your inbox", status.Interruptions))
+	}
+	ui.PrintInfo("Summary: %s.", strings.Join(details, ", "))
 }
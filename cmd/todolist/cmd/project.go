@@ -0,0 +1,404 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	projectAll         bool
+	projectDescription string
+	projectDeadline    string
+	projectColor       string
+	projectName        string
+
+	projectCmd = &cobra.Command{
+		Use:   "project",
+		Short: "Manage projects",
+		Long: `Group tasks that work towards a common goal into projects. Each project has a
+name, an optional description, deadline and color, and can be archived once it
+is finished. Tasks are added to a project with 'todolist add --project' or
+'todolist edit --project'; projects can be given by name or ID.
+
+Tasks in archived projects are left out of focus mode.`,
+		Example: `  todolist project add "Website relaunch" --deadline 2025-06-30 --color blue
+  todolist add "Draft landing page copy" --project "website relaunch"
+  todolist project list
+  todolist project show "website relaunch"
+  todolist project archive "website relaunch"`,
+	}
+
+	projectListCmd = &cobra.Command{
+		Use:           "list",
+		Short:         "List projects with their progress",
+		Long:          `List projects with the share of their tasks that are done, the number of overdue tasks and the next action to take.`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runProjectListCmd,
+	}
+
+	projectShowCmd = &cobra.Command{
+		Use:           "show [project]",
+		Short:         "Show a project and its tasks",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runProjectShowCmd,
+	}
+
+	projectAddCmd = &cobra.Command{
+		Use:           "add [name]",
+		Short:         "Create a project",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runProjectAddCmd,
+	}
+
+	projectEditCmd = &cobra.Command{
+		Use:           "edit [project]",
+		Short:         "Change the name, description, deadline or color of a project",
+		Long:          `Change the name, description, deadline or color of a project. Use "none" to clear the deadline or color.`,
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runProjectEditCmd,
+	}
+
+	projectArchiveCmd = &cobra.Command{
+		Use:           "archive [project]",
+		Short:         "Archive a finished or abandoned project",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, err := todoService.ArchiveProject(args[0], true)
+			if err != nil {
+				return fmt.Errorf("failed to archive project: %w", err)
+			}
+
+			if isStructured() {
+				return printProjectResult(project)
+			}
+
+			ui.PrintSuccess("Project archived: %s", project.Name)
+			return nil
+		},
+	}
+
+	projectUnarchiveCmd = &cobra.Command{
+		Use:           "unarchive [project]",
+		Short:         "Bring an archived project back",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, err := todoService.ArchiveProject(args[0], false)
+			if err != nil {
+				return fmt.Errorf("failed to unarchive project: %w", err)
+			}
+
+			if isStructured() {
+				return printProjectResult(project)
+			}
+
+			ui.PrintSuccess("Project unarchived: %s", project.Name)
+			return nil
+		},
+	}
+
+	projectDeleteCmd = &cobra.Command{
+		Use:           "delete [project]",
+		Short:         "Delete a project, keeping its tasks",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summary, err := todoService.ProjectSummary(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get project: %w", err)
+			}
+
+			moved, err := todoService.DeleteProject(summary.Project.ID)
+			if err != nil {
+				return fmt.Errorf("failed to delete project: %w", err)
+			}
+
+			if isStructured() {
+				data := output.ProjectDeletion{Unassigned: moved, Project: output.NewProject(*summary)}
+				table := output.Table{Columns: []string{"id", "name", "unassigned"}}
+				table.Add(data.Project.ID, data.Project.Name, strconv.Itoa(moved))
+				return printOutput(output.KindProjectDelete, data, table)
+			}
+
+			ui.PrintSuccess("Project deleted. %d task(s) no longer belong to a project.", moved)
+			return nil
+		},
+	}
+)
+
+func init() {
+	projectListCmd.Flags().BoolVarP(&projectAll, "all", "a", false, "Include archived projects")
+	for _, c := range []*cobra.Command{projectListCmd, projectShowCmd, projectAddCmd, projectEditCmd, projectArchiveCmd, projectUnarchiveCmd, projectDeleteCmd} {
+		structured(c)
+	}
+	projectShowCmd.Flags().BoolVarP(&projectAll, "all", "a", false, "Include completed tasks")
+
+	projectAddCmd.Flags().StringVarP(&projectDescription, "description", "d", "", "Project description")
+	projectAddCmd.Flags().StringVar(&projectDeadline, "deadline", "", "Deadline (YYYY-MM-DD, end of month, ...)")
+	projectAddCmd.Flags().StringVar(&projectColor, "color", "", "Color ("+strings.Join(models.ProjectColors, ", ")+")")
+
+	projectEditCmd.Flags().StringVar(&projectName, "name", "", "New name")
+	projectEditCmd.Flags().StringVarP(&projectDescription, "description", "d", "", "New description")
+	projectEditCmd.Flags().StringVar(&projectDeadline, "deadline", "", "New deadline or none")
+	projectEditCmd.Flags().StringVar(&projectColor, "color", "", "New color or none")
+
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectShowCmd)
+	projectCmd.AddCommand(projectAddCmd)
+	projectCmd.AddCommand(projectEditCmd)
+	projectCmd.AddCommand(projectArchiveCmd)
+	projectCmd.AddCommand(projectUnarchiveCmd)
+	projectCmd.AddCommand(projectDeleteCmd)
+}
+
+func runProjectListCmd(cmd *cobra.Command, args []string) error {
+	summaries, err := todoService.ProjectSummaries(projectAll)
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	if isStructured() {
+		data := make([]output.Project, len(summaries))
+		table := output.Table{Columns: []string{"id", "name", "done", "total", "percent", "overdue", "deadline", "archived", "next_action"}}
+		for i, summary := range summaries {
+			data[i] = output.NewProject(summary)
+			next := ""
+			if data[i].NextAction != nil {
+				next = data[i].NextAction.Title
+			}
+			table.Add(data[i].ID, data[i].Name, strconv.Itoa(data[i].Done), strconv.Itoa(data[i].Total),
+				strconv.Itoa(data[i].Percent), strconv.Itoa(data[i].Overdue), data[i].Deadline,
+				strconv.FormatBool(data[i].Archived), next)
+		}
+		return printOutput(output.KindProjects, data, table)
+	}
+
+	if len(summaries) == 0 {
+		ui.PrintInfo("No projects yet. Create one with 'todolist project add <name>'.")
+		return nil
+	}
+
+	now := ui.Clock.Now()
+	for _, summary := range summaries {
+		project := summary.Project
+		name := ui.ProjectColor(project.Color)(project.Name)
+		if project.Archived {
+			name += " (archived)"
+		}
+		fmt.Printf("%s  %d/%d done (%d%%)", name, summary.Done, summary.Total, summary.Percent())
+		if summary.Overdue > 0 {
+			fmt.Printf(", %d overdue", summary.Overdue)
+		}
+		if !project.Deadline.IsZero() {
+			fmt.Printf(" | Deadline: %s", project.Deadline.Format("2006-01-02"))
+			if project.IsOverdue(now) {
+				fmt.Print(" (passed)")
+			}
+		}
+		fmt.Println()
+		fmt.Printf("   Next: %s\n", nextActionLine(summary))
+	}
+	return nil
+}
+
+func runProjectShowCmd(cmd *cobra.Command, args []string) error {
+	summary, err := todoService.ProjectSummary(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	if isStructured() {
+		return printProjectOutput(*summary, tasks)
+	}
+
+	project := summary.Project
+	fmt.Println(ui.ProjectColor(project.Color)(project.Name))
+	if project.Description != "" {
+		fmt.Printf("   Description: %s\n", project.Description)
+	}
+	fmt.Printf("   ID: %s\n", project.ID)
+	if !project.Deadline.IsZero() {
+		fmt.Printf("   Deadline: %s\n", project.Deadline.Format("Mon 2006-01-02"))
+	}
+	if project.Archived {
+		fmt.Println("   Archived")
+	}
+	fmt.Printf("   Progress: %d/%d done (%d%%)\n", summary.Done, summary.Total, summary.Percent())
+	if summary.Overdue > 0 {
+		fmt.Printf("   Overdue: %d\n", summary.Overdue)
+	}
+	fmt.Printf("   Next: %s\n", nextActionLine(*summary))
+	fmt.Println()
+
+	var projectTasks []*models.Task
+	for _, task := range tasks {
+		if task.Project == project.ID {
+			projectTasks = append(projectTasks, task)
+		}
+	}
+	if len(projectTasks) == 0 {
+		ui.PrintInfo("No tasks in this project yet. Add one with 'todolist add <title> --project %q'.", project.Name)
+		return nil
+	}
+	ui.PrintTaskTree(projectTasks, projectAll, models.NewDependencies(tasks))
+	return nil
+}
+
+// printProjectOutput writes a project and its tasks in a structured --output
+// format. Completed tasks are left out unless --all is given.
+func printProjectOutput(summary models.ProjectSummary, tasks []*models.Task) error {
+	names := projectNames()
+	data := output.ProjectDetail{Project: output.NewProject(summary), Tasks: []output.Task{}}
+	for _, task := range tasks {
+		if task.Project == summary.Project.ID && (projectAll || !task.Completed) {
+			data.Tasks = append(data.Tasks, output.NewTask(task, names))
+		}
+	}
+	return printOutput(output.KindProject, data, output.TaskTable(data.Tasks))
+}
+
+// printProjectResult writes a project that was just created or changed in a
+// structured --output format, with its open tasks
+func printProjectResult(project *models.Project) error {
+	summary, err := todoService.ProjectSummary(project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	return printProjectOutput(*summary, tasks)
+}
+
+func runProjectAddCmd(cmd *cobra.Command, args []string) error {
+	project := models.Project{
+		Name:        args[0],
+		Description: projectDescription,
+		Color:       projectColor,
+	}
+	if projectDeadline != "" {
+		deadline, err := dateParser().Parse(projectDeadline)
+		if err != nil {
+			return models.Invalidf("invalid deadline: %w", err)
+		}
+		project.Deadline = deadline
+	}
+
+	created, err := todoService.AddProject(project)
+	if err != nil {
+		return fmt.Errorf("failed to add project: %w", err)
+	}
+
+	if isStructured() {
+		return printProjectResult(created)
+	}
+
+	ui.PrintSuccess("Project added: %s", created.Name)
+	fmt.Printf("   ID: %s\n", created.ID)
+	return nil
+}
+
+func runProjectEditCmd(cmd *cobra.Command, args []string) error {
+	var patch models.ProjectPatch
+	if cmd.Flags().Changed("name") {
+		patch.Name = &projectName
+	}
+	if cmd.Flags().Changed("description") {
+		patch.Description = &projectDescription
+	}
+	if cmd.Flags().Changed("deadline") {
+		var deadline time.Time
+		if !isNone(projectDeadline) {
+			var err error
+			deadline, err = dateParser().Parse(projectDeadline)
+			if err != nil {
+				return models.Invalidf("invalid deadline: %w", err)
+			}
+		}
+		patch.Deadline = &deadline
+	}
+	if cmd.Flags().Changed("color") {
+		color := projectColor
+		if isNone(color) {
+			color = ""
+		}
+		patch.Color = &color
+	}
+
+	if patch.IsEmpty() {
+		return models.Invalidf("give at least one of --name, --description, --deadline or --color")
+	}
+
+	project, err := todoService.UpdateProject(args[0], patch)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	if isStructured() {
+		return printProjectResult(project)
+	}
+
+	ui.PrintSuccess("Project updated: %s", project.Name)
+	return nil
+}
+
+// nextActionLine describes the next action of a project
+func nextActionLine(summary models.ProjectSummary) string {
+	if summary.NextAction != nil {
+		return fmt.Sprintf("%s (ID: %s)", summary.NextAction.Title, summary.NextAction.ID)
+	}
+	if summary.Total > 0 && summary.Done == summary.Total {
+		return "nothing left to do"
+	}
+	if summary.Total == 0 {
+		return "no tasks yet"
+	}
+	return "nothing actionable (the open tasks are blocked, waiting or snoozed)"
+}
+
+// isNone reports whether a flag value asks to clear a field
+func isNone(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || strings.EqualFold(value, "none")
+}
+
+// projectNames maps project IDs to names for display, or returns nil if the
+// projects cannot be loaded
+func projectNames() map[string]string {
+	projects, err := todoService.GetProjects(true)
+	if err != nil {
+		return nil
+	}
+
+	names := make(map[string]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	return names
+}
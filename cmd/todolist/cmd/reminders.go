@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	snoozeFor   time.Duration
+	snoozeUntil string
+
+	remindersCmd = &cobra.Command{
+		Use:   "reminders",
+		Short: "Show reminders that need attention",
+		Long: `Show tasks whose reminder time or due date has passed and that have not been
+acknowledged yet. When a server is running it also delivers these reminders
+as they come up; acknowledge or snooze them to stop it from repeating them.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tasks, err := todoService.PendingReminders()
+			if err != nil {
+				return fmt.Errorf("failed to get reminders: %w", err)
+			}
+
+			if isStructured() {
+				data := output.NewTasks(tasks, projectNames())
+				return printOutput(output.KindReminders, data, output.TaskTable(data))
+			}
+
+			if len(tasks) == 0 {
+				ui.PrintInfo("No pending reminders.")
+				return nil
+			}
+
+			ui.PrintTaskList(tasks, "Pending Reminders")
+			fmt.Println("Use 'todolist reminders ack <id>' or 'todolist reminders snooze <id>' to dismiss them.")
+			return nil
+		},
+		Example: `  todolist reminders
+  todolist reminders ack 1741359296120413000
+  todolist reminders snooze 1741359296120413000 --for 30m
+  todolist reminders snooze 1741359296120413000 --until "tomorrow morning"`,
+	}
+
+	remindersAckCmd = &cobra.Command{
+		Use:           "ack [task_id]",
+		Short:         "Acknowledge the reminders of a task",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := todoService.AcknowledgeReminder(args[0]); err != nil {
+				return fmt.Errorf("failed to acknowledge reminder: %w", err)
+			}
+
+			if isStructured() {
+				return printReminderOutput(args[0], time.Time{})
+			}
+
+			ui.PrintSuccess("Reminder acknowledged.")
+			return nil
+		},
+	}
+
+	remindersSnoozeCmd = &cobra.Command{
+		Use:           "snooze [task_id]",
+		Short:         "Snooze the reminders of a task",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			until := ui.Clock.Now().Add(snoozeFor)
+			if snoozeUntil != "" {
+				var err error
+				until, err = dateParser().Parse(snoozeUntil)
+				if err != nil {
+					return models.Invalidf("invalid snooze time: %w", err)
+				}
+			}
+
+			if err := todoService.SnoozeReminder(args[0], until); err != nil {
+				return fmt.Errorf("failed to snooze reminder: %w", err)
+			}
+
+			if isStructured() {
+				return printReminderOutput(args[0], until)
+			}
+
+			ui.PrintSuccess("Reminder snoozed until %s.", until.Format("2006-01-02 15:04"))
+			return nil
+		},
+	}
+)
+
+func init() {
+	remindersSnoozeCmd.Flags().DurationVar(&snoozeFor, "for", 10*time.Minute, "How long to snooze")
+	remindersSnoozeCmd.Flags().StringVar(&snoozeUntil, "until", "", "Snooze until a time (in 2h, tomorrow morning, ...)")
+
+	structured(remindersCmd)
+	structured(remindersAckCmd)
+	structured(remindersSnoozeCmd)
+
+	remindersCmd.AddCommand(remindersAckCmd)
+	remindersCmd.AddCommand(remindersSnoozeCmd)
+}
+
+// printReminderOutput writes an acknowledged reminder, or one snoozed until
+// the given time, in a structured --output format
+func printReminderOutput(taskID string, snoozedUntil time.Time) error {
+	task, err := todoService.GetTask(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	data := output.Reminder{
+		Acknowledged: snoozedUntil.IsZero(),
+		SnoozedUntil: output.FormatTime(snoozedUntil),
+		Task:         output.NewTask(task, projectNames()),
+	}
+	table := output.Table{Columns: append([]string{"acknowledged", "snoozed_until"}, output.TaskColumns...)}
+	table.Add(append([]string{strconv.FormatBool(data.Acknowledged), data.SnoozedUntil}, output.TaskRow(data.Task)...)...)
+	return printOutput(output.KindReminder, data, table)
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
 	"github.com/user/todolist/internal/ui"
 )
 
@@ -24,7 +26,7 @@ var (
 			index, err := strconv.Atoi(backupArg)
 			if err == nil {
 				// It's an index, get the backup file
-				backups, err := todoApp.ListBackups()
+				backups, err := todoService.ListBackups()
 				if err != nil {
 					return fmt.Errorf("failed to list backups: %w", err)
 				}
@@ -34,7 +36,7 @@ var (
 				}
 
 				if index < 1 || index > len(backups) {
-					return fmt.Errorf("invalid backup index: %d (must be between 1 and %d)", index, len(backups))
+					return models.Invalidf("invalid backup index: %d (must be between 1 and %d)", index, len(backups))
 				}
 
 				backupArg = backups[index-1]
@@ -48,16 +50,23 @@ var (
 
 				if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
 					ui.PrintInfo("Restore cancelled")
+					if isStructured() {
+						return printRestoreOutput(backupArg, false)
+					}
 					return nil
 				}
 			}
 
 			// Restore from backup
-			err = todoApp.RestoreTasks(backupArg)
+			err = todoService.RestoreTasks(backupArg)
 			if err != nil {
 				return fmt.Errorf("failed to restore tasks: %w", err)
 			}
 
+			if isStructured() {
+				return printRestoreOutput(backupArg, true)
+			}
+
 			ui.PrintSuccess("Tasks restored from: %s", backupArg)
 			return nil
 		},
@@ -69,4 +78,13 @@ var (
 
 func init() {
 	restoreCmd.Flags().BoolVarP(&restoreForce, "force", "f", false, "Restore without confirmation")
+	structured(restoreCmd)
+}
+
+// printRestoreOutput writes a restore in a structured --output format.
+// restored is false when the restore was cancelled.
+func printRestoreOutput(path string, restored bool) error {
+	table := output.Table{Columns: []string{"restored", "path"}}
+	table.Add(strconv.FormatBool(restored), path)
+	return printOutput(output.KindRestore, output.Restore{Restored: restored, Path: path}, table)
 }
@@ -1,23 +1,34 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/user/todolist/internal/app"
 	"github.com/user/todolist/internal/client"
+	"github.com/user/todolist/internal/dateparse"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/service"
 	"github.com/user/todolist/internal/storage"
 	"github.com/user/todolist/internal/ui"
 )
 
+// defaultServer is the server tried when --server is not given
+const defaultServer = "localhost:8080"
+
+// version is the TodoList release
+const version = "v1.0.0"
+
 var (
-	todoClient *client.Client
-	dataDir    string
-	verbose    bool
-	todoApp    *app.App
+	serverAddr  string
+	dataDir     string
+	storageType string
+	verbose     bool
+	todoService service.TaskService
 )
 
 var rootCmd = &cobra.Command{
@@ -30,21 +41,17 @@ brain dump mode, focus mode, and Pomodoro timer integration.
 `,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Skip initialization for completion and help commands
-		if cmd.Name() == "help" || cmd.Name() == "completion" || cmd.Name() == "version" {
+		if cmd.Name() == "help" || cmd.Name() == "completion" {
 			return nil
 		}
 
-		// If we're using the client, we don't need to initialize the app
-		if todoClient != nil {
-			return nil
+		err := setupOutput(cmd)
+		if err != nil || cmd.Name() == "version" {
+			return err
 		}
 
-		var err error
-		todoApp, err = app.NewApp(nil)
-		if err != nil {
-			return fmt.Errorf("failed to initialize application: %w", err)
-		}
-		return nil
+		todoService, err = openService(cmd)
+		return err
 	},
 	// Add a global error handler for all commands
 	SilenceErrors: true,
@@ -53,44 +60,78 @@ brain dump mode, focus mode, and Pomodoro timer integration.
 
 // Execute executes the root command
 func Execute() error {
+	defer func() {
+		if todoService != nil {
+			todoService.Close()
+		}
+	}()
+
+	// Subcommands are complete only now that every init function has run
+	wrapArgsValidation(rootCmd)
+
 	if err := rootCmd.Execute(); err != nil {
+		if strings.HasPrefix(err.Error(), "unknown command") {
+			err = &models.ValidationError{Err: err}
+		}
 		return handleError(err)
 	}
 	return nil
 }
 
-// InitializeWithClient initializes the command with a client
-func InitializeWithClient(client *client.Client) {
-	todoClient = client
-}
-
 func init() {
 	// Define persistent flags for the root command
+	rootCmd.PersistentFlags().StringVar(&serverAddr, "server", defaultServer, "Address of the TodoList server (empty for local mode)")
 	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", "", "Data directory (defaults to ~/.todolist)")
+	rootCmd.PersistentFlags().StringVar(&storageType, "storage", storage.TypeJSON, "Storage backend for local mode (json, sqlite)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
-
-	// If no client is provided, we're running in standalone mode (for backward compatibility)
-	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", string(output.FormatPlain), "Output format (json, yaml, csv, tsv, table, plain)")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return models.Invalidf("%w", err)
+	})
 
 	// Add commands
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(noteCmd)
 	rootCmd.AddCommand(completeCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(reopenCmd)
 	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(moveCmd)
+	rootCmd.AddCommand(blockCmd)
+	rootCmd.AddCommand(unblockCmd)
+	rootCmd.AddCommand(tagsCmd)
+	rootCmd.AddCommand(projectCmd)
 	rootCmd.AddCommand(brainDumpCmd)
 	rootCmd.AddCommand(focusCmd)
 	rootCmd.AddCommand(pomodoroCmd)
+	rootCmd.AddCommand(remindersCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(statsCmd)
 	rootCmd.AddCommand(backupCmd)
 	rootCmd.AddCommand(restoreCmd)
 
 	// Add version command
-	rootCmd.AddCommand(&cobra.Command{
+	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version number",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("TodoList v1.0.0")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isStructured() {
+				data := output.Version{Version: version}
+				return printOutput(output.KindVersion, data, output.Table{
+					Columns: []string{"version"},
+					Rows:    [][]string{{data.Version}},
+				})
+			}
+
+			fmt.Printf("TodoList %s\n", version)
+			return nil
 		},
-	})
+	}
+	structured(versionCmd)
+	rootCmd.AddCommand(versionCmd)
 
 	// Set up custom error handling for all commands
 	cobra.OnInitialize(func() {
@@ -101,59 +142,79 @@ func init() {
 	})
 }
 
-// initConfig initializes the application configuration
-func initConfig() {
-	// Skip initialization if client is already set
-	if todoClient != nil {
-		return
+// openService connects to the server selected by --server, or works on local
+// storage when it is empty. Only when --server is not given may the server be
+// unreachable; the local data is used instead.
+func openService(cmd *cobra.Command) (service.TaskService, error) {
+	if serverAddr != "" {
+		todoClient, err := client.NewClient(serverAddr)
+		if err == nil {
+			return todoClient, nil
+		}
+		if cmd.Flags().Changed("server") {
+			return nil, err
+		}
+		ui.PrintWarning("Cannot reach the server at %s, using local mode", serverAddr)
 	}
 
-	// Legacy standalone mode - initialize app directly
-	config := app.DefaultConfig()
-	if dataDir != "" {
-		config.DataDir = dataDir
-		config.StorageFile = filepath.Join(dataDir, "tasks.json")
-		config.BackupDir = filepath.Join(dataDir, "backups")
+	todoApp, err := newLocalApp()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize application: %w", err)
 	}
+	return todoApp, nil
+}
 
-	// Create data directory if it doesn't exist
-	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating data directory: %v\n", err)
-		os.Exit(1)
-	}
-	if err := os.MkdirAll(config.BackupDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating backup directory: %v\n", err)
-		os.Exit(1)
+// newLocalApp creates an application that works directly on local storage,
+// on the same clock as the UI
+func newLocalApp() (*app.App, error) {
+	todoApp, err := app.NewApp(localConfig())
+	if err != nil {
+		return nil, err
 	}
+	todoApp.Clock = ui.Clock
+	return todoApp, nil
+}
 
-	// Initialize storage
-	store := storage.NewJSONStorage(config.StorageFile)
-	if err := store.Initialize(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
-		os.Exit(1)
-	}
+// dateParser returns a parser for dates relative to the clock of the UI
+func dateParser() *dateparse.Parser {
+	return dateparse.NewWithClock(ui.Clock.Now)
+}
 
-	// Initialize app
-	var err error
-	todoApp, err = app.NewApp(config)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing app: %v\n", err)
-		os.Exit(1)
+// localConfig returns the configuration selected by the global flags
+func localConfig() *app.Config {
+	config := app.DefaultConfig()
+	if dataDir != "" {
+		config.SetDataDir(dataDir)
 	}
+	config.StorageType = storageType
+	return config
 }
 
-// handleError handles errors in a user-friendly way
+// handleError handles errors in a user-friendly way, or as an error document
+// for JSON and YAML output
 func handleError(err error) error {
 	if err == nil {
 		return nil
 	}
 
+	exitCode, code := classifyError(err)
+
+	// Flag errors happen before the output is set up
+	if format, parseErr := output.ParseFormat(outputFlag); parseErr == nil && format.IsDocument() {
+		output.WriteError(os.Stderr, format, output.Error{Code: code, ExitCode: exitCode, Message: err.Error()})
+		return err
+	}
+
 	// Check if it's a known error type
 	switch {
-	case strings.Contains(err.Error(), "not found"):
+	case exitCode == ExitConnection:
+		ui.PrintError("Connection to the server failed: %v", err)
+	case errors.As(err, new(storage.ErrProjectNotFound)):
+		ui.PrintError("Project not found. Use 'todolist project list --all' to see all projects.")
+	case exitCode == ExitNotFound:
 		ui.PrintError("Task not found. Please check the ID and try again.")
-	case strings.Contains(err.Error(), "invalid input"):
-		ui.PrintError("Invalid input: %v", err)
+	case exitCode == ExitValidation:
+		ui.PrintError("Invalid input: %v", strings.TrimPrefix(err.Error(), "invalid input: "))
 	default:
 		ui.PrintError("Error: %v", err)
 	}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// execute runs the CLI with the given arguments against a fresh data
+// directory and returns the exit code
+func execute(t *testing.T, args ...string) int {
+	t.Helper()
+
+	t.Cleanup(func() {
+		todoService = nil
+		rootCmd.SetArgs(nil)
+		rootCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+		})
+	})
+
+	rootCmd.SetArgs(append(args, "--data-dir", t.TempDir()))
+	return ExitCode(Execute())
+}
+
+func TestServerFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"local mode", []string{"--server", "", "list"}, ExitOK},
+		{"after the subcommand", []string{"list", "--server="}, ExitOK},
+		{"with structured output after the subcommand", []string{"list", "--server=", "--output", "json"}, ExitOK},
+		{"with structured output before the subcommand", []string{"--output", "json", "--server=", "list"}, ExitOK},
+		{"unreachable server", []string{"list", "--server", "127.0.0.1:1"}, ExitConnection},
+		{"unreachable server with structured output", []string{"-o", "json", "list", "--server", "127.0.0.1:1"}, ExitConnection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := execute(t, tt.args...); got != tt.want {
+				t.Errorf("todolist %v exited with %d, want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEveryCommandIsStructured(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, child := range cmd.Commands() {
+			// help and completion are added by cobra and do not print results
+			if child.Name() == "help" || child.Name() == "completion" {
+				continue
+			}
+			if child.Runnable() && child.Annotations[structuredAnnotation] == "" {
+				t.Errorf("%q does not support structured output", child.CommandPath())
+			}
+			walk(child)
+		}
+	}
+	walk(rootCmd)
+}
+
+func TestStructuredOutput(t *testing.T) {
+	tests := [][]string{
+		{"pomodoro", "status"},
+		{"version"},
+		{"tags", "rename", "work", "office"},
+	}
+
+	for _, args := range tests {
+		args = append(args, "--server=", "-o", "json")
+		if got := execute(t, args...); got != ExitOK {
+			t.Errorf("todolist %v exited with %d, want %d", args, got, ExitOK)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/search"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	searchAll   bool
+	searchLimit int
+
+	searchCmd = &cobra.Command{
+		Use:   "search [words...]",
+		Short: "Search the title, description and notes of tasks",
+		Long: `Search the title, description and notes of tasks, best matches first.
+
+Every word has to match. Words match regardless of their ending ("reports"
+finds "reporting"), as the start of a longer word ("pres" finds
+"presentation") and, if nothing else matches, despite a typo ("reprot").
+Matches in the title count more than matches in the description or notes.
+Done and cancelled tasks are left out unless --all is given.`,
+		Args:          cobra.MinimumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runSearchCmd,
+		Example: `  todolist search report
+  todolist search quarterly budget --all
+  todolist search dentist -n 3`,
+	}
+)
+
+func init() {
+	searchCmd.Flags().BoolVarP(&searchAll, "all", "a", false, "Include done and cancelled tasks")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Show at most this many results (0 for all)")
+	structured(searchCmd)
+}
+
+func runSearchCmd(cmd *cobra.Command, args []string) error {
+	text := strings.Join(args, " ")
+	results, err := todoService.SearchTasks(models.SearchQuery{Text: text, Limit: searchLimit, All: searchAll})
+	if err != nil {
+		return fmt.Errorf("failed to search tasks: %w", err)
+	}
+
+	if isStructured() {
+		return printSearchOutput(results)
+	}
+
+	if len(results) == 0 {
+		ui.PrintInfo("No tasks match %q.", text)
+		return nil
+	}
+
+	fmt.Printf("Found %d tasks matching %q:\n\n", len(results), text)
+	for i, result := range results {
+		task := result.Task
+		title := task.Title
+		for _, snippet := range result.Snippets {
+			if snippet.Field == search.FieldTitle {
+				title = ui.Highlight(snippet)
+			}
+		}
+		fmt.Printf("%d. %s %s (ID: %s)\n", i+1, task.Status.Marker(), title, task.ID)
+
+		for _, snippet := range result.Snippets {
+			switch snippet.Field {
+			case search.FieldDescription:
+				fmt.Printf("   Description: %s\n", ui.Highlight(snippet))
+			case search.FieldNotes:
+				fmt.Printf("   Note: %s\n", ui.Highlight(snippet))
+			}
+		}
+	}
+	return nil
+}
+
+// printSearchOutput writes search results in a structured --output format
+func printSearchOutput(results []*models.SearchResult) error {
+	names := projectNames()
+	data := make([]output.SearchResult, len(results))
+	table := output.Table{Columns: append([]string{"score"}, output.TaskColumns...)}
+	for i, result := range results {
+		snippets := result.Snippets
+		if snippets == nil {
+			snippets = []models.Snippet{}
+		}
+		data[i] = output.SearchResult{Score: result.Score, Task: output.NewTask(result.Task, names), Snippets: snippets}
+		table.Add(append([]string{strconv.FormatFloat(result.Score, 'f', -1, 64)}, output.TaskRow(data[i].Task)...)...)
+	}
+	return printOutput(output.KindSearchResults, data, table)
+}
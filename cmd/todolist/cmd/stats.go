@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	statsDays     int
+	statsSkipDays int
+
+	statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics about your work",
+	}
+
+	statsTimeCmd = &cobra.Command{
+		Use:   "time",
+		Short: "Show where your Pomodoro time went",
+		Long: `Show the time spent in Pomodoro work cycles per task, per category and per
+day, along with how many cycles were interrupted and why.`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runStatsTime,
+		Example: `  todolist stats time
+  todolist stats time --days 30
+  todolist stats time --days 0`,
+	}
+
+	statsSkipsCmd = &cobra.Command{
+		Use:   "skips",
+		Short: "Show the focus suggestions you skip most",
+		Long: `Show which tasks were skipped most often in focus mode, and why. Tasks that
+keep getting skipped may need to be broken down, rescheduled or dropped.`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runStatsSkips,
+		Example: `  todolist stats skips
+  todolist stats skips --days 7`,
+	}
+)
+
+func init() {
+	statsTimeCmd.Flags().IntVarP(&statsDays, "days", "d", 7, "Number of days to include, counting today (0 for all history)")
+	statsSkipsCmd.Flags().IntVarP(&statsSkipDays, "days", "d", 30, "Number of days to include, counting today (0 for all history)")
+	structured(statsTimeCmd)
+	structured(statsSkipsCmd)
+	statsCmd.AddCommand(statsTimeCmd)
+	statsCmd.AddCommand(statsSkipsCmd)
+}
+
+// timeTotal accumulates the work intervals of one group
+type timeTotal struct {
+	key         string
+	name        string
+	spent       time.Duration
+	completed   int
+	interrupted int
+}
+
+func (t *timeTotal) add(record *models.PomodoroRecord) {
+	t.spent += record.Duration()
+	if record.Completed {
+		t.completed++
+	} else {
+		t.interrupted++
+	}
+}
+
+func runStatsTime(cmd *cobra.Command, args []string) error {
+	if statsDays < 0 {
+		return models.Invalidf("days must not be negative")
+	}
+
+	since, period := statsPeriod(statsDays)
+	records, err := todoService.GetPomodoroHistory(since)
+	if err != nil {
+		return fmt.Errorf("failed to get pomodoro history: %w", err)
+	}
+
+	if len(records) == 0 && !isStructured() {
+		ui.PrintInfo("No Pomodoro work cycles recorded %s.", period)
+		return nil
+	}
+
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	tasksByID := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+
+	var total timeTotal
+	byTask := make(map[string]*timeTotal)
+	byCategory := make(map[string]*timeTotal)
+	byDay := make(map[string]*timeTotal)
+	reasons := make(map[string]int)
+
+	group := func(groups map[string]*timeTotal, key, name string) *timeTotal {
+		if groups[key] == nil {
+			groups[key] = &timeTotal{key: key, name: name}
+		}
+		return groups[key]
+	}
+
+	for _, record := range records {
+		title, category := "(deleted task)", "(unknown)"
+		if task, ok := tasksByID[record.TaskID]; ok {
+			title, category = task.Title, string(task.Category)
+		}
+
+		total.add(record)
+		day := record.Start.Local()
+		group(byTask, record.TaskID, title).add(record)
+		group(byCategory, category, category).add(record)
+		group(byDay, day.Format("2006-01-02"), day.Format("2006-01-02 Mon")).add(record)
+		if !record.Completed {
+			reasons[record.Reason]++
+		}
+	}
+
+	if isStructured() {
+		stats := output.TimeStats{
+			Since:         output.FormatTime(since),
+			SpentSeconds:  output.Seconds(total.spent),
+			Completed:     total.completed,
+			Interrupted:   total.interrupted,
+			ByTask:        timeGroups(sortedTotals(byTask, false)),
+			ByCategory:    timeGroups(sortedTotals(byCategory, false)),
+			ByDay:         timeGroups(sortedTotals(byDay, true)),
+			Interruptions: []output.ReasonCount{},
+		}
+		for _, reason := range sortedReasons(reasons) {
+			stats.Interruptions = append(stats.Interruptions, output.ReasonCount{Reason: reason, Count: reasons[reason]})
+		}
+
+		table := output.Table{Columns: []string{"group", "key", "name", "spent_seconds", "completed", "interrupted"}}
+		addTimeRows(&table, "task", stats.ByTask)
+		addTimeRows(&table, "category", stats.ByCategory)
+		addTimeRows(&table, "day", stats.ByDay)
+		return printOutput(output.KindTimeStats, stats, table)
+	}
+
+	title := fmt.Sprintf("Time spent %s", period)
+	fmt.Println(strings.ToUpper(title))
+	fmt.Println(strings.Repeat("-", len(title)))
+	fmt.Println()
+
+	printTimeTotals("By task", sortedTotals(byTask, false), total.spent)
+	printTimeTotals("By category", sortedTotals(byCategory, false), total.spent)
+	printTimeTotals("By day", sortedTotals(byDay, true), total.spent)
+
+	if len(reasons) > 0 {
+		fmt.Println("Interruptions:")
+		for _, reason := range sortedReasons(reasons) {
+			label := reason
+			if label == "" {
+				label = "(no reason given)"
+			}
+			fmt.Printf("  %3d× %s\n", reasons[reason], label)
+		}
+		fmt.Println()
+	}
+
+	ui.PrintInfo("Total: %s in %d work cycle(s), %d completed and %d interrupted.",
+		ui.FormatEstimate(total.spent), total.completed+total.interrupted, total.completed, total.interrupted)
+	return nil
+}
+
+// statsPeriod returns the start of a period of the given number of days,
+// counting today, and describes it; 0 days means all history
+func statsPeriod(days int) (time.Time, string) {
+	if days == 0 {
+		return time.Time{}, "all time"
+	}
+
+	now := ui.Clock.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := today.AddDate(0, 0, -(days - 1))
+	return since, fmt.Sprintf("since %s", since.Format("Mon 2006-01-02"))
+}
+
+// skipTotal counts the focus skips of one task
+type skipTotal struct {
+	task    *models.Task
+	count   int
+	reasons map[string]int
+}
+
+func runStatsSkips(cmd *cobra.Command, args []string) error {
+	if statsSkipDays < 0 {
+		return models.Invalidf("days must not be negative")
+	}
+	since, period := statsPeriod(statsSkipDays)
+
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	var totals []*skipTotal
+	overall := 0
+	for _, task := range tasks {
+		if task.Focus == nil {
+			continue
+		}
+
+		total := &skipTotal{task: task, reasons: make(map[string]int)}
+		for _, skip := range task.Focus.Skips {
+			if !skip.At.Before(since) {
+				total.count++
+				total.reasons[skip.Reason]++
+			}
+		}
+		if total.count > 0 {
+			totals = append(totals, total)
+			overall += total.count
+		}
+	}
+
+	if len(totals) == 0 && !isStructured() {
+		ui.PrintInfo("No focus suggestions skipped %s.", period)
+		return nil
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].count != totals[j].count {
+			return totals[i].count > totals[j].count
+		}
+		return totals[i].task.ID < totals[j].task.ID
+	})
+
+	if isStructured() {
+		return printSkipStatsOutput(since, overall, totals)
+	}
+
+	title := fmt.Sprintf("Skipped focus suggestions %s", period)
+	fmt.Println(strings.ToUpper(title))
+	fmt.Println(strings.Repeat("-", len(title)))
+	fmt.Println()
+
+	now := ui.Clock.Now()
+	for _, total := range totals {
+		status := ""
+		switch {
+		case total.task.Completed:
+			status = " [done]"
+		case total.task.IsFocusSnoozed(now):
+			status = fmt.Sprintf(" [snoozed until %s]", total.task.Focus.SnoozedUntil.Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("  %3d× %s (ID: %s)%s\n", total.count, total.task.Title, total.task.ID, status)
+
+		for _, reason := range sortedReasons(total.reasons) {
+			label := reason
+			if label == "" {
+				label = "(no reason given)"
+			}
+			fmt.Printf("        %d× %s\n", total.reasons[reason], label)
+		}
+	}
+	fmt.Println()
+
+	ui.PrintInfo("Total: %d skip(s) of %d task(s).", overall, len(totals))
+	return nil
+}
+
+// printSkipStatsOutput writes the skip statistics in a structured --output
+// format
+func printSkipStatsOutput(since time.Time, overall int, totals []*skipTotal) error {
+	names := projectNames()
+	stats := output.SkipStats{Since: output.FormatTime(since), Skips: overall, Tasks: []output.SkippedTask{}}
+	table := output.Table{Columns: append([]string{"skips", "top_reason"}, output.TaskColumns...)}
+	for _, total := range totals {
+		skipped := output.SkippedTask{Skips: total.count, Reasons: []output.ReasonCount{}, Task: output.NewTask(total.task, names)}
+		for _, reason := range sortedReasons(total.reasons) {
+			skipped.Reasons = append(skipped.Reasons, output.ReasonCount{Reason: reason, Count: total.reasons[reason]})
+		}
+		stats.Tasks = append(stats.Tasks, skipped)
+
+		row := []string{strconv.Itoa(total.count), skipped.Reasons[0].Reason}
+		table.Add(append(row, output.TaskRow(skipped.Task)...)...)
+	}
+	return printOutput(output.KindSkipStats, stats, table)
+}
+
+// sortedReasons returns the reasons ordered by count, most frequent first
+func sortedReasons(counts map[string]int) []string {
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	return reasons
+}
+
+// timeGroups converts time totals for output
+func timeGroups(totals []*timeTotal) []output.TimeGroup {
+	groups := make([]output.TimeGroup, len(totals))
+	for i, total := range totals {
+		groups[i] = output.TimeGroup{
+			Key:          total.key,
+			Name:         total.name,
+			SpentSeconds: output.Seconds(total.spent),
+			Completed:    total.completed,
+			Interrupted:  total.interrupted,
+		}
+	}
+	return groups
+}
+
+// addTimeRows adds the time groups to the table form of the time statistics
+func addTimeRows(table *output.Table, group string, groups []output.TimeGroup) {
+	for _, g := range groups {
+		table.Add(group, g.Key, g.Name, strconv.FormatInt(g.SpentSeconds, 10), strconv.Itoa(g.Completed), strconv.Itoa(g.Interrupted))
+	}
+}
+
+// sortedTotals returns the groups ordered by name, or by time spent with the
+// largest first
+func sortedTotals(groups map[string]*timeTotal, byName bool) []*timeTotal {
+	totals := make([]*timeTotal, 0, len(groups))
+	for _, total := range groups {
+		totals = append(totals, total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if !byName && totals[i].spent != totals[j].spent {
+			return totals[i].spent > totals[j].spent
+		}
+		return totals[i].name < totals[j].name
+	})
+	return totals
+}
+
+// printTimeTotals prints one section of the time report with a bar showing
+// each group's share of the total
+func printTimeTotals(heading string, totals []*timeTotal, overall time.Duration) {
+	const barWidth = 20
+
+	fmt.Printf("%s:\n", heading)
+	for _, total := range totals {
+		width := 0
+		if overall > 0 {
+			width = int(float64(total.spent) / float64(overall) * barWidth)
+		}
+
+		cycles := fmt.Sprintf("%d 🍅", total.completed)
+		if total.interrupted > 0 {
+			cycles += fmt.Sprintf(", %d interrupted", total.interrupted)
+		}
+
+		bar := strings.Repeat("█", width) + strings.Repeat(" ", barWidth-width)
+		fmt.Printf("  %6s %s %s (%s)\n", ui.FormatEstimate(total.spent), bar, total.name, cycles)
+	}
+	fmt.Println()
+}
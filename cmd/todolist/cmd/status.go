@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	statusCmd = &cobra.Command{
+		Use:   "status [task_id] [state]",
+		Short: "Show or change the status of a task",
+		Long: `Show the status of a task and how it got there, or move it to a new status:
+
+  todo         not started yet
+  in_progress  being worked on
+  waiting      waiting on someone or something else
+  blocked      stuck until something changes
+  done         finished
+  cancelled    won't be done
+
+Waiting and blocked tasks are left out of focus mode. Moving a task to done or
+cancelled also closes its open subtasks and schedules the next occurrence of a
+recurring task; moving a closed task to an open status reopens it.`,
+		Args:          cobra.RangeArgs(1, 2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runStatusCmd,
+		Example: `  todolist status 1741359296120413000
+  todolist status 1741359296120413000 in_progress
+  todolist status 1741359296120413000 waiting
+  todolist status 1741359296120413000 cancelled`,
+	}
+
+	reopenCmd = &cobra.Command{
+		Use:           "reopen [task_id]",
+		Short:         "Move a done or cancelled task back to the to-do list",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task, err := todoService.ReopenTask(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to reopen task: %w", err)
+			}
+
+			if isStructured() {
+				return printTaskOutput(task)
+			}
+
+			ui.PrintSuccess("Task reopened: %s", task.Title)
+			return nil
+		},
+		Example: `  todolist reopen 1741359296120413000`,
+	}
+)
+
+func init() {
+	structured(statusCmd)
+	structured(reopenCmd)
+}
+
+func runStatusCmd(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		task, err := todoService.GetTask(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if isStructured() {
+			return printStatusOutput(task)
+		}
+		printStatusHistory(task)
+		return nil
+	}
+
+	status, err := models.ParseStatus(args[1])
+	if err != nil {
+		return err
+	}
+
+	task, err := todoService.SetTaskStatus(args[0], status)
+	if err != nil {
+		return fmt.Errorf("failed to set status: %w", err)
+	}
+
+	if isStructured() {
+		return printStatusOutput(task)
+	}
+
+	ui.PrintSuccess("'%s' is now %s.", task.Title, task.Status)
+	return nil
+}
+
+// printStatusOutput writes the status history of a task in a structured
+// --output format, one row per change
+func printStatusOutput(task *models.Task) error {
+	data := output.NewTaskStatus(task, projectNames())
+	table := output.Table{Columns: []string{"id", "at", "from", "to"}}
+	for _, change := range data.History {
+		table.Add(task.ID, change.At, change.From, change.To)
+	}
+	return printOutput(output.KindTaskStatus, data, table)
+}
+
+// printStatusHistory prints the current status of a task and its transitions
+func printStatusHistory(task *models.Task) {
+	fmt.Printf("%s %s\n", task.Status.Marker(), task.Title)
+	fmt.Printf("   Status: %s%s\n", ui.StatusColor(task.Status)(task.Status.String()), statusSince(task))
+	if !task.CompletedAt.IsZero() {
+		fmt.Printf("   Closed: %s\n", task.CompletedAt.Format("2006-01-02 15:04"))
+	}
+
+	if len(task.StatusHistory) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("   %s  created\n", task.CreatedAt.Format("2006-01-02 15:04"))
+	for _, change := range task.StatusHistory {
+		fmt.Printf("   %s  %s → %s\n", change.At.Format("2006-01-02 15:04"), change.From, change.To)
+	}
+}
+
+// statusSince formats when a task entered its current status, if known
+func statusSince(task *models.Task) string {
+	since := task.StatusSince()
+	if since.IsZero() {
+		return ""
+	}
+	return " since " + since.Format("2006-01-02 15:04")
+}
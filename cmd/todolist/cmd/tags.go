@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/ui"
+)
+
+var (
+	tagsMergeInto string
+
+	tagsCmd = &cobra.Command{
+		Use:   "tags",
+		Short: "Show and manage tags",
+		Long: `Show every tag in use with the number of tasks that carry it, and add, remove,
+rename or merge tags. A task can have any number of tags.`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runTagsCmd,
+		Example: `  todolist tags
+  todolist tags add 1741359296120413000 urgent waiting-on-review
+  todolist tags remove 1741359296120413000 urgent
+  todolist tags rename review waiting-on-review
+  todolist tags merge errand errands shopping --into errands`,
+	}
+
+	tagsAddCmd = &cobra.Command{
+		Use:           "add [task_id] [tag...]",
+		Short:         "Add tags to a task",
+		Args:          cobra.MinimumNArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task, err := todoService.AddTags(args[0], args[1:])
+			if err != nil {
+				return fmt.Errorf("failed to add tags: %w", err)
+			}
+
+			if isStructured() {
+				return printTaskOutput(task)
+			}
+
+			ui.PrintSuccess("Tags of '%s': %s", task.Title, strings.Join(task.Tags, ", "))
+			return nil
+		},
+	}
+
+	tagsRemoveCmd = &cobra.Command{
+		Use:           "remove [task_id] [tag...]",
+		Short:         "Remove tags from a task",
+		Args:          cobra.MinimumNArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task, err := todoService.RemoveTags(args[0], args[1:])
+			if err != nil {
+				return fmt.Errorf("failed to remove tags: %w", err)
+			}
+
+			if isStructured() {
+				return printTaskOutput(task)
+			}
+
+			if len(task.Tags) == 0 {
+				ui.PrintSuccess("'%s' has no tags left.", task.Title)
+			} else {
+				ui.PrintSuccess("Tags of '%s': %s", task.Title, strings.Join(task.Tags, ", "))
+			}
+			return nil
+		},
+	}
+
+	tagsRenameCmd = &cobra.Command{
+		Use:           "rename [old_tag] [new_tag]",
+		Short:         "Rename a tag on every task",
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return renameTags(args[:1], args[1])
+		},
+	}
+
+	tagsMergeCmd = &cobra.Command{
+		Use:           "merge [tag...] --into [tag]",
+		Short:         "Merge several tags into one on every task",
+		Args:          cobra.MinimumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tagsMergeInto == "" {
+				return models.Invalidf("give the tag to merge into with --into")
+			}
+			return renameTags(args, tagsMergeInto)
+		},
+	}
+)
+
+func init() {
+	tagsMergeCmd.Flags().StringVar(&tagsMergeInto, "into", "", "Tag that replaces the merged tags")
+	structured(tagsCmd)
+	structured(tagsAddCmd)
+	structured(tagsRemoveCmd)
+	structured(tagsRenameCmd)
+	structured(tagsMergeCmd)
+
+	tagsCmd.AddCommand(tagsAddCmd)
+	tagsCmd.AddCommand(tagsRemoveCmd)
+	tagsCmd.AddCommand(tagsRenameCmd)
+	tagsCmd.AddCommand(tagsMergeCmd)
+}
+
+func runTagsCmd(cmd *cobra.Command, args []string) error {
+	counts, err := todoService.TagCounts()
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	if isStructured() {
+		data := make([]output.Tag, len(counts))
+		table := output.Table{Columns: []string{"tag", "open", "total"}}
+		for i, count := range counts {
+			data[i] = output.Tag{Tag: count.Tag, Tasks: count.Count, Open: count.Open}
+			table.Add(count.Tag, strconv.Itoa(count.Open), strconv.Itoa(count.Count))
+		}
+		return printOutput(output.KindTags, data, table)
+	}
+
+	if len(counts) == 0 {
+		ui.PrintInfo("No tags in use. Add some with 'todolist tags add <id> <tag>' or +tag in quick-add.")
+		return nil
+	}
+
+	fmt.Printf("%-24s %5s %5s\n", "TAG", "OPEN", "TOTAL")
+	for _, count := range counts {
+		fmt.Printf("%-24s %5d %5d\n", count.Tag, count.Open, count.Count)
+	}
+	return nil
+}
+
+// renameTags replaces tags on every task and reports how many were changed
+func renameTags(from []string, to string) error {
+	updated, err := todoService.RenameTags(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to rename tags: %w", err)
+	}
+
+	if isStructured() {
+		data := output.TagRename{From: make([]string, len(from)), To: models.NormalizeTag(to), Updated: updated}
+		for i, tag := range from {
+			data.From[i] = models.NormalizeTag(tag)
+		}
+		table := output.Table{Columns: []string{"from", "to", "updated"}}
+		table.Add(strings.Join(data.From, ","), data.To, strconv.Itoa(updated))
+		return printOutput(output.KindTagRename, data, table)
+	}
+
+	if updated == 0 {
+		ui.PrintInfo("No tasks are tagged %s.", strings.Join(from, ", "))
+		return nil
+	}
+	ui.PrintSuccess("Tagged %d task(s) %s instead of %s.", updated, models.NormalizeTag(to), strings.Join(from, ", "))
+	return nil
+}
+
+// tagFilterFlags holds the tag filter flags shared by the commands that
+// select tasks
+type tagFilterFlags struct {
+	any  []string
+	all  []string
+	none []string
+}
+
+// register adds the tag filter flags to a command
+func (f *tagFilterFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&f.all, "tag", nil, "Only tasks with all of these tags (can be repeated)")
+	cmd.Flags().StringSliceVar(&f.any, "any-tag", nil, "Only tasks with at least one of these tags (can be repeated)")
+	cmd.Flags().StringSliceVar(&f.none, "not-tag", nil, "Only tasks without any of these tags (can be repeated)")
+}
+
+// filter returns the tag filter given by the flags
+func (f *tagFilterFlags) filter() (models.TagFilter, error) {
+	var filter models.TagFilter
+	var err error
+	if filter.Any, err = models.ParseTags(f.any); err != nil {
+		return filter, err
+	}
+	if filter.All, err = models.ParseTags(f.all); err != nil {
+		return filter, err
+	}
+	if filter.None, err = models.ParseTags(f.none); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user/todolist/internal/client"
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/output"
+	"github.com/user/todolist/internal/ui"
+)
+
+// watchHistory is how many recent events are shown below the list
+const watchHistory = 5
+
+var (
+	watchAll      bool
+	watchInterval time.Duration
+
+	watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Show a live task list",
+		Long: `Show the task list and keep it up to date as tasks are added, changed,
+completed or deleted, including by other clients. Reminders are shown as they
+come up. In server mode changes are pushed by the server; in local mode the
+list is refreshed every --interval. Press Ctrl+C to stop.
+
+With a structured --output format, a task_list document is written at the start
+and again every time the list changes.`,
+		Args: cobra.NoArgs,
+		RunE: runWatchCmd,
+		Example: `  todolist --server localhost:8080 watch
+  todolist watch --all
+  todolist watch -o json`,
+	}
+)
+
+func init() {
+	watchCmd.Flags().BoolVarP(&watchAll, "all", "a", false, "Show completed tasks too")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 2*time.Second, "Refresh interval in local mode")
+	structured(watchCmd)
+}
+
+func runWatchCmd(cmd *cobra.Command, args []string) error {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	// Server mode gets changes pushed, local mode polls
+	var eventCh <-chan events.Event
+	var ticker <-chan time.Time
+	if todoClient, ok := todoService.(*client.Client); ok {
+		var err error
+		eventCh, err = todoClient.Subscribe()
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to events: %w", err)
+		}
+	} else {
+		t := time.NewTicker(watchInterval)
+		defer t.Stop()
+		ticker = t.C
+	}
+
+	var history []string
+	var written []output.Task
+	refresh := true
+	for {
+		if refresh {
+			render := func() error { return renderWatch(history) }
+			if isStructured() {
+				render = func() error { return writeWatch(&written) }
+			}
+			if err := render(); err != nil {
+				return err
+			}
+		}
+		refresh = true
+
+		select {
+		case <-interrupt:
+			if !isStructured() {
+				fmt.Println()
+			}
+			return nil
+
+		case <-ticker:
+
+		case event, ok := <-eventCh:
+			if !ok {
+				return fmt.Errorf("lost connection to server")
+			}
+			if event.Type == events.PomodoroTick {
+				refresh = false
+				continue
+			}
+			history = append(history, describeEvent(event))
+			if len(history) > watchHistory {
+				history = history[len(history)-watchHistory:]
+			}
+			if event.Type == events.Reminder && !isStructured() {
+				fmt.Print("\a")
+			}
+		}
+	}
+}
+
+// renderWatch redraws the task list and the recent events
+func renderWatch(history []string) error {
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	ui.ClearScreen()
+	ui.PrintInfo("Watching tasks (updated %s, Ctrl+C to stop)", ui.Clock.Now().Format("15:04:05"))
+	fmt.Println()
+	ui.PrintTaskTree(tasks, watchAll, models.NewDependencies(tasks))
+
+	if len(history) > 0 {
+		fmt.Println()
+		fmt.Println("Recent activity:")
+		for _, line := range history {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
+
+// writeWatch writes the task list in a structured --output format if it has
+// changed since it was last written
+func writeWatch(written *[]output.Task) error {
+	tasks, err := todoService.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	var shown []*models.Task
+	for _, task := range tasks {
+		if watchAll || !task.Completed {
+			shown = append(shown, task)
+		}
+	}
+	sort.SliceStable(shown, func(i, j int) bool {
+		return shown[i].CreatedAt.Before(shown[j].CreatedAt)
+	})
+
+	converted := output.NewTasks(shown, projectNames())
+	if *written != nil && reflect.DeepEqual(converted, *written) {
+		return nil
+	}
+	*written = converted
+	return printOutput(output.KindTaskList, output.TaskList{Tasks: converted, Total: len(converted)}, output.TaskTable(converted))
+}
+
+// describeEvent returns a one-line summary of an event
+func describeEvent(event events.Event) string {
+	title := event.TaskID
+	if event.Task != nil {
+		title = event.Task.Title
+	} else if event.Pomodoro != nil {
+		title = event.Pomodoro.TaskTitle
+	}
+
+	when := event.Time.Format("15:04:05")
+	switch event.Type {
+	case events.TaskCreated:
+		return fmt.Sprintf("%s added: %s", when, title)
+	case events.TaskUpdated:
+		return fmt.Sprintf("%s updated: %s", when, title)
+	case events.TaskCompleted:
+		return fmt.Sprintf("%s completed: %s", when, title)
+	case events.TaskDeleted:
+		return fmt.Sprintf("%s deleted: %s", when, title)
+	case events.TasksRestored:
+		return fmt.Sprintf("%s tasks restored from backup", when)
+	case events.Reminder:
+		return fmt.Sprintf("%s %s", when, event.Message)
+	default:
+		return fmt.Sprintf("%s %s: %s", when, strings.ReplaceAll(string(event.Type), "_", " "), title)
+	}
+}
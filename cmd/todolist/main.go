@@ -1,24 +1,14 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"os"
 	"runtime/debug"
 
 	"github.com/user/todolist/cmd/todolist/cmd"
-	"github.com/user/todolist/internal/client"
 	"github.com/user/todolist/internal/ui"
 )
 
-var (
-	serverAddr string
-)
-
-func init() {
-	flag.StringVar(&serverAddr, "server", "localhost:8080", "Address of the TodoList server")
-}
-
 func main() {
 	// Set up panic recovery to prevent crashes
 	defer func() {
@@ -33,26 +23,8 @@ func main() {
 		}
 	}()
 
-	// Parse flags before cobra gets to them
-	flag.Parse()
-
-	// Remove the parsed flags from os.Args
-	os.Args = append(os.Args[:1], flag.Args()...)
-
-	// Connect to the server if specified
-	if serverAddr != "" {
-		todoClient, err := client.NewClient(serverAddr)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error connecting to server: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Falling back to local mode\n")
-		} else {
-			defer todoClient.Close()
-			cmd.InitializeWithClient(todoClient)
-		}
-	}
-
+	// Execute has already reported the error
 	if err := cmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -1,16 +1,19 @@
 package app
 
 import (
-	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/user/todolist/internal/clock"
+	"github.com/user/todolist/internal/events"
 	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/service"
 	"github.com/user/todolist/internal/storage"
-	"github.com/user/todolist/internal/ui"
 	"github.com/user/todolist/internal/utils"
 )
 
@@ -18,14 +21,38 @@ import (
 type App struct {
 	Storage storage.Storage
 	Config  *Config
+	Events  *events.Bus
+	Clock   clock.Clock
+
+	// mu serializes read-modify-write operations, see modifyTask
+	mu sync.Mutex
+
+	// pomodoro is the running Pomodoro session, guarded by pomodoroMu
+	pomodoroMu   sync.Mutex
+	pomodoro     *utils.PomodoroSession
+	stopPomodoro chan struct{}
 }
 
+// Ensure App satisfies the shared service interface
+var _ service.TaskService = (*App)(nil)
+
 // Config represents the application configuration
 type Config struct {
-	DataDir           string
-	StorageFile       string
-	BackupDir         string
-	DefaultCategories []models.Category
+	DataDir      string
+	StorageType  string
+	StorageFile  string
+	DatabaseFile string
+	BackupDir    string
+	ConfigFile   string
+
+	// Settings that can be changed in the config file
+	Focus utils.FocusWeights
+}
+
+// fileConfig is the layout of the config file. Settings left out keep their
+// defaults.
+type fileConfig struct {
+	Focus *utils.FocusWeights `json:"focus,omitempty"`
 }
 
 // DefaultConfig returns the default application configuration
@@ -38,19 +65,42 @@ func DefaultConfig() *Config {
 	dataDir := filepath.Join(homeDir, ".todolist")
 
 	return &Config{
-		DataDir:     dataDir,
-		StorageFile: filepath.Join(dataDir, "tasks.json"),
-		BackupDir:   filepath.Join(dataDir, "backups"),
-		DefaultCategories: []models.Category{
-			models.Category("work"),
-			models.Category("personal"),
-			models.Category("urgent"),
-			models.Category("health"),
-			models.Category("learning"),
-		},
+		DataDir:      dataDir,
+		StorageType:  storage.TypeJSON,
+		StorageFile:  filepath.Join(dataDir, "tasks.json"),
+		DatabaseFile: filepath.Join(dataDir, "tasks.db"),
+		BackupDir:    filepath.Join(dataDir, "backups"),
+		ConfigFile:   filepath.Join(dataDir, "config.json"),
+		Focus:        utils.DefaultFocusWeights(),
 	}
 }
 
+// SetDataDir points all data files at the given directory
+func (c *Config) SetDataDir(dir string) {
+	c.DataDir = dir
+	c.StorageFile = filepath.Join(dir, "tasks.json")
+	c.DatabaseFile = filepath.Join(dir, "tasks.db")
+	c.BackupDir = filepath.Join(dir, "backups")
+	c.ConfigFile = filepath.Join(dir, "config.json")
+}
+
+// LoadFile applies the settings in the config file, if there is one
+func (c *Config) LoadFile() error {
+	data, err := os.ReadFile(c.ConfigFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file := fileConfig{Focus: &c.Focus}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to decode config file %s: %w", c.ConfigFile, err)
+	}
+	return nil
+}
+
 // NewApp creates a new application instance
 func NewApp(config *Config) (*App, error) {
 	if config == nil {
@@ -67,22 +117,204 @@ func NewApp(config *Config) (*App, error) {
 		return nil, fmt.Errorf("failed to create backup directory: %w", err)
 	}
 
+	if err := config.LoadFile(); err != nil {
+		return nil, err
+	}
+
 	// Create storage
-	store := storage.NewJSONStorage(config.StorageFile)
-	if err := store.Initialize(); err != nil {
-		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	store, err := newStorage(config)
+	if err != nil {
+		return nil, err
 	}
 
-	return &App{
+	todoApp := &App{
 		Storage: store,
 		Config:  config,
-	}, nil
+		Clock:   clock.System,
+	}
+	todoApp.Events = events.NewBus(appClock{todoApp})
+	return todoApp, nil
+}
+
+// appClock is the clock of an app, following it when Clock is replaced after
+// the app is created
+type appClock struct {
+	app *App
+}
+
+func (c appClock) Now() time.Time { return c.app.Clock.Now() }
+
+func (c appClock) NewTicker(d time.Duration) clock.Ticker { return c.app.Clock.NewTicker(d) }
+
+func (c appClock) Sleep(d time.Duration) { c.app.Clock.Sleep(d) }
+
+// newStorage creates and initializes the storage backend selected in the config
+func newStorage(config *Config) (storage.Storage, error) {
+	switch config.StorageType {
+	case "", storage.TypeJSON:
+		store := storage.NewJSONStorage(config.StorageFile)
+		if err := store.Initialize(); err != nil {
+			return nil, fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		return store, nil
+
+	case storage.TypeSQLite:
+		// Only migrate from tasks.json when the database is created for the first time
+		_, statErr := os.Stat(config.DatabaseFile)
+		firstRun := os.IsNotExist(statErr)
+
+		store := storage.NewSQLiteStorage(config.DatabaseFile)
+		if err := store.Initialize(); err != nil {
+			return nil, fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		if firstRun {
+			if _, err := storage.ImportJSON(store, config.StorageFile); err != nil {
+				store.Close()
+				os.Remove(config.DatabaseFile)
+				return nil, fmt.Errorf("failed to migrate tasks from %s: %w", config.StorageFile, err)
+			}
+		}
+		return store, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage type: %s (must be %s or %s)", config.StorageType, storage.TypeJSON, storage.TypeSQLite)
+	}
 }
 
-// AddTask adds a new task
-func (a *App) AddTask(title, description string, priority models.Priority, category models.Category, dueDate, reminderAt time.Time) error {
-	task := models.NewTask(title, description, priority, category, dueDate, reminderAt)
-	return a.Storage.AddTask(task)
+// AddTask adds a new task and returns it
+func (a *App) AddTask(spec models.TaskSpec) (*models.Task, error) {
+	if spec.Recurrence != nil {
+		if err := spec.Recurrence.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := models.ParseTags(spec.Tags)
+	if err != nil {
+		return nil, err
+	}
+	spec.Tags = tags
+
+	if err := a.validateSpecBlockers(spec); err != nil {
+		return nil, err
+	}
+
+	project, err := a.resolveProject(spec.Project)
+	if err != nil {
+		return nil, err
+	}
+	spec.Project = project
+
+	task := models.NewTaskFromSpec(spec, a.Clock.Now())
+	if err := a.Storage.AddTask(task); err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskCreated, task)
+	return task, nil
+}
+
+// AddTasks adds several tasks at once. All specs are validated before any
+// task is added.
+func (a *App) AddTasks(specs []models.TaskSpec) ([]*models.Task, error) {
+	for i, spec := range specs {
+		if strings.TrimSpace(spec.Title) == "" {
+			return nil, models.Invalidf("task %d has an empty title", i+1)
+		}
+		if spec.Recurrence != nil {
+			if err := spec.Recurrence.Validate(); err != nil {
+				return nil, fmt.Errorf("task %d: %w", i+1, err)
+			}
+		}
+		tags, err := models.ParseTags(spec.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		specs[i].Tags = tags
+		if err := a.validateSpecBlockers(spec); err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		project, err := a.resolveProject(spec.Project)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		specs[i].Project = project
+	}
+
+	tasks := make([]*models.Task, 0, len(specs))
+	for _, spec := range specs {
+		task := models.NewTaskFromSpec(spec, a.Clock.Now())
+		if err := a.Storage.AddTask(task); err != nil {
+			return tasks, fmt.Errorf("failed to add task '%s': %w", spec.Title, err)
+		}
+		a.Events.PublishTask(events.TaskCreated, task)
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// AddSubtask adds a new task nested under an existing parent task. Unless
+// given another project, the subtask belongs to the parent's project.
+func (a *App) AddSubtask(parentID string, spec models.TaskSpec) (*models.Task, error) {
+	parent, err := a.Storage.GetTask(parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Recurrence != nil {
+		if err := spec.Recurrence.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.Tags, err = models.ParseTags(spec.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := a.validateSpecBlockers(spec); err != nil {
+		return nil, err
+	}
+
+	if spec.Project == "" {
+		spec.Project = parent.Project
+	} else if spec.Project, err = a.resolveProject(spec.Project); err != nil {
+		return nil, err
+	}
+
+	task := models.NewTaskFromSpec(spec, a.Clock.Now())
+	task.ParentID = parentID
+	if err := a.Storage.AddTask(task); err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskCreated, task)
+	return task, nil
+}
+
+// MoveTask moves a task under a new parent. An empty parent ID moves the task
+// to the top level.
+func (a *App) MoveTask(id, parentID string) error {
+	if parentID != "" {
+		if parentID == id {
+			return models.Invalidf("a task cannot be its own parent")
+		}
+
+		if _, err := a.Storage.GetTask(parentID); err != nil {
+			return err
+		}
+	}
+
+	task, err := a.modifyTaskWithAll(id, func(task *models.Task, tasks []*models.Task) error {
+		if parentID != "" && models.IsDescendant(tasks, id, parentID) {
+			return models.Invalidf("cannot move a task under one of its own subtasks")
+		}
+		task.ParentID = parentID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return nil
 }
 
 // GetTask retrieves a task by ID
@@ -105,30 +337,234 @@ func (a *App) GetTasksByPriority(priority models.Priority) ([]*models.Task, erro
 	return a.Storage.GetTasksByPriority(priority)
 }
 
-// UpdateTask updates an existing task
+// UpdateTask replaces an existing task
 func (a *App) UpdateTask(task *models.Task) error {
-	return a.Storage.UpdateTask(task)
+	updated, err := a.modifyTask(task.ID, func(stored *models.Task) error {
+		*stored = *task.Clone()
+		// Older clients only know the Completed flag
+		stored.MigrateStatus()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.Events.PublishTask(events.TaskUpdated, updated)
+	return nil
+}
+
+// PatchTask applies a partial update to a task and returns the updated task
+func (a *App) PatchTask(id string, patch models.TaskPatch) (*models.Task, error) {
+	if patch.Project != nil {
+		project, err := a.resolveProject(*patch.Project)
+		if err != nil {
+			return nil, err
+		}
+		patch.Project = &project
+	}
+
+	task, err := a.modifyTask(id, patch.Apply)
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
 }
 
-// DeleteTask deletes a task by ID
+// modifyTask applies fn to a copy of the stored task and saves the result.
+// The storage keeps other processes out while it does, and calls are
+// serialized so concurrent changes to one task are not lost.
+func (a *App) modifyTask(id string, fn func(task *models.Task) error) (*models.Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.Storage.ModifyTask(id, fn)
+}
+
+// modifyTaskWithAll is modifyTask for changes that must be checked against
+// the other tasks. fn also gets all tasks as read under the same lock, so no
+// other change of the app can slip in between the check and the write.
+func (a *App) modifyTaskWithAll(id string, fn func(task *models.Task, tasks []*models.Task) error) (*models.Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	return a.Storage.ModifyTask(id, func(task *models.Task) error {
+		return fn(task, tasks)
+	})
+}
+
+// DeleteTask deletes a task by ID together with all of its subtasks
 func (a *App) DeleteTask(id string) error {
-	return a.Storage.DeleteTask(id)
+	deleted, err := a.deleteTaskTree(id)
+	for _, task := range deleted {
+		a.Events.PublishTask(events.TaskDeleted, task)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Tasks waiting for a deleted task no longer have to
+	ids := make([]string, 0, len(deleted))
+	for _, task := range deleted {
+		ids = append(ids, task.ID)
+	}
+	return a.releaseDependents(ids)
 }
 
-// CompleteTask marks a task as completed
+// deleteTaskTree deletes a task and its subtasks under the lock that
+// modifyTask takes, and returns the tasks it deleted
+func (a *App) deleteTaskTree(id string) ([]*models.Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	task, err := a.Storage.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	// Delete the deepest subtasks first so no orphans are left behind on failure
+	var deleted []*models.Task
+	descendants := models.Descendants(tasks, id)
+	for i := len(descendants) - 1; i >= 0; i-- {
+		if err := a.Storage.DeleteTask(descendants[i].ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete subtask: %w", err)
+		}
+		deleted = append(deleted, descendants[i])
+	}
+
+	if err := a.Storage.DeleteTask(id); err != nil {
+		return deleted, err
+	}
+	return append(deleted, task), nil
+}
+
+// CompleteTask marks a task as completed. Completing a parent task also
+// completes all of its open subtasks, completing a recurring task schedules
+// its next occurrence, and tasks that were blocked by a completed task are
+// unblocked.
 func (a *App) CompleteTask(id string) error {
+	_, err := a.closeTask(id, models.StatusDone)
+	return err
+}
+
+// SetTaskStatus moves a task to a new status. Closing a task as done or
+// cancelled works like completing it, including its open subtasks; moving a
+// closed task to an open status reopens it.
+func (a *App) SetTaskStatus(id string, status models.Status) (*models.Task, error) {
+	if _, err := models.ParseStatus(string(status)); err != nil {
+		return nil, err
+	}
+
+	if status.IsClosed() {
+		return a.closeTask(id, status)
+	}
+
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.SetStatus(status, a.Clock.Now())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
+
+// ReopenTask moves a done or cancelled task back to the to-do list
+func (a *App) ReopenTask(id string) (*models.Task, error) {
 	task, err := a.Storage.GetTask(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if !task.Completed {
+		return nil, models.Invalidf("task '%s' is not done or cancelled", task.Title)
+	}
+	return a.SetTaskStatus(id, models.StatusTodo)
+}
+
+// closeTask moves a task and its open subtasks to a closed status, schedules
+// the next occurrence of a recurring task and unblocks the tasks that waited
+// for them
+func (a *App) closeTask(id string, status models.Status) (*models.Task, error) {
+	now := a.Clock.Now()
+	var previous models.Status
+	var wasClosed bool
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.MigrateStatus()
+		previous, wasClosed = task.Status, task.Completed
+		task.SetStatus(status, now)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if wasClosed {
+		// Switching between done and cancelled only relabels the task
+		if previous != status {
+			a.Events.PublishTask(events.TaskUpdated, task)
+		}
+		return task, nil
+	}
+
+	eventType := events.TaskUpdated
+	if status == models.StatusDone {
+		eventType = events.TaskCompleted
+	}
+	a.Events.PublishTask(eventType, task)
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	task.MarkComplete()
-	return a.Storage.UpdateTask(task)
+	closed := []string{id}
+	for _, subtask := range models.Descendants(tasks, id) {
+		if subtask.Completed {
+			continue
+		}
+
+		changed := false
+		subtask, err := a.modifyTask(subtask.ID, func(subtask *models.Task) error {
+			if !subtask.Completed {
+				subtask.SetStatus(status, now)
+				changed = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to close subtask: %w", err)
+		}
+		if changed {
+			closed = append(closed, subtask.ID)
+			a.Events.PublishTask(eventType, subtask)
+		}
+	}
+
+	// Schedule the next occurrence of a recurring task
+	if next := models.NextOccurrence(task, now); next != nil {
+		if err := a.Storage.AddTask(next); err != nil {
+			return nil, fmt.Errorf("failed to schedule next occurrence: %w", err)
+		}
+		a.Events.PublishTask(events.TaskCreated, next)
+	}
+
+	if err := a.releaseDependents(closed); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
 // BackupTasks creates a backup of the tasks
 func (a *App) BackupTasks() (string, error) {
-	timestamp := time.Now().Format("20060102-150405")
+	timestamp := a.Clock.Now().Format("20060102-150405")
 	backupFile := filepath.Join(a.Config.BackupDir, fmt.Sprintf("tasks-backup-%s.json", timestamp))
 
 	if err := a.Storage.Backup(backupFile); err != nil {
@@ -140,7 +576,11 @@ func (a *App) BackupTasks() (string, error) {
 
 // RestoreTasks restores tasks from a backup
 func (a *App) RestoreTasks(backupFile string) error {
-	return a.Storage.Restore(backupFile)
+	if err := a.Storage.Restore(backupFile); err != nil {
+		return err
+	}
+	a.Events.Publish(events.Event{Type: events.TasksRestored})
+	return nil
 }
 
 // ListBackups lists available backups
@@ -160,81 +600,92 @@ func (a *App) ListBackups() ([]string, error) {
 	return backups, nil
 }
 
-// BrainDump enters brain dump mode for quickly adding multiple tasks
-func (a *App) BrainDump() error {
-	scanner := bufio.NewScanner(os.Stdin)
-	count := 0
-
-	for {
-		fmt.Print("> ")
-		if !scanner.Scan() {
-			break
-		}
-
-		title := strings.TrimSpace(scanner.Text())
-		if title == "" {
-			break
-		}
-
-		// Handle special commands
-		if title == "q" || title == "quit" || title == "exit" {
-			ui.PrintInfo("Exiting brain dump mode")
-			break
-		}
-
-		if title == "help" || title == "--help" || title == "-h" {
-			ui.PrintInfo("Brain Dump Mode Help:")
-			ui.PrintInfo("- Enter task titles one per line")
-			ui.PrintInfo("- Leave a line empty to finish")
-			ui.PrintInfo("- Type 'q', 'quit', or 'exit' to exit")
-			ui.PrintInfo("- Press Ctrl+C to exit at any time")
-			continue
-		}
-
-		task := models.NewTask(title, "", models.PriorityMedium, models.Category("inbox"), time.Time{}, time.Time{})
-		if err := a.Storage.AddTask(task); err != nil {
-			return fmt.Errorf("failed to add task: %w", err)
+// FocusMode suggests the tasks to work on next, best first, with the factors
+// that make up their scores
+func (a *App) FocusMode(options models.FocusOptions) ([]models.TaskScore, error) {
+	if options.Limit < 0 {
+		return nil, models.Invalidf("the number of suggestions must not be negative")
+	}
+	if options.Available < 0 {
+		return nil, models.Invalidf("the time available must not be negative")
+	}
+	if options.Energy != "" {
+		if _, err := models.ParseEnergy(string(options.Energy)); err != nil {
+			return nil, err
 		}
-
-		count++
-		ui.PrintSuccess("Added: %s", title)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading input: %w", err)
+	project, err := a.resolveProject(options.Project)
+	if err != nil {
+		return nil, err
 	}
+	options.Project = project
 
-	ui.PrintSuccess("Added %d tasks to your inbox.", count)
-	return nil
-}
-
-// FocusMode enters focus mode to suggest the next task to work on
-func (a *App) FocusMode() (*models.Task, error) {
 	tasks, err := a.GetAllTasks()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	nextTask := utils.GetNextFocusTask(tasks)
-	return nextTask, nil
+	archived, err := a.archivedProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks in archived projects are on ice, but may still block other tasks,
+	// so they are only dropped from the suggestions
+	limit := options.Limit
+	options.Limit = 0
+	suggestions := make([]models.TaskScore, 0)
+	for _, suggestion := range utils.FocusMode(tasks, a.Clock.Now(), a.Config.Focus, options) {
+		if !archived[suggestion.Task.Project] {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
 }
 
-// StartPomodoro starts a Pomodoro timer for a task
-func (a *App) StartPomodoro(taskID string, customDuration time.Duration) error {
-	task, err := a.GetTask(taskID)
-	if err != nil {
-		return err
+// SkipFocusTask records that a suggested task was skipped, with an optional
+// reason, and keeps it out of focus suggestions until the given time, if any
+func (a *App) SkipFocusTask(id, reason string, until time.Time) (*models.Task, error) {
+	now := a.Clock.Now()
+	if !until.IsZero() && !until.After(now) {
+		return nil, models.Invalidf("skip time must be in the future")
 	}
 
-	config := utils.DefaultPomodoroConfig()
-	config.TaskName = task.Title
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.SkipFocus(now, strings.TrimSpace(reason), until)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
 
-	if customDuration > 0 {
-		config.WorkDuration = customDuration
+// SnoozeFocusTask keeps a task out of focus suggestions until the given time.
+// The zero time ends the snooze.
+func (a *App) SnoozeFocusTask(id string, until time.Time) (*models.Task, error) {
+	if !until.IsZero() && !until.After(a.Clock.Now()) {
+		return nil, models.Invalidf("snooze time must be in the future")
 	}
 
-	session := utils.NewPomodoroSession(config)
-	session.Start()
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.SnoozeFocus(until)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
 
-	return nil
+// Close releases resources held by the application
+func (a *App) Close() error {
+	a.StopPomodoro("closed")
+	return a.Storage.Close()
 }
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/clock"
+	"github.com/user/todolist/internal/models"
+)
+
+// newTestApp returns an app working on a fresh data directory and driven by
+// the given clock
+func newTestApp(t *testing.T, clk clock.Clock) *App {
+	t.Helper()
+	config := DefaultConfig()
+	config.SetDataDir(t.TempDir())
+	todoApp, err := NewApp(config)
+	if err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	todoApp.Clock = clk
+	t.Cleanup(func() { todoApp.Close() })
+	return todoApp
+}
+
+// TestConcurrentWrites runs every kind of task write at the same time as tag
+// changes to the same tasks and checks no tag is lost
+func TestConcurrentWrites(t *testing.T) {
+	todoApp := newTestApp(t, clock.NewFake(time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)))
+
+	parent, err := todoApp.AddTask(models.TaskSpec{Title: "Parent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := todoApp.AddSubtask(parent.ID, models.TaskSpec{Title: "Child"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := todoApp.AddTask(models.TaskSpec{Title: "Other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const updates = 10
+	var wg sync.WaitGroup
+	for _, id := range []string{parent.ID, child.ID} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			for i := 0; i < updates; i++ {
+				if _, err := todoApp.AddTags(id, []string{fmt.Sprintf("t%d", i)}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(id)
+	}
+
+	writes := []func() error{
+		func() error { return todoApp.MoveTask(child.ID, other.ID) },
+		func() error { return todoApp.MoveTask(child.ID, parent.ID) },
+		func() error { return todoApp.CompleteTask(parent.ID) },
+		func() error { _, err := todoApp.SetTaskStatus(parent.ID, models.StatusCancelled); return err },
+	}
+	for _, write := range writes {
+		wg.Add(1)
+		go func(write func() error) {
+			defer wg.Done()
+			if err := write(); err != nil {
+				t.Error(err)
+			}
+		}(write)
+	}
+	wg.Wait()
+
+	for _, id := range []string{parent.ID, child.ID} {
+		task, err := todoApp.GetTask(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(task.Tags) != updates {
+			t.Errorf("task %s has tags %v, want %d tags", task.Title, task.Tags, updates)
+		}
+		if !task.Completed {
+			t.Errorf("task %s is %s, want it closed", task.Title, task.Status)
+		}
+	}
+}
+
+func TestUpdateTaskMigratesStatus(t *testing.T) {
+	todoApp := newTestApp(t, clock.System)
+	added, err := todoApp.AddTask(models.TaskSpec{Title: "Draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := *added
+	update.Title = "Final"
+	update.Status = ""
+	if err := todoApp.UpdateTask(&update); err != nil {
+		t.Fatal(err)
+	}
+	task, err := todoApp.GetTask(added.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != "Final" || task.Status != models.StatusTodo {
+		t.Errorf("stored task is %q with status %q", task.Title, task.Status)
+	}
+}
+
+func TestAddNormalizesTags(t *testing.T) {
+	todoApp := newTestApp(t, clock.System)
+	tags := []string{"+Work", "work", " Home "}
+	want := []string{"work", "home"}
+
+	task, err := todoApp.AddTask(models.TaskSpec{Title: "Task", Tags: tags})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subtask, err := todoApp.AddSubtask(task.ID, models.TaskSpec{Title: "Subtask", Tags: tags})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, err := todoApp.AddTasks([]models.TaskSpec{{Title: "Batch", Tags: tags}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, added := range []*models.Task{task, subtask, batch[0]} {
+		stored, err := todoApp.GetTask(added.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(stored.Tags, want) {
+			t.Errorf("%s has tags %q, want %q", stored.Title, stored.Tags, want)
+		}
+	}
+
+	invalid := []string{"two words"}
+	if _, err := todoApp.AddTask(models.TaskSpec{Title: "Bad", Tags: invalid}); err == nil {
+		t.Error("AddTask with an invalid tag succeeded")
+	}
+	if _, err := todoApp.AddSubtask(task.ID, models.TaskSpec{Title: "Bad", Tags: invalid}); err == nil {
+		t.Error("AddSubtask with an invalid tag succeeded")
+	}
+	if _, err := todoApp.AddTasks([]models.TaskSpec{{Title: "Good"}, {Title: "Bad", Tags: invalid}}); err == nil {
+		t.Error("AddTasks with an invalid tag succeeded")
+	}
+}
+
+// TestEventsUseAppClock checks that events are stamped by the clock of the
+// app, also when it is set after the app was created
+func TestEventsUseAppClock(t *testing.T) {
+	now := time.Date(2025, time.March, 12, 9, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	todoApp := newTestApp(t, clk)
+	eventCh, unsubscribe := todoApp.Events.Subscribe()
+	defer unsubscribe()
+
+	task, err := todoApp.AddTask(models.TaskSpec{Title: "Stamped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(time.Minute)
+	if _, err := todoApp.StartPomodoro(task.ID, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	defer todoApp.StopPomodoro("")
+
+	for _, want := range []time.Time{now, now.Add(time.Minute)} {
+		event := <-eventCh
+		if !event.Time.Equal(want) {
+			t.Errorf("%s event stamped %s, want %s", event.Type, event.Time, want)
+		}
+	}
+}
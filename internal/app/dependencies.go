@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+)
+
+// BlockTask records that a task cannot start until another task is completed
+func (a *App) BlockTask(id, blockerID string) (*models.Task, error) {
+	if id == blockerID {
+		return nil, models.Invalidf("a task cannot be blocked by itself")
+	}
+
+	task, err := a.modifyTaskWithAll(id, func(task *models.Task, tasks []*models.Task) error {
+		if task.IsBlockedBy(blockerID) {
+			return nil
+		}
+		if err := validateBlockers(tasks, id, []string{blockerID}); err != nil {
+			return err
+		}
+		task.BlockedBy = append(append([]string(nil), task.BlockedBy...), blockerID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
+
+// UnblockTask removes a "blocked by" link from a task. An empty blocker ID
+// removes all of the task's blockers.
+func (a *App) UnblockTask(id, blockerID string) (*models.Task, error) {
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		if blockerID == "" {
+			task.BlockedBy = nil
+			return nil
+		}
+		if !task.RemoveBlocker(blockerID) {
+			return models.Invalidf("task %s is not blocked by %s", id, blockerID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
+
+// validateBlockers checks that the given tasks exist, are still open and can
+// block the task with the given ID without creating a cycle
+func validateBlockers(tasks []*models.Task, id string, blockerIDs []string) error {
+	deps := models.NewDependencies(tasks)
+	byID := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	for _, blockerID := range blockerIDs {
+		blocker, ok := byID[blockerID]
+		if !ok {
+			return models.Invalidf("blocking task not found: %s", blockerID)
+		}
+		if blocker.Completed {
+			return models.Invalidf("blocking task '%s' is already completed", blocker.Title)
+		}
+		if deps.CreatesCycle(id, blockerID) {
+			return models.Invalidf("'%s' already waits for this task, so it cannot block it", blocker.Title)
+		}
+	}
+	return nil
+}
+
+// validateSpecBlockers checks the blockers of a task that is about to be added
+func (a *App) validateSpecBlockers(spec models.TaskSpec) error {
+	if len(spec.BlockedBy) == 0 {
+		return nil
+	}
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	// A new task has no dependents yet, so it cannot be part of a cycle
+	return validateBlockers(tasks, "", spec.BlockedBy)
+}
+
+// releaseDependents removes the given tasks from the blockers of the tasks
+// that wait for them
+func (a *App) releaseDependents(ids []string) error {
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		waiting := false
+		for _, id := range ids {
+			waiting = waiting || task.IsBlockedBy(id)
+		}
+		if !waiting {
+			continue
+		}
+
+		changed := false
+		updated, err := a.modifyTask(task.ID, func(task *models.Task) error {
+			for _, id := range ids {
+				if task.RemoveBlocker(id) {
+					changed = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update dependent task: %w", err)
+		}
+		if changed {
+			a.Events.PublishTask(events.TaskUpdated, updated)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/clock"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/storage"
+)
+
+// TestReleaseDependentsKeepsConcurrentChanges completes blockers while the
+// task waiting for them is being tagged, and checks both changes survive
+func TestReleaseDependentsKeepsConcurrentChanges(t *testing.T) {
+	todoApp := newTestApp(t, clock.System)
+
+	var blockers []string
+	for i := 0; i < 5; i++ {
+		blocker := mustAddTask(t, todoApp, models.TaskSpec{Title: fmt.Sprintf("Blocker %d", i)})
+		blockers = append(blockers, blocker.ID)
+	}
+	dependent := mustAddTask(t, todoApp, models.TaskSpec{Title: "Dependent", BlockedBy: blockers})
+
+	const updates = 20
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < updates; i++ {
+			if _, err := todoApp.AddTags(dependent.ID, []string{fmt.Sprintf("t%d", i)}); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	for _, id := range blockers {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if err := todoApp.CompleteTask(id); err != nil {
+				t.Error(err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	task, err := todoApp.GetTask(dependent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(task.BlockedBy) != 0 {
+		t.Errorf("task is still blocked by %v", task.BlockedBy)
+	}
+	if len(task.Tags) != updates {
+		t.Errorf("task has %d tags, want %d", len(task.Tags), updates)
+	}
+}
+
+// rendezvousStorage holds up GetAllTasks until a second call arrives or a
+// short wait passes, so two operations read the tasks at the same moment if
+// nothing keeps them apart
+type rendezvousStorage struct {
+	storage.Storage
+	arrived chan struct{}
+}
+
+func (s *rendezvousStorage) GetAllTasks() ([]*models.Task, error) {
+	tasks, err := s.Storage.GetAllTasks()
+	select {
+	case s.arrived <- struct{}{}:
+	case <-s.arrived:
+	case <-time.After(50 * time.Millisecond):
+	}
+	return tasks, err
+}
+
+// TestConcurrentLinksCannotFormCycle blocks two tasks by each other, and
+// moves two tasks under each other, at the same time; only one of each pair
+// may succeed
+func TestConcurrentLinksCannotFormCycle(t *testing.T) {
+	todoApp := newTestApp(t, clock.System)
+	todoApp.Storage = &rendezvousStorage{Storage: todoApp.Storage, arrived: make(chan struct{})}
+
+	links := []struct {
+		name string
+		link func(id, otherID string) error
+	}{
+		{"block", func(id, otherID string) error { _, err := todoApp.BlockTask(id, otherID); return err }},
+		{"move", todoApp.MoveTask},
+	}
+	for _, link := range links {
+		a := mustAddTask(t, todoApp, models.TaskSpec{Title: "A"})
+		b := mustAddTask(t, todoApp, models.TaskSpec{Title: "B"})
+
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i, pair := range [][2]string{{a.ID, b.ID}, {b.ID, a.ID}} {
+			wg.Add(1)
+			go func(i int, pair [2]string) {
+				defer wg.Done()
+				errs[i] = link.link(pair[0], pair[1])
+			}(i, pair)
+		}
+		wg.Wait()
+
+		if (errs[0] == nil) == (errs[1] == nil) {
+			t.Errorf("%s: linking A and B both ways got errors %v and %v, want exactly one to fail", link.name, errs[0], errs[1])
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/clock"
+	"github.com/user/todolist/internal/models"
+)
+
+// focusStart is Wednesday 12 March 2025, 9:00
+var focusStart = time.Date(2025, time.March, 12, 9, 0, 0, 0, time.UTC)
+
+func mustAddTask(t *testing.T, todoApp *App, spec models.TaskSpec) *models.Task {
+	t.Helper()
+	task, err := todoApp.AddTask(spec)
+	if err != nil {
+		t.Fatalf("AddTask(%q) failed: %v", spec.Title, err)
+	}
+	return task
+}
+
+// scores returns the titles and scores of the focus suggestions, best first
+func scores(t *testing.T, todoApp *App, options models.FocusOptions) ([]string, []float64) {
+	t.Helper()
+	suggestions, err := todoApp.FocusMode(options)
+	if err != nil {
+		t.Fatalf("FocusMode failed: %v", err)
+	}
+	titles := make([]string, len(suggestions))
+	points := make([]float64, len(suggestions))
+	for i, suggestion := range suggestions {
+		titles[i] = suggestion.Task.Title
+		points[i] = suggestion.Score
+	}
+	return titles, points
+}
+
+func equalScores(titles []string, points []float64, wantTitles []string, wantPoints []float64) bool {
+	if len(titles) != len(wantTitles) || len(points) != len(wantPoints) {
+		return false
+	}
+	for i := range titles {
+		if titles[i] != wantTitles[i] || points[i] != wantPoints[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFocusScoring(t *testing.T) {
+	clk := clock.NewFake(focusStart)
+	todoApp := newTestApp(t, clk)
+
+	added := mustAddTask(t, todoApp, models.TaskSpec{Title: "Plan roadmap", Priority: models.PriorityHigh})
+	if !added.CreatedAt.Equal(focusStart) {
+		t.Errorf("task created at %s, want the time of the clock %s", added.CreatedAt, focusStart)
+	}
+	mustAddTask(t, todoApp, models.TaskSpec{Title: "Pay bill", Priority: models.PriorityLow, DueDate: focusStart.Add(2 * time.Hour)})
+	mustAddTask(t, todoApp, models.TaskSpec{Title: "Write report", Priority: models.PriorityMedium, DueDate: focusStart.Add(60 * time.Hour)})
+	done := mustAddTask(t, todoApp, models.TaskSpec{Title: "Done already", Priority: models.PriorityHigh})
+	if err := todoApp.CompleteTask(done.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		after  time.Duration
+		titles []string
+		points []float64
+	}{
+		// low 25 + due within a day 150; medium 50 + due within 3 days 75; high 100
+		{0, []string{"Pay bill", "Write report", "Plan roadmap"}, []float64{175, 125, 100}},
+		// Three days on everything has aged by 6 and both due tasks are overdue
+		{72 * time.Hour, []string{"Write report", "Pay bill", "Plan roadmap"}, []float64{256, 231, 106}},
+	}
+	for _, tt := range tests {
+		clk.Set(focusStart.Add(tt.after))
+		titles, points := scores(t, todoApp, models.FocusOptions{})
+		if !equalScores(titles, points, tt.titles, tt.points) {
+			t.Errorf("after %s got %v %v, want %v %v", tt.after, titles, points, tt.titles, tt.points)
+		}
+	}
+}
+
+func TestFocusOptions(t *testing.T) {
+	todoApp := newTestApp(t, clock.NewFake(focusStart))
+
+	mustAddTask(t, todoApp, models.TaskSpec{Title: "Deep work", Priority: models.PriorityHigh, Energy: models.EnergyHigh, Estimate: 2 * time.Hour})
+	mustAddTask(t, todoApp, models.TaskSpec{Title: "Reply to email", Priority: models.PriorityMedium, Energy: models.EnergyLow, Estimate: 15 * time.Minute})
+	mustAddTask(t, todoApp, models.TaskSpec{Title: "Tidy desk", Priority: models.PriorityLow})
+
+	tests := []struct {
+		name    string
+		options models.FocusOptions
+		titles  []string
+		points  []float64
+	}{
+		{"all", models.FocusOptions{}, []string{"Deep work", "Reply to email", "Tidy desk"}, []float64{100, 50, 25}},
+		{"limit", models.FocusOptions{Limit: 1}, []string{"Deep work"}, []float64{100}},
+		// Matching energy and fitting in the time add 30 each
+		{"low energy", models.FocusOptions{Energy: models.EnergyLow}, []string{"Reply to email", "Tidy desk"}, []float64{80, 25}},
+		{"half an hour", models.FocusOptions{Available: 30 * time.Minute}, []string{"Reply to email", "Tidy desk"}, []float64{80, 25}},
+	}
+	for _, tt := range tests {
+		titles, points := scores(t, todoApp, tt.options)
+		if !equalScores(titles, points, tt.titles, tt.points) {
+			t.Errorf("%s: got %v %v, want %v %v", tt.name, titles, points, tt.titles, tt.points)
+		}
+	}
+
+	if _, err := todoApp.FocusMode(models.FocusOptions{Limit: -1}); err == nil {
+		t.Error("FocusMode with a negative limit succeeded")
+	}
+}
+
+func TestFocusSnoozeAndSkip(t *testing.T) {
+	clk := clock.NewFake(focusStart)
+	todoApp := newTestApp(t, clk)
+
+	first := mustAddTask(t, todoApp, models.TaskSpec{Title: "First", Priority: models.PriorityHigh})
+	mustAddTask(t, todoApp, models.TaskSpec{Title: "Second", Priority: models.PriorityLow})
+
+	if _, err := todoApp.SnoozeFocusTask(first.ID, focusStart.Add(-time.Minute)); err == nil {
+		t.Error("snoozing until a past time succeeded")
+	}
+	if _, err := todoApp.SnoozeFocusTask(first.ID, focusStart.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if titles, _ := scores(t, todoApp, models.FocusOptions{}); len(titles) != 1 || titles[0] != "Second" {
+		t.Errorf("while snoozed got %v, want only Second", titles)
+	}
+
+	// The snooze ends with the hour
+	clk.Advance(time.Hour)
+	if titles, _ := scores(t, todoApp, models.FocusOptions{}); len(titles) != 2 || titles[0] != "First" {
+		t.Errorf("after the snooze got %v, want First back on top", titles)
+	}
+
+	skipped, err := todoApp.SkipFocusTask(first.ID, " too tired ", clk.Now().Add(30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped.FocusSkipCount() != 1 {
+		t.Fatalf("task was skipped %d times, want 1", skipped.FocusSkipCount())
+	}
+	skip := skipped.Focus.Skips[0]
+	if !skip.At.Equal(clk.Now()) || skip.Reason != "too tired" {
+		t.Errorf("skip recorded at %s for %q, want %s for %q", skip.At, skip.Reason, clk.Now(), "too tired")
+	}
+	if titles, _ := scores(t, todoApp, models.FocusOptions{}); len(titles) != 1 {
+		t.Errorf("right after skipping got %v, want only Second", titles)
+	}
+	clk.Advance(30 * time.Minute)
+	if titles, _ := scores(t, todoApp, models.FocusOptions{}); len(titles) != 2 {
+		t.Errorf("after the skip ended got %v, want both tasks", titles)
+	}
+}
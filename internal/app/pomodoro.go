@@ -0,0 +1,201 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/utils"
+)
+
+// StartPomodoro starts a Pomodoro session for a task. The session runs in the
+// background for the given number of work cycles, or until it is stopped if
+// cycles is zero; its progress is published on the event bus.
+func (a *App) StartPomodoro(taskID string, customDuration time.Duration, cycles int) (*models.PomodoroStatus, error) {
+	if cycles < 0 {
+		return nil, models.Invalidf("the number of cycles must not be negative")
+	}
+
+	task, err := a.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.pomodoroMu.Lock()
+	defer a.pomodoroMu.Unlock()
+
+	if a.pomodoro != nil {
+		if status := a.pomodoro.Status(a.Clock.Now()); status.Active {
+			return nil, models.Invalidf("a pomodoro session is already running for '%s' (stop it first)", status.TaskTitle)
+		}
+	}
+
+	config := utils.DefaultPomodoroConfig()
+	config.TaskID = task.ID
+	config.TaskName = task.Title
+
+	if customDuration > 0 {
+		config.WorkDuration = customDuration
+	}
+	config.Cycles = cycles
+
+	session := utils.NewPomodoroSession(config, a.Clock)
+	session.OnWorkEnd = func(record models.PomodoroRecord) {
+		if err := a.recordPomodoro(&record); err != nil {
+			log.Printf("Failed to record pomodoro for task %s: %v", record.TaskID, err)
+		}
+	}
+	stop := make(chan struct{})
+	a.pomodoro = session
+	a.stopPomodoro = stop
+
+	go session.Run(stop,
+		func(status models.PomodoroStatus) { a.Events.PublishPomodoro(events.PomodoroTick, status) },
+		func(status models.PomodoroStatus) { a.Events.PublishPomodoro(events.PomodoroPhase, status) },
+		func(status models.PomodoroStatus) { a.endPomodoro(session, status) },
+	)
+
+	status := session.Status(a.Clock.Now())
+	a.Events.PublishPomodoro(events.PomodoroStarted, status)
+	return &status, nil
+}
+
+// PomodoroStatus returns the state of the current Pomodoro session
+func (a *App) PomodoroStatus() (*models.PomodoroStatus, error) {
+	a.pomodoroMu.Lock()
+	defer a.pomodoroMu.Unlock()
+
+	if a.pomodoro == nil {
+		return &models.PomodoroStatus{}, nil
+	}
+
+	status := a.pomodoro.Status(a.Clock.Now())
+	return &status, nil
+}
+
+// PausePomodoro pauses the current Pomodoro session
+func (a *App) PausePomodoro() (*models.PomodoroStatus, error) {
+	return a.controlPomodoro(events.PomodoroPaused, (*utils.PomodoroSession).Pause)
+}
+
+// ResumePomodoro resumes a paused Pomodoro session
+func (a *App) ResumePomodoro() (*models.PomodoroStatus, error) {
+	return a.controlPomodoro(events.PomodoroResumed, (*utils.PomodoroSession).Resume)
+}
+
+// SkipPomodoroPhase ends the current work or break phase early
+func (a *App) SkipPomodoroPhase() (*models.PomodoroStatus, error) {
+	return a.controlPomodoro(events.PomodoroPhase, (*utils.PomodoroSession).Skip)
+}
+
+// StopPomodoro ends the current Pomodoro session and returns its final state.
+// A work phase in progress is recorded as interrupted for the given reason.
+func (a *App) StopPomodoro(reason string) (*models.PomodoroStatus, error) {
+	a.pomodoroMu.Lock()
+	defer a.pomodoroMu.Unlock()
+
+	if a.pomodoro == nil {
+		return nil, models.Invalidf("no pomodoro session is running")
+	}
+
+	if reason == "" {
+		reason = "stopped"
+	}
+
+	session := a.pomodoro
+	session.Stop(a.Clock.Now(), reason)
+	close(a.stopPomodoro)
+	a.pomodoro = nil
+	a.stopPomodoro = nil
+
+	status := session.Status(a.Clock.Now())
+	a.Events.PublishPomodoro(events.PomodoroStopped, status)
+	return &status, nil
+}
+
+// FinishPomodoroCycle ends the session once the current phase is over
+func (a *App) FinishPomodoroCycle() (*models.PomodoroStatus, error) {
+	return a.controlPomodoro(events.PomodoroUpdated, (*utils.PomodoroSession).FinishAfterPhase)
+}
+
+// LogInterruption captures something that came up during a Pomodoro session
+// as a new task and counts it as an interruption, without stopping the timer
+func (a *App) LogInterruption(spec models.TaskSpec) (*models.Task, error) {
+	if strings.TrimSpace(spec.Title) == "" {
+		return nil, models.Invalidf("the interruption note is empty")
+	}
+
+	status, err := a.controlPomodoro(events.PomodoroUpdated, (*utils.PomodoroSession).LogInterruption)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Description == "" {
+		spec.Description = fmt.Sprintf("Came up during a Pomodoro on '%s'", status.TaskTitle)
+	}
+	return a.AddTask(spec)
+}
+
+// endPomodoro cleans up after a session that ended by itself
+func (a *App) endPomodoro(session *utils.PomodoroSession, status models.PomodoroStatus) {
+	a.pomodoroMu.Lock()
+	defer a.pomodoroMu.Unlock()
+
+	// The session may have been stopped and replaced in the meantime
+	if a.pomodoro != session {
+		return
+	}
+
+	a.pomodoro = nil
+	a.stopPomodoro = nil
+	a.Events.PublishPomodoro(events.PomodoroStopped, status)
+}
+
+// controlPomodoro applies an action to the running session and publishes the result
+func (a *App) controlPomodoro(eventType events.Type, action func(*utils.PomodoroSession, time.Time) error) (*models.PomodoroStatus, error) {
+	a.pomodoroMu.Lock()
+	defer a.pomodoroMu.Unlock()
+
+	if a.pomodoro == nil {
+		return nil, models.Invalidf("no pomodoro session is running")
+	}
+
+	now := a.Clock.Now()
+	if err := action(a.pomodoro, now); err != nil {
+		return nil, err
+	}
+
+	status := a.pomodoro.Status(now)
+	a.Events.PublishPomodoro(eventType, status)
+	return &status, nil
+}
+
+// recordPomodoro stores a finished work interval and adds the time worked to its task
+func (a *App) recordPomodoro(record *models.PomodoroRecord) error {
+	if err := a.Storage.AddPomodoroRecord(record); err != nil {
+		return fmt.Errorf("failed to save pomodoro record: %w", err)
+	}
+
+	task, err := a.modifyTask(record.TaskID, func(task *models.Task) error {
+		task.TimeSpent += record.Duration()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return nil
+}
+
+// GetPomodoroHistory returns the work intervals that ended at or after since, oldest first
+func (a *App) GetPomodoroHistory(since time.Time) ([]*models.PomodoroRecord, error) {
+	records, err := a.Storage.GetPomodoroRecords(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pomodoro history: %w", err)
+	}
+	return records, nil
+}
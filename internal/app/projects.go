@@ -0,0 +1,253 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/storage"
+	"github.com/user/todolist/internal/utils"
+)
+
+// AddProject creates a project with the name, description, deadline and color
+// of the given one
+func (a *App) AddProject(project models.Project) (*models.Project, error) {
+	created := models.NewProject(project.Name, a.Clock.Now())
+	created.Description = project.Description
+	created.Deadline = project.Deadline
+	created.Color = strings.ToLower(strings.TrimSpace(project.Color))
+	created.CreatedAt = a.Clock.Now()
+	if err := created.Validate(); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.checkProjectName(created); err != nil {
+		return nil, err
+	}
+	if err := a.Storage.AddProject(created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateProject applies a partial update to a project
+func (a *App) UpdateProject(ref string, patch models.ProjectPatch) (*models.Project, error) {
+	return a.modifyProject(ref, func(project *models.Project) error {
+		if err := patch.Apply(project); err != nil {
+			return err
+		}
+		return a.checkProjectName(project)
+	})
+}
+
+// ArchiveProject archives a project, or brings an archived project back.
+// Tasks in archived projects are left out of focus mode.
+func (a *App) ArchiveProject(ref string, archived bool) (*models.Project, error) {
+	return a.modifyProject(ref, func(project *models.Project) error {
+		project.Archived = archived
+		return nil
+	})
+}
+
+// DeleteProject deletes a project and moves its tasks out of it. It returns
+// the number of tasks that were in the project.
+func (a *App) DeleteProject(ref string) (int, error) {
+	project, err := a.GetProject(ref)
+	if err != nil {
+		return 0, err
+	}
+	if err := a.Storage.DeleteProject(project.ID); err != nil {
+		return 0, err
+	}
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	moved := 0
+	for _, task := range tasks {
+		if task.Project != project.ID {
+			continue
+		}
+
+		updated, err := a.modifyTask(task.ID, func(task *models.Task) error {
+			task.Project = ""
+			return nil
+		})
+		if err != nil {
+			return moved, fmt.Errorf("failed to update task '%s': %w", task.Title, err)
+		}
+		a.Events.PublishTask(events.TaskUpdated, updated)
+		moved++
+	}
+	return moved, nil
+}
+
+// GetProject retrieves a project by ID or by name, ignoring case
+func (a *App) GetProject(ref string) (*models.Project, error) {
+	projects, err := a.Storage.GetAllProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	project := models.FindProject(projects, ref)
+	if project == nil {
+		return nil, storage.ErrProjectNotFound{ID: ref}
+	}
+	return project, nil
+}
+
+// GetProjects returns all projects, oldest first. Archived projects are only
+// included if asked for.
+func (a *App) GetProjects(includeArchived bool) ([]*models.Project, error) {
+	projects, err := a.Storage.GetAllProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	if includeArchived {
+		return projects, nil
+	}
+
+	active := make([]*models.Project, 0, len(projects))
+	for _, project := range projects {
+		if !project.Archived {
+			active = append(active, project)
+		}
+	}
+	return active, nil
+}
+
+// ProjectSummaries returns the progress of every project, oldest first.
+// Archived projects are only included if asked for.
+func (a *App) ProjectSummaries(includeArchived bool) ([]models.ProjectSummary, error) {
+	projects, err := a.GetProjects(includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	summaries := make([]models.ProjectSummary, 0, len(projects))
+	for _, project := range projects {
+		summaries = append(summaries, a.summarizeProject(project, tasks))
+	}
+	return summaries, nil
+}
+
+// ProjectSummary returns the progress of a single project
+func (a *App) ProjectSummary(ref string) (*models.ProjectSummary, error) {
+	project, err := a.GetProject(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	summary := a.summarizeProject(project, tasks)
+	return &summary, nil
+}
+
+// summarizeProject counts the project's tasks and picks the best focus
+// suggestion among them as its next action. Cancelled tasks do not count
+// towards the project's progress.
+func (a *App) summarizeProject(project *models.Project, tasks []*models.Task) models.ProjectSummary {
+	now := a.Clock.Now()
+	summary := models.ProjectSummary{Project: project}
+	for _, task := range tasks {
+		if task.Project != project.ID || task.Status == models.StatusCancelled {
+			continue
+		}
+		summary.Total++
+		if task.Completed {
+			summary.Done++
+		} else if task.IsOverdue(now) {
+			summary.Overdue++
+		}
+	}
+
+	// Blockers may live in other projects, so score against all tasks
+	suggestions := utils.FocusMode(tasks, now, a.Config.Focus, models.FocusOptions{Project: project.ID, Limit: 1})
+	if len(suggestions) > 0 {
+		summary.NextAction = suggestions[0].Task
+	}
+	return summary
+}
+
+// modifyProject applies fn to a copy of the stored project and saves the result
+func (a *App) modifyProject(ref string, fn func(project *models.Project) error) (*models.Project, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	project, err := a.GetProject(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *project
+	if err := fn(&updated); err != nil {
+		return nil, err
+	}
+
+	if err := a.Storage.UpdateProject(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// checkProjectName makes sure no other project has the project's name
+func (a *App) checkProjectName(project *models.Project) error {
+	projects, err := a.Storage.GetAllProjects()
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	for _, other := range projects {
+		if other.ID != project.ID && strings.EqualFold(other.Name, project.Name) {
+			return models.Invalidf("a project named '%s' already exists", other.Name)
+		}
+	}
+	return nil
+}
+
+// resolveProject turns a project ID or name into the ID of an active project.
+// An empty reference means no project.
+func (a *App) resolveProject(ref string) (string, error) {
+	if strings.TrimSpace(ref) == "" {
+		return "", nil
+	}
+
+	project, err := a.GetProject(ref)
+	if err != nil {
+		return "", err
+	}
+	if project.Archived {
+		return "", models.Invalidf("project '%s' is archived", project.Name)
+	}
+	return project.ID, nil
+}
+
+// archivedProjects returns the IDs of the archived projects
+func (a *App) archivedProjects() (map[string]bool, error) {
+	projects, err := a.Storage.GetAllProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	archived := make(map[string]bool)
+	for _, project := range projects {
+		if project.Archived {
+			archived[project.ID] = true
+		}
+	}
+	return archived, nil
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/query"
+)
+
+// QueryTasks returns the page of tasks matching a query expression, sorted
+// and paged as requested
+func (a *App) QueryTasks(q models.TaskQuery) (*models.TaskPage, error) {
+	if q.Offset < 0 || q.Limit < 0 {
+		return nil, models.Invalidf("offset and limit must not be negative")
+	}
+
+	where, err := query.Parse(q.Where, a.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := a.resolveQueryProjects(where); err != nil {
+		return nil, err
+	}
+
+	sortKeys, err := query.ParseSort(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := a.Storage.QueryTasks(query.Query{Where: where, Sort: sortKeys, Offset: q.Offset, Limit: q.Limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	return page, nil
+}
+
+// resolveQueryProjects replaces the project names in a query with their IDs.
+// Archived projects can be queried as well.
+func (a *App) resolveQueryProjects(where query.Expr) error {
+	if !query.HasField(where, query.FieldProject) {
+		return nil
+	}
+
+	projects, err := a.Storage.GetAllProjects()
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	var unknown []string
+	query.Walk(where, func(term *query.Term) {
+		if term.Field != query.FieldProject || term.IsNone() {
+			return
+		}
+		if project := models.FindProject(projects, term.Value); project != nil {
+			term.Value = project.ID
+		} else {
+			unknown = append(unknown, term.Value)
+		}
+	})
+	if len(unknown) > 0 {
+		return models.Invalidf("no project named %q (use 'todolist project list --all' to see all projects)", unknown[0])
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+)
+
+// PendingReminders returns the tasks whose reminder or due date has passed
+// without being acknowledged, oldest first
+func (a *App) PendingReminders() ([]*models.Task, error) {
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	now := a.Clock.Now()
+	alerts := make(map[string]time.Time)
+	var pending []*models.Task
+	for _, task := range tasks {
+		if at, ok := task.PendingAlert(now); ok {
+			alerts[task.ID] = at
+			pending = append(pending, task)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return alerts[pending[i].ID].Before(alerts[pending[j].ID])
+	})
+	return pending, nil
+}
+
+// AcknowledgeReminder stops all alerts of a task that are due so far
+func (a *App) AcknowledgeReminder(id string) error {
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.AcknowledgeAlerts(a.Clock.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return nil
+}
+
+// SnoozeReminder silences the alerts of a task until the given time
+func (a *App) SnoozeReminder(id string, until time.Time) error {
+	if !until.After(a.Clock.Now()) {
+		return models.Invalidf("snooze time must be in the future")
+	}
+
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.Snooze(until)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return nil
+}
+
+// MarkReminderNotified records that a task's pending alert was delivered
+func (a *App) MarkReminderNotified(id string, at time.Time) error {
+	_, err := a.modifyTask(id, func(task *models.Task) error {
+		task.MarkNotified(at)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+)
+
+// SearchTasks runs a full-text search over the title, description and notes
+// of the tasks and returns the matches best first
+func (a *App) SearchTasks(q models.SearchQuery) ([]*models.SearchResult, error) {
+	if strings.TrimSpace(q.Text) == "" {
+		return nil, models.Invalidf("search text cannot be empty")
+	}
+	if q.Limit < 0 {
+		return nil, models.Invalidf("limit must not be negative")
+	}
+
+	results, err := a.Storage.SearchTasks(q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+	return results, nil
+}
+
+// AddNote appends a note to a task
+func (a *App) AddNote(id, text string) (*models.Task, error) {
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		return task.AddNote(text, a.Clock.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/models"
+)
+
+// AddTags adds tags to a task
+func (a *App) AddTags(id string, tags []string) (*models.Task, error) {
+	tags, err := parseTagList(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.AddTags(tags...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
+
+// RemoveTags removes tags from a task
+func (a *App) RemoveTags(id string, tags []string) (*models.Task, error) {
+	tags, err := parseTagList(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := a.modifyTask(id, func(task *models.Task) error {
+		task.RemoveTags(tags...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Events.PublishTask(events.TaskUpdated, task)
+	return task, nil
+}
+
+// RenameTags replaces the given tags with a new tag on every task. Renaming
+// several tags at once merges them. It returns the number of tasks changed.
+func (a *App) RenameTags(from []string, to string) (int, error) {
+	from, err := parseTagList(from)
+	if err != nil {
+		return 0, err
+	}
+	target, err := parseTagList([]string{to})
+	if err != nil {
+		return 0, err
+	}
+
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	updated := 0
+	for _, task := range tasks {
+		if !(models.TagFilter{Any: from}).Matches(task) {
+			continue
+		}
+
+		changed, err := a.modifyTask(task.ID, func(task *models.Task) error {
+			task.RemoveTags(from...)
+			task.AddTags(target...)
+			return nil
+		})
+		if err != nil {
+			return updated, fmt.Errorf("failed to update task '%s': %w", task.Title, err)
+		}
+		a.Events.PublishTask(events.TaskUpdated, changed)
+		updated++
+	}
+	return updated, nil
+}
+
+// TagCounts returns every tag in use with the number of tasks that carry it,
+// most used first
+func (a *App) TagCounts() ([]models.TagCount, error) {
+	tasks, err := a.Storage.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	counts := make(map[string]*models.TagCount)
+	for _, task := range tasks {
+		for _, tag := range task.Tags {
+			count, ok := counts[tag]
+			if !ok {
+				count = &models.TagCount{Tag: tag}
+				counts[tag] = count
+			}
+			count.Count++
+			if !task.Completed {
+				count.Open++
+			}
+		}
+	}
+
+	result := make([]models.TagCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result, nil
+}
+
+// parseTagList validates a non-empty list of tags
+func parseTagList(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, models.Invalidf("no tags given")
+	}
+	return models.ParseTags(tags)
+}
@@ -3,36 +3,210 @@ package client
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/user/todolist/internal/events"
 	"github.com/user/todolist/internal/models"
 	"github.com/user/todolist/internal/protocol"
+	"github.com/user/todolist/internal/service"
+	"github.com/user/todolist/internal/storage"
 )
 
-// Client represents a connection to the TodoList server
+// Client represents a connection to the TodoList server. Requests may be
+// sent from several goroutines; responses are matched to their request by ID.
 type Client struct {
-	conn   net.Conn
-	reader *bufio.Reader
+	address string
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	current *connection
+	nextID  uint64
+	pending map[string]chan *protocol.Response
+	events  chan events.Event
 }
 
+// dialTimeout bounds how long connecting to the server may take
+const dialTimeout = 5 * time.Second
+
+// eventBuffer is the number of events queued for a subscriber
+const eventBuffer = 64
+
+// connection is a single TCP connection to the server
+type connection struct {
+	conn net.Conn
+	done chan struct{}
+	err  error // why the connection ended, set before done is closed
+}
+
+// ConnectionError reports that the server could not be reached or the
+// connection to it was lost
+type ConnectionError struct {
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// ServerError reports a request the server failed. It unwraps to a
+// *models.ValidationError, storage.ErrTaskNotFound or storage.ErrProjectNotFound
+// when the server said what kind of error it was.
+type ServerError struct {
+	Message string
+	kind    error
+}
+
+func (e *ServerError) Error() string {
+	return "server error: " + e.Message
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.kind
+}
+
+// serverError returns the error for a failed response
+func serverError(response *protocol.Response) error {
+	err := &ServerError{Message: response.Error}
+	switch response.Code {
+	case protocol.CodeInvalidInput:
+		err.kind = &models.ValidationError{Err: errors.New(response.Error)}
+	case protocol.CodeTaskNotFound:
+		err.kind = storage.ErrTaskNotFound{}
+	case protocol.CodeProjectNotFound:
+		err.kind = storage.ErrProjectNotFound{}
+	}
+	return err
+}
+
+// Ensure Client satisfies the shared service interface
+var _ service.TaskService = (*Client)(nil)
+
 // NewClient creates a new client connected to the specified address
 func NewClient(address string) (*Client, error) {
-	conn, err := net.Dial("tcp", address)
+	c := &Client{address: address}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// connect opens a new connection to the server, replacing the current one
+func (c *Client) connect() error {
+	conn, err := net.DialTimeout("tcp", c.address, dialTimeout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to server: %w", err)
+		return &ConnectionError{Err: fmt.Errorf("failed to connect to server: %w", err)}
+	}
+
+	cn := &connection{conn: conn, done: make(chan struct{})}
+
+	c.mu.Lock()
+	if c.current != nil {
+		c.current.conn.Close()
 	}
+	c.current = cn
+	c.pending = make(map[string]chan *protocol.Response)
+	// The subscription ended with the old connection
+	if c.events != nil {
+		close(c.events)
+		c.events = nil
+	}
+	c.mu.Unlock()
+
+	go c.readLoop(cn)
+	return nil
+}
 
-	return &Client{
-		conn:   conn,
-		reader: bufio.NewReader(conn),
-	}, nil
+// Reconnect replaces a lost connection with a new one. Event subscriptions
+// do not survive a reconnect.
+func (c *Client) Reconnect() error {
+	return c.connect()
 }
 
 // Close closes the connection to the server
 func (c *Client) Close() error {
-	return c.conn.Close()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current.conn.Close()
+}
+
+// Done returns a channel that is closed when the connection is lost
+func (c *Client) Done() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current.done
+}
+
+// readLoop reads messages from the server and dispatches responses to the
+// waiting requests and events to the subscription
+func (c *Client) readLoop(cn *connection) {
+	var err error
+	defer func() {
+		cn.err = err
+		c.mu.Lock()
+		if c.current == cn && c.events != nil {
+			close(c.events)
+			c.events = nil
+		}
+		c.mu.Unlock()
+		close(cn.done)
+	}()
+
+	reader := bufio.NewReader(cn.conn)
+	for {
+		var responseBytes []byte
+		responseBytes, err = reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+
+		var response protocol.Response
+		if err = json.Unmarshal(responseBytes, &response); err != nil {
+			err = fmt.Errorf("failed to unmarshal response: %w", err)
+			return
+		}
+
+		if response.Event != nil {
+			c.mu.Lock()
+			if c.current == cn && c.events != nil {
+				deliverEvent(c.events, *response.Event)
+			}
+			c.mu.Unlock()
+			continue
+		}
+
+		c.mu.Lock()
+		responseCh, ok := c.pending[response.ID]
+		delete(c.pending, response.ID)
+		c.mu.Unlock()
+		if ok {
+			responseCh <- &response
+		}
+	}
+}
+
+// deliverEvent queues an event without blocking. When the subscriber falls
+// behind, the oldest queued event is dropped to make room.
+func deliverEvent(eventCh chan events.Event, event events.Event) {
+	for {
+		select {
+		case eventCh <- event:
+			return
+		default:
+		}
+		select {
+		case <-eventCh:
+		default:
+		}
+	}
 }
 
 // sendRequest sends a request to the server and returns the response
@@ -47,8 +221,18 @@ func (c *Client) sendRequest(operation string, payload interface{}) (*protocol.R
 		}
 	}
 
+	// Register the request so the reader can hand us its response
+	responseCh := make(chan *protocol.Response, 1)
+	c.mu.Lock()
+	c.nextID++
+	id := strconv.FormatUint(c.nextID, 10)
+	c.pending[id] = responseCh
+	cn := c.current
+	c.mu.Unlock()
+
 	// Create request
 	request := protocol.Request{
+		ID:        id,
 		Operation: operation,
 		Payload:   payloadBytes,
 	}
@@ -56,41 +240,63 @@ func (c *Client) sendRequest(operation string, payload interface{}) (*protocol.R
 	// Marshal request
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
+		c.forget(id)
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 	requestBytes = append(requestBytes, '\n')
 
 	// Send request
-	_, err = c.conn.Write(requestBytes)
+	c.writeMu.Lock()
+	_, err = cn.conn.Write(requestBytes)
+	c.writeMu.Unlock()
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		c.forget(id)
+		return nil, &ConnectionError{Err: fmt.Errorf("failed to send request: %w", err)}
 	}
 
-	// Read response
-	responseBytes, err := c.reader.ReadBytes('\n')
+	// Wait for the response
+	select {
+	case response := <-responseCh:
+		return response, nil
+	case <-cn.done:
+		c.forget(id)
+		return nil, &ConnectionError{Err: fmt.Errorf("failed to read response: %w", cn.err)}
+	}
+}
+
+// forget drops a request that will get no response
+func (c *Client) forget(id string) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// Subscribe asks the server to push task change and reminder events. The
+// returned channel is closed when the connection is lost or replaced. A
+// subscriber that falls behind loses the oldest events.
+func (c *Client) Subscribe() (<-chan events.Event, error) {
+	c.mu.Lock()
+	if c.events == nil {
+		c.events = make(chan events.Event, eventBuffer)
+	}
+	eventCh := c.events
+	c.mu.Unlock()
+
+	response, err := c.sendRequest(protocol.OpSubscribe, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	// Unmarshal response
-	var response protocol.Response
-	if err := json.Unmarshal(responseBytes, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if !response.Success {
+		return nil, serverError(response)
 	}
 
-	return &response, nil
+	return eventCh, nil
 }
 
 // AddTask adds a new task
-func (c *Client) AddTask(title, description string, priority models.Priority, category models.Category, dueDate, reminderAt time.Time) (*models.Task, error) {
-	payload := protocol.AddTaskRequest{
-		Title:       title,
-		Description: description,
-		Priority:    priority,
-		Category:    category,
-		DueDate:     dueDate,
-		ReminderAt:  reminderAt,
-	}
+func (c *Client) AddTask(spec models.TaskSpec) (*models.Task, error) {
+	payload := protocol.NewAddTaskRequest(spec)
 
 	response, err := c.sendRequest(protocol.OpAddTask, payload)
 	if err != nil {
@@ -98,7 +304,176 @@ func (c *Client) AddTask(title, description string, priority models.Priority, ca
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// AddTasks adds several tasks in one request
+func (c *Client) AddTasks(specs []models.TaskSpec) ([]*models.Task, error) {
+	payload := protocol.BatchAddRequest{Tasks: make([]protocol.AddTaskRequest, len(specs))}
+	for i, spec := range specs {
+		payload.Tasks[i] = protocol.NewAddTaskRequest(spec)
+	}
+
+	response, err := c.sendRequest(protocol.OpBatchAdd, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var tasksResp protocol.TasksResponse
+	if err := json.Unmarshal(response.Payload, &tasksResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tasks response: %w", err)
+	}
+
+	return tasksResp.Tasks, nil
+}
+
+// AddSubtask adds a new task nested under an existing parent task
+func (c *Client) AddSubtask(parentID string, spec models.TaskSpec) (*models.Task, error) {
+	payload := protocol.AddSubtaskRequest{
+		ParentID:       parentID,
+		AddTaskRequest: protocol.NewAddTaskRequest(spec),
+	}
+
+	response, err := c.sendRequest(protocol.OpAddSubtask, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// MoveTask moves a task under a new parent, or to the top level if parentID is empty
+func (c *Client) MoveTask(id, parentID string) error {
+	payload := protocol.MoveTaskRequest{ID: id, ParentID: parentID}
+
+	response, err := c.sendRequest(protocol.OpMoveTask, payload)
+	if err != nil {
+		return err
+	}
+
+	if !response.Success {
+		return serverError(response)
+	}
+
+	return nil
+}
+
+// SetTaskStatus moves a task to a new status
+func (c *Client) SetTaskStatus(id string, status models.Status) (*models.Task, error) {
+	payload := protocol.SetStatusRequest{ID: id, Status: status}
+
+	response, err := c.sendRequest(protocol.OpSetStatus, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// AddNote appends a note to a task
+func (c *Client) AddNote(id, text string) (*models.Task, error) {
+	payload := protocol.AddNoteRequest{ID: id, Text: text}
+
+	response, err := c.sendRequest(protocol.OpAddNote, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// ReopenTask moves a done or cancelled task back to the to-do list
+func (c *Client) ReopenTask(id string) (*models.Task, error) {
+	payload := protocol.IDRequest{ID: id}
+
+	response, err := c.sendRequest(protocol.OpReopenTask, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// BlockTask records that a task cannot start until another task is completed
+func (c *Client) BlockTask(id, blockerID string) (*models.Task, error) {
+	payload := protocol.BlockTaskRequest{ID: id, BlockerID: blockerID}
+
+	response, err := c.sendRequest(protocol.OpBlockTask, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// UnblockTask removes a "blocked by" link from a task, or all of them if blockerID is empty
+func (c *Client) UnblockTask(id, blockerID string) (*models.Task, error) {
+	payload := protocol.BlockTaskRequest{ID: id, BlockerID: blockerID}
+
+	response, err := c.sendRequest(protocol.OpUnblockTask, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
 	}
 
 	var taskResp protocol.TaskResponse
@@ -119,7 +494,7 @@ func (c *Client) GetTask(id string) (*models.Task, error) {
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
 	var taskResp protocol.TaskResponse
@@ -138,7 +513,7 @@ func (c *Client) GetAllTasks() ([]*models.Task, error) {
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
 	var tasksResp protocol.TasksResponse
@@ -159,7 +534,7 @@ func (c *Client) GetTasksByCategory(category models.Category) ([]*models.Task, e
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
 	var tasksResp protocol.TasksResponse
@@ -180,7 +555,7 @@ func (c *Client) GetTasksByPriority(priority models.Priority) ([]*models.Task, e
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
 	var tasksResp protocol.TasksResponse
@@ -191,6 +566,48 @@ func (c *Client) GetTasksByPriority(priority models.Priority) ([]*models.Task, e
 	return tasksResp.Tasks, nil
 }
 
+// QueryTasks retrieves the page of tasks matching a query expression
+func (c *Client) QueryTasks(q models.TaskQuery) (*models.TaskPage, error) {
+	payload := protocol.QueryRequest{Query: q}
+
+	response, err := c.sendRequest(protocol.OpQuery, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var queryResp protocol.QueryResponse
+	if err := json.Unmarshal(response.Payload, &queryResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal query response: %w", err)
+	}
+
+	return queryResp.Page, nil
+}
+
+// SearchTasks runs a full-text search over the tasks
+func (c *Client) SearchTasks(q models.SearchQuery) ([]*models.SearchResult, error) {
+	payload := protocol.SearchRequest{Query: q}
+
+	response, err := c.sendRequest(protocol.OpSearch, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var searchResp protocol.SearchResponse
+	if err := json.Unmarshal(response.Payload, &searchResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal search response: %w", err)
+	}
+
+	return searchResp.Results, nil
+}
+
 // UpdateTask updates a task
 func (c *Client) UpdateTask(task *models.Task) error {
 	payload := protocol.TaskResponse{Task: task}
@@ -201,12 +618,33 @@ func (c *Client) UpdateTask(task *models.Task) error {
 	}
 
 	if !response.Success {
-		return fmt.Errorf("server error: %s", response.Error)
+		return serverError(response)
 	}
 
 	return nil
 }
 
+// PatchTask applies a partial update to a task and returns the updated task
+func (c *Client) PatchTask(id string, patch models.TaskPatch) (*models.Task, error) {
+	payload := protocol.PatchTaskRequest{ID: id, Patch: patch}
+
+	response, err := c.sendRequest(protocol.OpPatchTask, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
 // DeleteTask deletes a task by ID
 func (c *Client) DeleteTask(id string) error {
 	payload := protocol.IDRequest{ID: id}
@@ -217,7 +655,7 @@ func (c *Client) DeleteTask(id string) error {
 	}
 
 	if !response.Success {
-		return fmt.Errorf("server error: %s", response.Error)
+		return serverError(response)
 	}
 
 	return nil
@@ -233,7 +671,289 @@ func (c *Client) CompleteTask(id string) error {
 	}
 
 	if !response.Success {
-		return fmt.Errorf("server error: %s", response.Error)
+		return serverError(response)
+	}
+
+	return nil
+}
+
+// AddTags adds tags to a task
+func (c *Client) AddTags(id string, tags []string) (*models.Task, error) {
+	payload := protocol.TagsRequest{ID: id, Tags: tags}
+
+	response, err := c.sendRequest(protocol.OpAddTags, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// RemoveTags removes tags from a task
+func (c *Client) RemoveTags(id string, tags []string) (*models.Task, error) {
+	payload := protocol.TagsRequest{ID: id, Tags: tags}
+
+	response, err := c.sendRequest(protocol.OpRemoveTags, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// RenameTags replaces tags with another tag on every task and returns the
+// number of tasks changed
+func (c *Client) RenameTags(from []string, to string) (int, error) {
+	payload := protocol.RenameTagsRequest{From: from, To: to}
+
+	response, err := c.sendRequest(protocol.OpRenameTags, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	if !response.Success {
+		return 0, serverError(response)
+	}
+
+	var renameResp protocol.RenameTagsResponse
+	if err := json.Unmarshal(response.Payload, &renameResp); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal rename tags response: %w", err)
+	}
+
+	return renameResp.Updated, nil
+}
+
+// TagCounts retrieves every tag in use with the number of tasks that carry it
+func (c *Client) TagCounts() ([]models.TagCount, error) {
+	response, err := c.sendRequest(protocol.OpGetTags, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var tagsResp protocol.TagCountsResponse
+	if err := json.Unmarshal(response.Payload, &tagsResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags response: %w", err)
+	}
+
+	return tagsResp.Tags, nil
+}
+
+// AddProject creates a project
+func (c *Client) AddProject(project models.Project) (*models.Project, error) {
+	payload := protocol.ProjectRequest{Project: project}
+
+	response, err := c.sendRequest(protocol.OpAddProject, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var projectResp protocol.ProjectResponse
+	if err := json.Unmarshal(response.Payload, &projectResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project response: %w", err)
+	}
+
+	return projectResp.Project, nil
+}
+
+// UpdateProject applies a partial update to a project
+func (c *Client) UpdateProject(ref string, patch models.ProjectPatch) (*models.Project, error) {
+	payload := protocol.UpdateProjectRequest{Project: ref, Patch: patch}
+
+	response, err := c.sendRequest(protocol.OpUpdateProject, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var projectResp protocol.ProjectResponse
+	if err := json.Unmarshal(response.Payload, &projectResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project response: %w", err)
+	}
+
+	return projectResp.Project, nil
+}
+
+// ArchiveProject archives or unarchives a project
+func (c *Client) ArchiveProject(ref string, archived bool) (*models.Project, error) {
+	payload := protocol.ArchiveProjectRequest{Project: ref, Archived: archived}
+
+	response, err := c.sendRequest(protocol.OpArchiveProject, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var projectResp protocol.ProjectResponse
+	if err := json.Unmarshal(response.Payload, &projectResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project response: %w", err)
+	}
+
+	return projectResp.Project, nil
+}
+
+// DeleteProject deletes a project and returns the number of tasks moved out
+// of it
+func (c *Client) DeleteProject(ref string) (int, error) {
+	payload := protocol.ProjectRefRequest{Project: ref}
+
+	response, err := c.sendRequest(protocol.OpDeleteProject, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	if !response.Success {
+		return 0, serverError(response)
+	}
+
+	var deleteResp protocol.DeleteProjectResponse
+	if err := json.Unmarshal(response.Payload, &deleteResp); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal delete project response: %w", err)
+	}
+
+	return deleteResp.Moved, nil
+}
+
+// GetProjects retrieves the projects
+func (c *Client) GetProjects(includeArchived bool) ([]*models.Project, error) {
+	payload := protocol.ProjectsRequest{IncludeArchived: includeArchived}
+
+	response, err := c.sendRequest(protocol.OpGetProjects, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var projectsResp protocol.ProjectsResponse
+	if err := json.Unmarshal(response.Payload, &projectsResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal projects response: %w", err)
+	}
+
+	return projectsResp.Projects, nil
+}
+
+// ProjectSummary retrieves the progress of a project
+func (c *Client) ProjectSummary(ref string) (*models.ProjectSummary, error) {
+	payload := protocol.ProjectRefRequest{Project: ref}
+
+	response, err := c.sendRequest(protocol.OpGetProjectSummary, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var summaryResp protocol.ProjectSummaryResponse
+	if err := json.Unmarshal(response.Payload, &summaryResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project summary response: %w", err)
+	}
+
+	return summaryResp.Summary, nil
+}
+
+// ProjectSummaries retrieves the progress of every project
+func (c *Client) ProjectSummaries(includeArchived bool) ([]models.ProjectSummary, error) {
+	payload := protocol.ProjectsRequest{IncludeArchived: includeArchived}
+
+	response, err := c.sendRequest(protocol.OpGetProjectSummaries, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var summariesResp protocol.ProjectSummariesResponse
+	if err := json.Unmarshal(response.Payload, &summariesResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project summaries response: %w", err)
+	}
+
+	return summariesResp.Summaries, nil
+}
+
+// PendingReminders retrieves the tasks with unacknowledged alerts
+func (c *Client) PendingReminders() ([]*models.Task, error) {
+	response, err := c.sendRequest(protocol.OpGetReminders, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var tasksResp protocol.TasksResponse
+	if err := json.Unmarshal(response.Payload, &tasksResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tasks response: %w", err)
+	}
+
+	return tasksResp.Tasks, nil
+}
+
+// AcknowledgeReminder stops the due alerts of a task
+func (c *Client) AcknowledgeReminder(id string) error {
+	payload := protocol.IDRequest{ID: id}
+
+	response, err := c.sendRequest(protocol.OpAcknowledgeReminder, payload)
+	if err != nil {
+		return err
+	}
+
+	if !response.Success {
+		return serverError(response)
+	}
+
+	return nil
+}
+
+// SnoozeReminder silences the alerts of a task until the given time
+func (c *Client) SnoozeReminder(id string, until time.Time) error {
+	payload := protocol.SnoozeReminderRequest{ID: id, Until: until}
+
+	response, err := c.sendRequest(protocol.OpSnoozeReminder, payload)
+	if err != nil {
+		return err
+	}
+
+	if !response.Success {
+		return serverError(response)
 	}
 
 	return nil
@@ -249,7 +969,7 @@ func (c *Client) BackupTasks() (string, error) {
 	}
 
 	if !response.Success {
-		return "", fmt.Errorf("server error: %s", response.Error)
+		return "", serverError(response)
 	}
 
 	var backupResp protocol.BackupResponse
@@ -270,7 +990,7 @@ func (c *Client) RestoreTasks(filename string) error {
 	}
 
 	if !response.Success {
-		return fmt.Errorf("server error: %s", response.Error)
+		return serverError(response)
 	}
 
 	return nil
@@ -284,7 +1004,7 @@ func (c *Client) ListBackups() ([]string, error) {
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
 	var backupsResp protocol.ListBackupsResponse
@@ -295,29 +1015,61 @@ func (c *Client) ListBackups() ([]string, error) {
 	return backupsResp.Backups, nil
 }
 
-// BrainDump performs a brain dump
-func (c *Client) BrainDump() error {
-	response, err := c.sendRequest(protocol.OpBrainDump, nil)
+// FocusMode retrieves focus suggestions from the server
+func (c *Client) FocusMode(options models.FocusOptions) ([]models.TaskScore, error) {
+	payload := protocol.FocusModeRequest{
+		Options: options,
+	}
+
+	response, err := c.sendRequest(protocol.OpFocusMode, payload)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !response.Success {
-		return fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
-	return nil
+	var focusResp protocol.FocusModeResponse
+	if err := json.Unmarshal(response.Payload, &focusResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal focus mode response: %w", err)
+	}
+
+	return focusResp.Suggestions, nil
+}
+
+// SkipFocusTask records that a focus suggestion was skipped and hides it until the given time
+func (c *Client) SkipFocusTask(id, reason string, until time.Time) (*models.Task, error) {
+	payload := protocol.SkipFocusRequest{ID: id, Reason: reason, Until: until}
+
+	response, err := c.sendRequest(protocol.OpSkipFocus, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
 }
 
-// FocusMode enters focus mode
-func (c *Client) FocusMode() (*models.Task, error) {
-	response, err := c.sendRequest(protocol.OpFocusMode, nil)
+// SnoozeFocusTask keeps a task out of focus suggestions until the given time
+func (c *Client) SnoozeFocusTask(id string, until time.Time) (*models.Task, error) {
+	payload := protocol.SnoozeFocusRequest{ID: id, Until: until}
+
+	response, err := c.sendRequest(protocol.OpSnoozeFocus, payload)
 	if err != nil {
 		return nil, err
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
 	var taskResp protocol.TaskResponse
@@ -328,21 +1080,104 @@ func (c *Client) FocusMode() (*models.Task, error) {
 	return taskResp.Task, nil
 }
 
-// StartPomodoro starts a pomodoro timer for a task
-func (c *Client) StartPomodoro(taskID string, customDuration time.Duration) error {
+// StartPomodoro starts a pomodoro session for a task on the server
+func (c *Client) StartPomodoro(taskID string, customDuration time.Duration, cycles int) (*models.PomodoroStatus, error) {
 	payload := protocol.PomodoroRequest{
 		TaskID:         taskID,
 		CustomDuration: customDuration,
+		Cycles:         cycles,
 	}
 
-	response, err := c.sendRequest(protocol.OpStartPomodoro, payload)
+	return c.pomodoroRequest(protocol.OpStartPomodoro, payload)
+}
+
+// PomodoroStatus retrieves the state of the pomodoro session on the server
+func (c *Client) PomodoroStatus() (*models.PomodoroStatus, error) {
+	return c.pomodoroRequest(protocol.OpPomodoroStatus, nil)
+}
+
+// PausePomodoro pauses the pomodoro session on the server
+func (c *Client) PausePomodoro() (*models.PomodoroStatus, error) {
+	return c.pomodoroRequest(protocol.OpPausePomodoro, nil)
+}
+
+// ResumePomodoro resumes the paused pomodoro session on the server
+func (c *Client) ResumePomodoro() (*models.PomodoroStatus, error) {
+	return c.pomodoroRequest(protocol.OpResumePomodoro, nil)
+}
+
+// SkipPomodoroPhase ends the current work or break phase early
+func (c *Client) SkipPomodoroPhase() (*models.PomodoroStatus, error) {
+	return c.pomodoroRequest(protocol.OpSkipPomodoro, nil)
+}
+
+// FinishPomodoroCycle ends the pomodoro session on the server once the current phase is over
+func (c *Client) FinishPomodoroCycle() (*models.PomodoroStatus, error) {
+	return c.pomodoroRequest(protocol.OpFinishPomodoro, nil)
+}
+
+// LogInterruption captures an interruption during the pomodoro session as a new task
+func (c *Client) LogInterruption(spec models.TaskSpec) (*models.Task, error) {
+	response, err := c.sendRequest(protocol.OpLogInterruption, protocol.NewAddTaskRequest(spec))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !response.Success {
-		return fmt.Errorf("server error: %s", response.Error)
+		return nil, serverError(response)
 	}
 
-	return nil
+	var taskResp protocol.TaskResponse
+	if err := json.Unmarshal(response.Payload, &taskResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task response: %w", err)
+	}
+
+	return taskResp.Task, nil
+}
+
+// StopPomodoro ends the pomodoro session on the server
+func (c *Client) StopPomodoro(reason string) (*models.PomodoroStatus, error) {
+	return c.pomodoroRequest(protocol.OpStopPomodoro, protocol.StopPomodoroRequest{Reason: reason})
+}
+
+// GetPomodoroHistory retrieves the work intervals that ended at or after since
+func (c *Client) GetPomodoroHistory(since time.Time) ([]*models.PomodoroRecord, error) {
+	payload := protocol.PomodoroHistoryRequest{
+		Since: since,
+	}
+
+	response, err := c.sendRequest(protocol.OpGetPomodoroHistory, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var historyResp protocol.PomodoroHistoryResponse
+	if err := json.Unmarshal(response.Payload, &historyResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pomodoro history response: %w", err)
+	}
+
+	return historyResp.Records, nil
+}
+
+// pomodoroRequest sends a pomodoro operation and returns the resulting session state
+func (c *Client) pomodoroRequest(operation string, payload interface{}) (*models.PomodoroStatus, error) {
+	response, err := c.sendRequest(operation, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, serverError(response)
+	}
+
+	var pomodoroResp protocol.PomodoroResponse
+	if err := json.Unmarshal(response.Payload, &pomodoroResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pomodoro response: %w", err)
+	}
+
+	return pomodoroResp.Status, nil
 }
@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/client"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/testutil"
+)
+
+// newClient returns a client connected to an in-process server that works
+// on a fresh data directory
+func newClient(t *testing.T) *client.Client {
+	t.Helper()
+	return testutil.NewClient(t, testutil.NewApp(t))
+}
+
+// TestUndrainedSubscription checks that a subscriber that reads no events
+// does not hold up the responses to other requests
+func TestUndrainedSubscription(t *testing.T) {
+	todoClient := newClient(t)
+	eventCh, err := todoClient.Subscribe()
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	finished := make(chan error, 1)
+	go func() {
+		for i := 0; i < 200; i++ {
+			if _, err := todoClient.AddTask(models.TaskSpec{Title: fmt.Sprintf("Task %d", i)}); err != nil {
+				finished <- err
+				return
+			}
+		}
+		finished <- nil
+	}()
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("AddTask failed: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("requests stalled behind undrained events")
+	}
+
+	// The newest events are kept; they may still be on their way
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-eventCh:
+			if event.Task != nil && event.Task.Title == "Task 199" {
+				return
+			}
+		case <-timeout:
+			t.Fatal("the event for the last task never arrived")
+		}
+	}
+}
+
+func TestReconnectEndsSubscription(t *testing.T) {
+	todoClient := newClient(t)
+	eventCh, err := todoClient.Subscribe()
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	if err := todoClient.Reconnect(); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	select {
+	case _, ok := <-eventCh:
+		if ok {
+			t.Error("got an event from a subscription of the old connection")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription channel was not closed by the reconnect")
+	}
+
+	if _, err := todoClient.Subscribe(); err != nil {
+		t.Fatalf("Subscribe after reconnecting failed: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/user/todolist/internal/protocol"
+)
+
+// TestFailedRequestIsForgotten checks that a request that could not be sent
+// leaves nothing behind waiting for a response
+func TestFailedRequestIsForgotten(t *testing.T) {
+	local, remote := net.Pipe()
+	remote.Close()
+	c := &Client{
+		current: &connection{conn: local, done: make(chan struct{})},
+		pending: make(map[string]chan *protocol.Response),
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := c.sendRequest(protocol.OpGetAllTasks, nil)
+		if !errors.As(err, new(*ConnectionError)) {
+			t.Fatalf("request over a closed connection failed with %v, want a connection error", err)
+		}
+	}
+	if len(c.pending) != 0 {
+		t.Errorf("%d failed requests are still pending", len(c.pending))
+	}
+}
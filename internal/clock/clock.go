@@ -0,0 +1,40 @@
+// Package clock abstracts the passing of time so that code depending on it,
+// such as focus scoring, reminders and Pomodoro sessions, can be driven by a
+// fake clock instead of waiting in real time.
+package clock
+
+import "time"
+
+// Clock tells the time and waits for it to pass
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	Sleep(d time.Duration)
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// System is the clock of the operating system
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{ticker: time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	ticker *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.ticker.C }
+
+func (t systemTicker) Stop() { t.ticker.Stop() }
@@ -0,0 +1,125 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a clock that only moves when told to. Tickers fire and sleepers
+// wake as Set or Advance move the time past their deadlines, so code that
+// waits for minutes runs instantly.
+type Fake struct {
+	mu       sync.Mutex
+	now      time.Time
+	tickers  []*fakeTicker
+	sleepers []*sleeper
+}
+
+type sleeper struct {
+	until time.Time
+	done  chan struct{}
+}
+
+// NewFake creates a fake clock set to the given time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the current time of the clock
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to t, firing every ticker and waking every sleeper
+// whose time has come. Like time.Ticker, a ticker whose last tick has not
+// been received yet drops further ticks.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = t
+
+	for _, ticker := range f.tickers {
+		for !ticker.stopped && !ticker.next.After(t) {
+			select {
+			case ticker.c <- ticker.next:
+			default:
+			}
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+	}
+
+	waiting := f.sleepers[:0]
+	for _, s := range f.sleepers {
+		if s.until.After(t) {
+			waiting = append(waiting, s)
+		} else {
+			close(s.done)
+		}
+	}
+	f.sleepers = waiting
+}
+
+// Sleep blocks until the clock has been moved forward by d
+func (f *Fake) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	s := &sleeper{until: f.now.Add(d), done: make(chan struct{})}
+	f.sleepers = append(f.sleepers, s)
+	f.mu.Unlock()
+
+	<-s.done
+}
+
+// NewTicker creates a ticker that fires every d of fake time
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ticker := &fakeTicker{
+		clock:  f,
+		c:      make(chan time.Time, 1),
+		period: d,
+		next:   f.now.Add(d),
+	}
+	f.tickers = append(f.tickers, ticker)
+	return ticker
+}
+
+type fakeTicker struct {
+	clock   *Fake
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	stopped bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.c }
+
+// Stop turns off the ticker and removes it from the clock
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	t.stopped = true
+	for i, ticker := range t.clock.tickers {
+		if ticker == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,552 @@
+// Package dateparse parses natural-language date and time expressions such as
+// "friday 5pm", "in 3 days", "in 2h", "end of month" or "tomorrow morning".
+package dateparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeOfDay is a wall-clock time without a date
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+// Parser parses date expressions relative to the time returned by Now
+type Parser struct {
+	// Now returns the reference time. It can be replaced to pin results in tests.
+	Now func() time.Time
+	// DefaultTime is used when an expression names a day but no time
+	DefaultTime TimeOfDay
+	// Periods maps named parts of the day ("morning", "eod", ...) to times
+	Periods map[string]TimeOfDay
+}
+
+// DefaultPeriods returns the default times for named parts of the day
+func DefaultPeriods() map[string]TimeOfDay {
+	return map[string]TimeOfDay{
+		"morning":   {Hour: 9},
+		"noon":      {Hour: 12},
+		"midday":    {Hour: 12},
+		"lunch":     {Hour: 12},
+		"afternoon": {Hour: 14},
+		"eod":       {Hour: 17},
+		"cob":       {Hour: 17},
+		"evening":   {Hour: 18},
+		"tonight":   {Hour: 20},
+		"night":     {Hour: 21},
+		"midnight":  {Hour: 23, Minute: 59, Second: 59},
+	}
+}
+
+// New creates a parser using the system clock
+func New() *Parser {
+	return NewWithClock(time.Now)
+}
+
+// NewWithClock creates a parser that resolves expressions relative to now
+func NewWithClock(now func() time.Time) *Parser {
+	return &Parser{
+		Now:         now,
+		DefaultTime: TimeOfDay{Hour: 23, Minute: 59, Second: 59},
+		Periods:     DefaultPeriods(),
+	}
+}
+
+// Parse parses an expression using the system clock
+func Parse(input string) (time.Time, error) {
+	return New().Parse(input)
+}
+
+// fillerWords are ignored wherever they appear
+var fillerWords = map[string]bool{
+	"at": true, "on": true, "by": true, "the": true, "this": true, "of": true,
+}
+
+// Parse parses a date expression. It accepts absolute dates ("2025-03-14",
+// "2025-03-14 15:04", "march 14"), relative days ("today", "tomorrow",
+// "next tuesday", "friday"), offsets ("in 3 days", "in 2h", "+30m",
+// "2 weeks from now"), named periods ("end of month", "eow", "this weekend")
+// and times of day ("5pm", "17:30", "morning", "eod"), in any sensible
+// combination such as "friday 5pm" or "tomorrow morning".
+func (p *Parser) Parse(input string) (time.Time, error) {
+	now := p.Now()
+	expr := normalize(input)
+	if expr == "" {
+		return time.Time{}, fmt.Errorf("empty date string")
+	}
+
+	// Absolute formats are matched on the raw expression first
+	if t, ok := parseAbsolute(expr, now.Location()); ok {
+		return t, nil
+	}
+
+	var tokens []string
+	for _, token := range strings.Fields(expr) {
+		if !fillerWords[token] || token == "of" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	// Offsets shorter than a day resolve to an exact instant
+	if t, ok := p.parseInstant(tokens, now); ok {
+		return t, nil
+	}
+
+	var day *time.Time
+	var clock *TimeOfDay
+	bareWeekday := false
+
+	for i := 0; i < len(tokens); {
+		if tokens[i] == "of" {
+			i++
+			continue
+		}
+
+		if n, d, tod, ok := p.matchDay(tokens[i:], now); ok {
+			if day != nil {
+				return time.Time{}, unknownFormat(input)
+			}
+			day = &d
+			_, bareWeekday = weekdays[tokens[i]]
+			if tod != nil {
+				if clock != nil {
+					return time.Time{}, unknownFormat(input)
+				}
+				clock = tod
+			}
+			i += n
+			continue
+		}
+
+		if n, tod, ok := p.matchTime(tokens[i:]); ok {
+			if clock != nil {
+				return time.Time{}, unknownFormat(input)
+			}
+			clock = &tod
+			i += n
+			continue
+		}
+
+		return time.Time{}, unknownFormat(input)
+	}
+
+	switch {
+	case day == nil && clock == nil:
+		return time.Time{}, unknownFormat(input)
+	case day == nil:
+		// A bare time means the next time the clock shows it
+		t := at(now, *clock)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	tod := p.DefaultTime
+	if clock != nil {
+		tod = *clock
+	}
+
+	// "friday 5pm" said on a Friday evening means next Friday
+	t := at(*day, tod)
+	if bareWeekday && !t.After(now) {
+		t = t.AddDate(0, 0, 7)
+	}
+	return t, nil
+}
+
+// unknownFormat returns the error used for expressions that cannot be parsed
+func unknownFormat(input string) error {
+	return fmt.Errorf("unknown date format: %s (try YYYY-MM-DD, 'tomorrow 3pm', 'friday', 'in 3 days', 'in 2h' or 'end of month')", input)
+}
+
+// normalize lowercases the input and collapses whitespace and punctuation
+func normalize(input string) string {
+	expr := strings.ToLower(strings.TrimSpace(input))
+	expr = strings.NewReplacer(",", " ", "end-of-", "end of ").Replace(expr)
+	return strings.Join(strings.Fields(expr), " ")
+}
+
+// at returns the given day at the given time of day
+func at(day time.Time, tod TimeOfDay) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), tod.Hour, tod.Minute, tod.Second, 0, day.Location())
+}
+
+// startOfDay truncates t to midnight in its location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// parseAbsolute parses ISO-style absolute dates
+func parseAbsolute(expr string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02t15:04", "2006-01-02 15:04:05", "2006-01-02t15:04:05"} {
+		if t, err := time.ParseInLocation(layout, expr, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseInstant parses "now" and offsets measured in minutes or hours, which
+// resolve to an exact point in time rather than a day
+func (p *Parser) parseInstant(tokens []string, now time.Time) (time.Time, bool) {
+	if len(tokens) == 1 && tokens[0] == "now" {
+		return now, true
+	}
+
+	d, ok := parseOffset(tokens)
+	if !ok || d.clock == 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, d.months, d.days).Add(d.clock), true
+}
+
+// offset is a relative amount of time split into calendar and clock parts
+type offset struct {
+	months int
+	days   int
+	clock  time.Duration
+}
+
+// unitOffsets maps unit names to offsets of one unit
+var unitOffsets = map[string]offset{
+	"m": {clock: time.Minute}, "min": {clock: time.Minute}, "mins": {clock: time.Minute},
+	"minute": {clock: time.Minute}, "minutes": {clock: time.Minute},
+	"h": {clock: time.Hour}, "hr": {clock: time.Hour}, "hrs": {clock: time.Hour},
+	"hour": {clock: time.Hour}, "hours": {clock: time.Hour},
+	"d": {days: 1}, "day": {days: 1}, "days": {days: 1},
+	"w": {days: 7}, "wk": {days: 7}, "wks": {days: 7}, "week": {days: 7}, "weeks": {days: 7},
+	"mo": {months: 1}, "month": {months: 1}, "months": {months: 1},
+	"y": {months: 12}, "yr": {months: 12}, "yrs": {months: 12}, "year": {months: 12}, "years": {months: 12},
+}
+
+// parseOffset parses "in 3 days", "in 2h", "in an hour", "+30m" and
+// "2 weeks from now". The whole token list must be consumed.
+func parseOffset(tokens []string) (offset, bool) {
+	switch {
+	case len(tokens) >= 2 && tokens[0] == "in":
+		tokens = tokens[1:]
+	case len(tokens) >= 3 && tokens[len(tokens)-2] == "from" && tokens[len(tokens)-1] == "now":
+		tokens = tokens[:len(tokens)-2]
+	case len(tokens) == 1 && strings.HasPrefix(tokens[0], "+"):
+		tokens = []string{strings.TrimPrefix(tokens[0], "+")}
+	default:
+		return offset{}, false
+	}
+
+	var total offset
+	for len(tokens) > 0 {
+		n, unit, consumed, ok := splitAmount(tokens)
+		if !ok {
+			return offset{}, false
+		}
+
+		o, ok := unitOffsets[unit]
+		if !ok {
+			return offset{}, false
+		}
+
+		total.months += o.months * n
+		total.days += o.days * n
+		total.clock += o.clock * time.Duration(n)
+		tokens = tokens[consumed:]
+
+		// Allow "in 1 hour and 30 minutes"
+		if len(tokens) > 0 && tokens[0] == "and" {
+			tokens = tokens[1:]
+		}
+	}
+
+	return total, true
+}
+
+// splitAmount reads an amount and unit from either one token ("3d", "90m")
+// or two tokens ("3 days", "an hour")
+func splitAmount(tokens []string) (n int, unit string, consumed int, ok bool) {
+	token := tokens[0]
+
+	// Compact form such as "2h" or "15m"
+	i := 0
+	for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+		i++
+	}
+	if i > 0 && i < len(token) {
+		n, _ = strconv.Atoi(token[:i])
+		return n, token[i:], 1, true
+	}
+
+	if len(tokens) < 2 {
+		return 0, "", 0, false
+	}
+
+	switch token {
+	case "a", "an", "one":
+		n = 1
+	default:
+		var err error
+		n, err = strconv.Atoi(token)
+		if err != nil {
+			return 0, "", 0, false
+		}
+	}
+
+	return n, tokens[1], 2, true
+}
+
+// weekdays maps weekday names and abbreviations to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// months maps month names and abbreviations to months
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// matchDay matches a phrase naming a day at the start of tokens. It returns
+// the number of tokens consumed, the day, and optionally a time of day implied
+// by the phrase (such as "tonight").
+func (p *Parser) matchDay(tokens []string, now time.Time) (int, time.Time, *TimeOfDay, bool) {
+	today := startOfDay(now)
+
+	// ISO date
+	if t, err := time.ParseInLocation("2006-01-02", tokens[0], now.Location()); err == nil {
+		return 1, t, nil, true
+	}
+
+	switch tokens[0] {
+	case "today", "tod":
+		return 1, today, nil, true
+	case "tonight":
+		tod := p.Periods["tonight"]
+		return 1, today, &tod, true
+	case "tomorrow", "tmrw", "tmr", "tomorow":
+		return 1, today.AddDate(0, 0, 1), nil, true
+	case "yesterday":
+		return 1, today.AddDate(0, 0, -1), nil, true
+	case "eow":
+		return 1, endOfWeek(today), nil, true
+	case "eom":
+		return 1, endOfMonth(today), nil, true
+	case "eoy":
+		return 1, time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), nil, true
+	case "weekend":
+		return 1, nextWeekday(today, time.Saturday, true), nil, true
+	}
+
+	if len(tokens) >= 3 && tokens[0] == "day" && tokens[1] == "after" && tokens[2] == "tomorrow" {
+		return 3, today.AddDate(0, 0, 2), nil, true
+	}
+
+	// "end of week/month/year"; "of" and "the" have already been reduced to "of"
+	if len(tokens) >= 3 && tokens[0] == "end" && tokens[1] == "of" {
+		switch tokens[2] {
+		case "week":
+			return 3, endOfWeek(today), nil, true
+		case "month":
+			return 3, endOfMonth(today), nil, true
+		case "year":
+			return 3, time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), nil, true
+		case "day":
+			tod := p.Periods["eod"]
+			return 3, today, &tod, true
+		}
+	}
+
+	// "next week", "next month", "next year", "next friday"
+	if tokens[0] == "next" && len(tokens) >= 2 {
+		switch tokens[1] {
+		case "week":
+			return 2, today.AddDate(0, 0, 7), nil, true
+		case "month":
+			return 2, today.AddDate(0, 1, 0), nil, true
+		case "year":
+			return 2, today.AddDate(1, 0, 0), nil, true
+		case "weekend":
+			return 2, nextWeekday(today, time.Saturday, false), nil, true
+		}
+		if day, ok := weekdays[tokens[1]]; ok {
+			return 2, nextWeekday(today, day, false), nil, true
+		}
+	}
+
+	// Weekday names refer to the next such day, or today if it matches
+	if day, ok := weekdays[tokens[0]]; ok {
+		return 1, nextWeekday(today, day, true), nil, true
+	}
+
+	// Day offsets such as "in 3 days" or "2 weeks from now"
+	for n := len(tokens); n >= 2; n-- {
+		if o, ok := parseOffset(tokens[:n]); ok && o.clock == 0 {
+			return n, today.AddDate(0, o.months, o.days), nil, true
+		}
+	}
+
+	// Month names: "march 14", "14 march", optionally followed by a year
+	if n, t, ok := matchMonthDay(tokens, today); ok {
+		return n, t, nil, true
+	}
+
+	return 0, time.Time{}, nil, false
+}
+
+// matchMonthDay matches "march 14", "14 march" and "march 14 2026". Dates that
+// have already passed this year without an explicit year refer to next year.
+func matchMonthDay(tokens []string, today time.Time) (int, time.Time, bool) {
+	if len(tokens) < 2 {
+		return 0, time.Time{}, false
+	}
+
+	month, ok := months[tokens[0]]
+	dayToken := tokens[1]
+	if !ok {
+		month, ok = months[tokens[1]]
+		dayToken = tokens[0]
+	}
+	if !ok {
+		return 0, time.Time{}, false
+	}
+
+	dayToken = strings.TrimRight(dayToken, "stndrh") // 1st, 2nd, 3rd, 4th
+	day, err := strconv.Atoi(dayToken)
+	if err != nil || day < 1 || day > 31 {
+		return 0, time.Time{}, false
+	}
+
+	consumed := 2
+	year := today.Year()
+	explicitYear := false
+	if len(tokens) >= 3 {
+		if y, err := strconv.Atoi(tokens[2]); err == nil && y >= 1000 {
+			year = y
+			explicitYear = true
+			consumed = 3
+		}
+	}
+
+	t := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if t.Month() != month {
+		return 0, time.Time{}, false
+	}
+	if !explicitYear && t.Before(today) {
+		t = t.AddDate(1, 0, 0)
+	}
+
+	return consumed, t, true
+}
+
+// nextWeekday returns the next given weekday after today. If includeToday is
+// set and today is that weekday, today is returned.
+func nextWeekday(today time.Time, day time.Weekday, includeToday bool) time.Time {
+	diff := (int(day) - int(today.Weekday()) + 7) % 7
+	if diff == 0 && !includeToday {
+		diff = 7
+	}
+	return today.AddDate(0, 0, diff)
+}
+
+// endOfWeek returns the Friday of the current work week, or next Friday at weekends
+func endOfWeek(today time.Time) time.Time {
+	return nextWeekday(today, time.Friday, true)
+}
+
+// endOfMonth returns the last day of the current month
+func endOfMonth(today time.Time) time.Time {
+	return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location())
+}
+
+// matchTime matches a time of day at the start of tokens
+func (p *Parser) matchTime(tokens []string) (int, TimeOfDay, bool) {
+	if tod, ok := p.Periods[tokens[0]]; ok {
+		return 1, tod, true
+	}
+
+	// "5 pm" written with a space
+	if len(tokens) >= 2 && (tokens[1] == "am" || tokens[1] == "pm") {
+		if tod, ok := parseClock(tokens[0] + tokens[1]); ok {
+			return 2, tod, true
+		}
+	}
+
+	if tod, ok := parseClock(tokens[0]); ok {
+		return 1, tod, true
+	}
+
+	return 0, TimeOfDay{}, false
+}
+
+// parseClock parses "5pm", "5:30pm", "12am", "17:30" and "9:05"
+func parseClock(token string) (TimeOfDay, bool) {
+	suffix := ""
+	if strings.HasSuffix(token, "am") || strings.HasSuffix(token, "pm") {
+		suffix = token[len(token)-2:]
+		token = token[:len(token)-2]
+	}
+
+	hourStr, minuteStr, hasMinutes := strings.Cut(token, ":")
+	if !hasMinutes && suffix == "" {
+		// A bare number is not a time ("3" could be anything)
+		return TimeOfDay{}, false
+	}
+
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil {
+		return TimeOfDay{}, false
+	}
+
+	minute := 0
+	if hasMinutes {
+		if len(minuteStr) != 2 {
+			return TimeOfDay{}, false
+		}
+		minute, err = strconv.Atoi(minuteStr)
+		if err != nil || minute < 0 || minute > 59 {
+			return TimeOfDay{}, false
+		}
+	}
+
+	switch suffix {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return TimeOfDay{}, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return TimeOfDay{}, false
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	default:
+		if hour < 0 || hour > 23 {
+			return TimeOfDay{}, false
+		}
+	}
+
+	return TimeOfDay{Hour: hour, Minute: minute}, true
+}
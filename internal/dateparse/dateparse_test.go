@@ -0,0 +1,147 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+// now is Wednesday 12 March 2025, 10:30
+var now = time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+
+func date(month time.Month, day, hour, minute, second int) time.Time {
+	return time.Date(2025, month, day, hour, minute, second, 0, time.UTC)
+}
+
+// endOf returns the end of a day, the time used when no time is given
+func endOf(month time.Month, day int) time.Time {
+	return date(month, day, 23, 59, 59)
+}
+
+func TestParse(t *testing.T) {
+	parser := NewWithClock(func() time.Time { return now })
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		// Relative days
+		{"today", endOf(time.March, 12)},
+		{"tomorrow", endOf(time.March, 13)},
+		{"Tomorrow", endOf(time.March, 13)},
+		{"day after tomorrow", endOf(time.March, 14)},
+		{"next week", endOf(time.March, 19)},
+		{"next month", endOf(time.April, 12)},
+
+		// Relative offsets
+		{"in 3 days", endOf(time.March, 15)},
+		{"in a week", endOf(time.March, 19)},
+		{"2 weeks from now", endOf(time.March, 26)},
+		{"in 2h", date(time.March, 12, 12, 30, 0)},
+		{"in 1 hour and 30 minutes", date(time.March, 12, 12, 0, 0)},
+		{"+30m", date(time.March, 12, 11, 0, 0)},
+		{"now", now},
+
+		// Weekdays
+		{"friday", endOf(time.March, 14)},
+		{"fri", endOf(time.March, 14)},
+		{"wednesday", endOf(time.March, 12)},
+		{"next tuesday", endOf(time.March, 18)},
+		{"next wednesday", endOf(time.March, 19)},
+		{"monday", endOf(time.March, 17)},
+
+		// Times of day
+		{"5pm", date(time.March, 12, 17, 0, 0)},
+		{"5 pm", date(time.March, 12, 17, 0, 0)},
+		{"17:30", date(time.March, 12, 17, 30, 0)},
+		{"9am", date(time.March, 13, 9, 0, 0)},
+		{"12am", date(time.March, 13, 0, 0, 0)},
+		{"12pm", date(time.March, 12, 12, 0, 0)},
+		{"friday 5pm", date(time.March, 14, 17, 0, 0)},
+		{"tomorrow 3pm", date(time.March, 13, 15, 0, 0)},
+		{"tomorrow at 9:15am", date(time.March, 13, 9, 15, 0)},
+		{"wednesday 9am", date(time.March, 19, 9, 0, 0)},
+		{"wednesday 5pm", date(time.March, 12, 17, 0, 0)},
+
+		// Named periods
+		{"morning", date(time.March, 13, 9, 0, 0)},
+		{"tomorrow morning", date(time.March, 13, 9, 0, 0)},
+		{"afternoon", date(time.March, 12, 14, 0, 0)},
+		{"evening", date(time.March, 12, 18, 0, 0)},
+		{"friday evening", date(time.March, 14, 18, 0, 0)},
+		{"tonight", date(time.March, 12, 20, 0, 0)},
+		{"eod", date(time.March, 12, 17, 0, 0)},
+		{"EOD", date(time.March, 12, 17, 0, 0)},
+		{"end of day", date(time.March, 12, 17, 0, 0)},
+		{"tomorrow eod", date(time.March, 13, 17, 0, 0)},
+		{"end of week", endOf(time.March, 14)},
+		{"eow", endOf(time.March, 14)},
+		{"end of month", endOf(time.March, 31)},
+		{"end-of-month", endOf(time.March, 31)},
+		{"end of the year", endOf(time.December, 31)},
+		{"weekend", endOf(time.March, 15)},
+
+		// Absolute dates
+		{"2025-04-01", endOf(time.April, 1)},
+		{"2025-04-01 15:04", date(time.April, 1, 15, 4, 0)},
+		{"march 14", endOf(time.March, 14)},
+		{"14 march", endOf(time.March, 14)},
+		{"march 1", time.Date(2026, time.March, 1, 23, 59, 59, 0, time.UTC)},
+		{"march 1 2025", endOf(time.March, 1)},
+	}
+
+	for _, tt := range tests {
+		got, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got.Format(time.RFC1123), tt.want.Format(time.RFC1123))
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	parser := NewWithClock(func() time.Time { return now })
+
+	for _, input := range []string{
+		"",
+		"someday",
+		"3",
+		"13pm",
+		"25:00",
+		"friday monday",
+		"5pm 6pm",
+		"tonight 5pm",
+		"february 30",
+		"in 3 fortnights",
+	} {
+		if got, err := parser.Parse(input); err == nil {
+			t.Errorf("Parse(%q) = %s, want an error", input, got)
+		}
+	}
+}
+
+func TestParseCustomPeriods(t *testing.T) {
+	parser := NewWithClock(func() time.Time { return now })
+	parser.Periods["morning"] = TimeOfDay{Hour: 7, Minute: 30}
+	parser.DefaultTime = TimeOfDay{Hour: 9}
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"tomorrow morning", date(time.March, 13, 7, 30, 0)},
+		{"friday", date(time.March, 14, 9, 0, 0)},
+	}
+	for _, tt := range tests {
+		got, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package events broadcasts task changes and reminders to subscribers.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/user/todolist/internal/clock"
+	"github.com/user/todolist/internal/models"
+)
+
+// Type identifies the kind of an event
+type Type string
+
+const (
+	TaskCreated   Type = "task_created"
+	TaskUpdated   Type = "task_updated"
+	TaskDeleted   Type = "task_deleted"
+	TaskCompleted Type = "task_completed"
+	TasksRestored Type = "tasks_restored"
+	Reminder      Type = "reminder"
+
+	PomodoroStarted Type = "pomodoro_started"
+	PomodoroTick    Type = "pomodoro_tick"
+	PomodoroPhase   Type = "pomodoro_phase"
+	PomodoroPaused  Type = "pomodoro_paused"
+	PomodoroResumed Type = "pomodoro_resumed"
+	PomodoroUpdated Type = "pomodoro_updated"
+	PomodoroStopped Type = "pomodoro_stopped"
+)
+
+// subscriberBuffer is how many events a slow subscriber may fall behind
+// before further events are dropped for it
+const subscriberBuffer = 64
+
+// Event describes a change to the task list
+type Event struct {
+	Type     Type                   `json:"type"`
+	TaskID   string                 `json:"task_id,omitempty"`
+	Task     *models.Task           `json:"task,omitempty"`
+	Message  string                 `json:"message,omitempty"`
+	Pomodoro *models.PomodoroStatus `json:"pomodoro,omitempty"`
+	Time     time.Time              `json:"time"`
+}
+
+// Bus delivers published events to all current subscribers
+type Bus struct {
+	clock  clock.Clock
+	mu     sync.Mutex
+	subs   map[int]chan Event
+	nextID int
+}
+
+// NewBus creates an event bus without subscribers that stamps events with
+// the time of the given clock
+func NewBus(clk clock.Clock) *Bus {
+	return &Bus{clock: clk, subs: make(map[int]chan Event)}
+}
+
+// Subscribe returns a channel receiving every published event and a function
+// that ends the subscription and closes the channel
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, subscriberBuffer)
+	b.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+}
+
+// Publish sends an event to all subscribers without blocking. A nil bus
+// discards the event.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = b.clock.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// PublishPomodoro sends an event about the Pomodoro session
+func (b *Bus) PublishPomodoro(eventType Type, status models.PomodoroStatus) {
+	b.Publish(Event{Type: eventType, TaskID: status.TaskID, Pomodoro: &status})
+}
+
+// PublishTask sends an event about a task
+func (b *Bus) PublishTask(eventType Type, task *models.Task) {
+	b.Publish(Event{Type: eventType, TaskID: task.ID, Task: task})
+}
@@ -0,0 +1,112 @@
+package models
+
+// Dependencies indexes the "blocked by" links between tasks. Only open tasks
+// block others: a link to a completed or missing task is ignored.
+type Dependencies struct {
+	tasks    map[string]*Task
+	blocking map[string][]*Task
+}
+
+// NewDependencies indexes the dependencies between the given tasks
+func NewDependencies(tasks []*Task) *Dependencies {
+	d := &Dependencies{
+		tasks:    make(map[string]*Task, len(tasks)),
+		blocking: make(map[string][]*Task),
+	}
+	for _, task := range tasks {
+		d.tasks[task.ID] = task
+	}
+	for _, task := range tasks {
+		for _, id := range task.BlockedBy {
+			d.blocking[id] = append(d.blocking[id], task)
+		}
+	}
+	return d
+}
+
+// Blockers returns the open tasks that the task is waiting for
+func (d *Dependencies) Blockers(task *Task) []*Task {
+	var blockers []*Task
+	for _, id := range task.BlockedBy {
+		if blocker, ok := d.tasks[id]; ok && !blocker.Completed {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return blockers
+}
+
+// Blocking returns the open tasks that are waiting for the task
+func (d *Dependencies) Blocking(task *Task) []*Task {
+	if task.Completed {
+		return nil
+	}
+
+	var dependents []*Task
+	for _, dependent := range d.blocking[task.ID] {
+		if !dependent.Completed {
+			dependents = append(dependents, dependent)
+		}
+	}
+	return dependents
+}
+
+// IsBlocked checks if the task is waiting for at least one open task
+func (d *Dependencies) IsBlocked(task *Task) bool {
+	return len(d.Blockers(task)) > 0
+}
+
+// CreatesCycle checks if blocking the task with the given ID by blockerID
+// would make a task wait for itself, directly or through other tasks
+func (d *Dependencies) CreatesCycle(id, blockerID string) bool {
+	seen := make(map[string]bool)
+
+	var waitsFor func(taskID string) bool
+	waitsFor = func(taskID string) bool {
+		if taskID == id {
+			return true
+		}
+		if seen[taskID] {
+			return false
+		}
+		seen[taskID] = true
+
+		task, ok := d.tasks[taskID]
+		if !ok {
+			return false
+		}
+		for _, next := range task.BlockedBy {
+			if waitsFor(next) {
+				return true
+			}
+		}
+		return false
+	}
+	return waitsFor(blockerID)
+}
+
+// IsBlockedBy checks if the task lists the given task as a blocker
+func (t *Task) IsBlockedBy(id string) bool {
+	for _, blockerID := range t.BlockedBy {
+		if blockerID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveBlocker removes a task from the task's blockers and reports whether
+// it was listed
+func (t *Task) RemoveBlocker(id string) bool {
+	if !t.IsBlockedBy(id) {
+		return false
+	}
+
+	var remaining []string
+	for _, blockerID := range t.BlockedBy {
+		if blockerID != id {
+			remaining = append(remaining, blockerID)
+		}
+	}
+	t.BlockedBy = remaining
+	return true
+}
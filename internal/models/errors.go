@@ -0,0 +1,23 @@
+package models
+
+import "fmt"
+
+// ValidationError reports input that was rejected, such as an invalid
+// argument, flag or field value
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Invalidf returns a validation error with a formatted message, prefixed
+// with "invalid input: ". Like fmt.Errorf, it wraps errors given with %w.
+func Invalidf(format string, args ...interface{}) error {
+	return &ValidationError{Err: fmt.Errorf("invalid input: "+format, args...)}
+}
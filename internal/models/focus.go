@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// FocusOptions describes the current state of the user that focus suggestions
+// should fit, and how many suggestions are wanted
+type FocusOptions struct {
+	Limit     int           `json:"limit"`               // number of suggestions, 0 for all
+	Energy    Energy        `json:"energy,omitempty"`    // energy available, empty for any
+	Available time.Duration `json:"available,omitempty"` // time available, 0 for any
+	Tags      TagFilter     `json:"tags,omitempty"`      // tags to focus on
+	Project   string        `json:"project,omitempty"`   // project ID or name, empty for any
+}
+
+// ScoreFactor is one factor's contribution to a task's focus score
+type ScoreFactor struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+}
+
+// TaskScore is a focus suggestion: a task with its score and the factors
+// that make up the score
+type TaskScore struct {
+	Task    *Task         `json:"task"`
+	Score   float64       `json:"score"`
+	Factors []ScoreFactor `json:"factors,omitempty"`
+}
+
+// FocusState tracks how a task has fared as a focus suggestion
+type FocusState struct {
+	SnoozedUntil time.Time   `json:"snoozed_until,omitempty"`
+	Skips        []FocusSkip `json:"skips,omitempty"`
+}
+
+// FocusSkip records a time a task was suggested and skipped
+type FocusSkip struct {
+	At     time.Time `json:"at"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// IsFocusSnoozed checks if the task is kept out of focus suggestions at the given time
+func (t *Task) IsFocusSnoozed(now time.Time) bool {
+	return t.Focus != nil && now.Before(t.Focus.SnoozedUntil)
+}
+
+func (t *Task) focusState() FocusState {
+	if t.Focus == nil {
+		return FocusState{}
+	}
+	return *t.Focus
+}
+
+// SnoozeFocus keeps the task out of focus suggestions until the given time.
+// The zero time ends the snooze.
+func (t *Task) SnoozeFocus(until time.Time) {
+	state := t.focusState()
+	state.SnoozedUntil = until
+	t.Focus = &state
+}
+
+// SkipFocus records that the task was skipped as a focus suggestion and
+// snoozes it until the given time, if any
+func (t *Task) SkipFocus(now time.Time, reason string, until time.Time) {
+	state := t.focusState()
+	state.Skips = append(append([]FocusSkip(nil), state.Skips...), FocusSkip{At: now, Reason: reason})
+	if !until.IsZero() {
+		state.SnoozedUntil = until
+	}
+	t.Focus = &state
+}
+
+// FocusSkipCount returns how often the task was skipped as a focus suggestion
+func (t *Task) FocusSkipCount() int {
+	if t.Focus == nil {
+		return 0
+	}
+	return len(t.Focus.Skips)
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Note is a timestamped remark added to a task after it was created, such as
+// a phone number or the outcome of a call
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddNote appends a note to the task
+func (t *Task) AddNote(text string, now time.Time) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Invalidf("note cannot be empty")
+	}
+
+	// Copy so tasks sharing the slice are left untouched
+	notes := make([]Note, len(t.Notes), len(t.Notes)+1)
+	copy(notes, t.Notes)
+	t.Notes = append(notes, Note{Text: text, CreatedAt: now})
+	return nil
+}
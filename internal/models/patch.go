@@ -0,0 +1,128 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// TaskPatch describes a partial update to a task. Only fields that are set
+// are applied, so concurrent edits to different fields do not overwrite each
+// other. Setting DueDate or ReminderAt to the zero time, or Energy or Project
+// to an empty value, clears them.
+type TaskPatch struct {
+	Title           *string        `json:"title,omitempty"`
+	Description     *string        `json:"description,omitempty"`
+	Priority        *Priority      `json:"priority,omitempty"`
+	Category        *Category      `json:"category,omitempty"`
+	Project         *string        `json:"project,omitempty"`
+	DueDate         *time.Time     `json:"due_date,omitempty"`
+	ReminderAt      *time.Time     `json:"reminder_at,omitempty"`
+	Recurrence      *Recurrence    `json:"recurrence,omitempty"`
+	ClearRecurrence bool           `json:"clear_recurrence,omitempty"`
+	Tags            *[]string      `json:"tags,omitempty"`
+	Context         *string        `json:"context,omitempty"`
+	Estimate        *time.Duration `json:"estimate,omitempty"`
+	Energy          *Energy        `json:"energy,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p TaskPatch) IsEmpty() bool {
+	return p.Title == nil && p.Description == nil && p.Priority == nil && p.Category == nil && p.Project == nil &&
+		p.DueDate == nil && p.ReminderAt == nil && p.Recurrence == nil && !p.ClearRecurrence &&
+		p.Tags == nil && p.Context == nil && p.Estimate == nil && p.Energy == nil
+}
+
+// Validate checks the values in the patch
+func (p TaskPatch) Validate() error {
+	if p.Title != nil && strings.TrimSpace(*p.Title) == "" {
+		return Invalidf("title cannot be empty")
+	}
+
+	if p.Priority != nil {
+		switch *p.Priority {
+		case PriorityLow, PriorityMedium, PriorityHigh:
+		default:
+			return Invalidf("invalid priority: %s (must be low, medium, or high)", *p.Priority)
+		}
+	}
+
+	if p.Recurrence != nil {
+		if p.ClearRecurrence {
+			return Invalidf("cannot set and clear the repeat rule at the same time")
+		}
+		if err := p.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.Tags != nil {
+		if _, err := ParseTags(*p.Tags); err != nil {
+			return err
+		}
+	}
+
+	if p.Estimate != nil && *p.Estimate < 0 {
+		return Invalidf("estimate cannot be negative")
+	}
+
+	if p.Energy != nil && *p.Energy != "" {
+		if _, err := ParseEnergy(string(*p.Energy)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Apply validates the patch and applies it to the task
+func (p TaskPatch) Apply(task *Task) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	if p.Title != nil {
+		task.Title = strings.TrimSpace(*p.Title)
+	}
+	if p.Description != nil {
+		task.Description = *p.Description
+	}
+	if p.Priority != nil {
+		task.Priority = *p.Priority
+	}
+	if p.Category != nil {
+		task.Category = *p.Category
+	}
+	if p.Project != nil {
+		task.Project = *p.Project
+	}
+	if p.DueDate != nil {
+		task.DueDate = *p.DueDate
+	}
+	if p.ReminderAt != nil {
+		task.ReminderAt = *p.ReminderAt
+	}
+	if p.DueDate != nil || p.ReminderAt != nil {
+		// New dates start a fresh reminder cycle
+		task.Reminder = nil
+	}
+	if p.Recurrence != nil {
+		task.Recurrence = p.Recurrence
+	}
+	if p.ClearRecurrence {
+		task.Recurrence = nil
+	}
+	if p.Tags != nil {
+		task.Tags, _ = ParseTags(*p.Tags)
+	}
+	if p.Context != nil {
+		task.Context = *p.Context
+	}
+	if p.Estimate != nil {
+		task.Estimate = *p.Estimate
+	}
+	if p.Energy != nil {
+		task.Energy = *p.Energy
+	}
+
+	return nil
+}
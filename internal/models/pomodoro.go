@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// PomodoroPhase is the part of a Pomodoro cycle a session is in
+type PomodoroPhase string
+
+const (
+	PhaseWork       PomodoroPhase = "work"
+	PhaseShortBreak PomodoroPhase = "short_break"
+	PhaseLongBreak  PomodoroPhase = "long_break"
+)
+
+// IsBreak reports whether the phase is a short or long break
+func (p PomodoroPhase) IsBreak() bool {
+	return p == PhaseShortBreak || p == PhaseLongBreak
+}
+
+// PomodoroStatus is a snapshot of a Pomodoro session. Once the session has
+// ended it doubles as its summary.
+type PomodoroStatus struct {
+	Active          bool          `json:"active"`
+	TaskID          string        `json:"task_id,omitempty"`
+	TaskTitle       string        `json:"task_title,omitempty"`
+	Phase           PomodoroPhase `json:"phase,omitempty"`
+	Cycle           int           `json:"cycle,omitempty"`
+	Cycles          int           `json:"cycles,omitempty"`
+	CompletedCycles int           `json:"completed_cycles"`
+	Paused          bool          `json:"paused,omitempty"`
+	StopAfterPhase  bool          `json:"stop_after_phase,omitempty"`
+	Remaining       time.Duration `json:"remaining,omitempty"`
+	PhaseEnd        time.Time     `json:"phase_end,omitempty"`
+	StartedAt       time.Time     `json:"started_at,omitempty"`
+	EndedAt         time.Time     `json:"ended_at,omitempty"`
+	WorkTime        time.Duration `json:"work_time,omitempty"`
+	Interrupted     int           `json:"interrupted,omitempty"`
+	Interruptions   int           `json:"interruptions,omitempty"`
+}
+
+// PomodoroRecord is a work interval of a Pomodoro session. A work phase that
+// ran to the end is completed; one that was skipped or stopped early is
+// interrupted and carries the reason.
+type PomodoroRecord struct {
+	ID        string        `json:"id"`
+	TaskID    string        `json:"task_id"`
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end"`
+	Paused    time.Duration `json:"paused,omitempty"`
+	Completed bool          `json:"completed"`
+	Reason    string        `json:"reason,omitempty"`
+}
+
+// Duration returns the time actually worked during the interval
+func (r *PomodoroRecord) Duration() time.Duration {
+	d := r.End.Sub(r.Start) - r.Paused
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ProjectColors lists the colors a project can be shown in
+var ProjectColors = []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// Project groups tasks that work towards a common goal
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Deadline    time.Time `json:"deadline,omitempty"`
+	Color       string    `json:"color,omitempty"`
+	Archived    bool      `json:"archived"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewProject creates a new project with the given name, created at now
+func NewProject(name string, now time.Time) *Project {
+	return &Project{
+		ID:        newID(now),
+		Name:      strings.TrimSpace(name),
+		CreatedAt: now,
+	}
+}
+
+// Validate checks the project's name and color
+func (p *Project) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return Invalidf("project name cannot be empty")
+	}
+
+	if p.Color != "" {
+		valid := false
+		for _, color := range ProjectColors {
+			if p.Color == color {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return Invalidf("invalid color: %s (must be one of %s)", p.Color, strings.Join(ProjectColors, ", "))
+		}
+	}
+	return nil
+}
+
+// ProjectPatch describes a partial update to a project. Only fields that are
+// set are applied. Setting Deadline to the zero time, or Color to an empty
+// value, clears them.
+type ProjectPatch struct {
+	Name        *string    `json:"name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
+	Color       *string    `json:"color,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p ProjectPatch) IsEmpty() bool {
+	return p.Name == nil && p.Description == nil && p.Deadline == nil && p.Color == nil
+}
+
+// Apply applies the patch to the project and validates the result
+func (p ProjectPatch) Apply(project *Project) error {
+	if p.Name != nil {
+		project.Name = strings.TrimSpace(*p.Name)
+	}
+	if p.Description != nil {
+		project.Description = *p.Description
+	}
+	if p.Deadline != nil {
+		project.Deadline = *p.Deadline
+	}
+	if p.Color != nil {
+		project.Color = strings.ToLower(strings.TrimSpace(*p.Color))
+	}
+	return project.Validate()
+}
+
+// IsOverdue checks if the project's deadline has passed at the given time
+func (p *Project) IsOverdue(now time.Time) bool {
+	return !p.Deadline.IsZero() && now.After(p.Deadline) && !p.Archived
+}
+
+// FindProject finds a project by ID, or else by name ignoring case. It
+// returns nil if there is no such project.
+func FindProject(projects []*Project, ref string) *Project {
+	ref = strings.TrimSpace(ref)
+	for _, project := range projects {
+		if project.ID == ref {
+			return project
+		}
+	}
+	for _, project := range projects {
+		if strings.EqualFold(project.Name, ref) {
+			return project
+		}
+	}
+	return nil
+}
+
+// ProjectSummary describes the progress of a project
+type ProjectSummary struct {
+	Project    *Project `json:"project"`
+	Total      int      `json:"total"`
+	Done       int      `json:"done"`
+	Overdue    int      `json:"overdue"`
+	NextAction *Task    `json:"next_action,omitempty"`
+}
+
+// Percent returns the share of the project's tasks that are closed, from 0 to 100
+func (s ProjectSummary) Percent() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Done * 100 / s.Total
+}
@@ -0,0 +1,428 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency represents how often a recurring task repeats
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyYearly  Frequency = "yearly"
+)
+
+// Recurrence describes an RRULE-style repeat schedule for a task
+type Recurrence struct {
+	Frequency Frequency `json:"frequency"`
+	// Interval is the number of periods between occurrences (defaults to 1)
+	Interval int `json:"interval,omitempty"`
+	// Weekdays restricts weekly schedules to specific days of the week
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+	// AfterCompletion schedules the next occurrence relative to when the task
+	// was completed rather than to its due date
+	AfterCompletion bool `json:"after_completion,omitempty"`
+	// Start is the first scheduled occurrence of the series. Later occurrences
+	// are counted from it so that month-end dates do not drift.
+	Start time.Time `json:"start,omitempty"`
+}
+
+// Validate checks that the recurrence rule is well formed
+func (r *Recurrence) Validate() error {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return Invalidf("unknown repeat frequency %q", r.Frequency)
+	}
+
+	if r.Interval < 0 {
+		return Invalidf("repeat interval must be positive")
+	}
+
+	if len(r.Weekdays) > 0 && r.Frequency != FrequencyWeekly {
+		return Invalidf("weekdays can only be used with weekly repeats")
+	}
+
+	if len(r.Weekdays) > 0 && r.AfterCompletion {
+		return Invalidf("weekdays cannot be combined with 'after completion'")
+	}
+
+	return nil
+}
+
+// interval returns the interval, treating zero as one
+func (r *Recurrence) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+// Next returns the first occurrence strictly after from, keeping the time of day
+func (r *Recurrence) Next(from time.Time) time.Time {
+	return r.nth(from, 1)
+}
+
+// nth returns the k-th occurrence after from. Occurrences are always counted
+// from the original time so that month-end dates do not drift after a short
+// month.
+func (r *Recurrence) nth(from time.Time, k int) time.Time {
+	n := r.interval() * k
+
+	switch r.Frequency {
+	case FrequencyDaily:
+		return from.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		if len(r.Weekdays) == 0 {
+			return from.AddDate(0, 0, 7*n)
+		}
+
+		next := from
+		for i := 0; i < k; i++ {
+			next = r.nextWeekday(next)
+		}
+		return next
+	case FrequencyMonthly:
+		return addMonths(from, n)
+	case FrequencyYearly:
+		return addMonths(from, 12*n)
+	}
+
+	return from
+}
+
+// nextWeekday finds the next matching weekday, skipping Interval-1 weeks
+// once the current week (starting Monday) has no more matching days
+func (r *Recurrence) nextWeekday(from time.Time) time.Time {
+	days := make(map[time.Weekday]bool, len(r.Weekdays))
+	for _, day := range r.Weekdays {
+		days[day] = true
+	}
+
+	// Look for a later matching day in the current week
+	offset := (int(from.Weekday()) + 6) % 7 // days since Monday
+	for i := offset + 1; i < 7; i++ {
+		candidate := from.AddDate(0, 0, i-offset)
+		if days[candidate.Weekday()] {
+			return candidate
+		}
+	}
+
+	// Jump to the start of the next scheduled week and take the first match
+	weekStart := from.AddDate(0, 0, -offset+7*r.interval())
+	for i := 0; i < 7; i++ {
+		candidate := weekStart.AddDate(0, 0, i)
+		if days[candidate.Weekday()] {
+			return candidate
+		}
+	}
+
+	return from.AddDate(0, 0, 7*r.interval())
+}
+
+// addMonths adds months to t, clamping the day to the end of shorter months
+// so that e.g. January 31st is followed by the last day of February
+func addMonths(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	target := firstOfMonth.AddDate(0, months, 0)
+
+	lastDay := target.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return target.AddDate(0, 0, day-1)
+}
+
+// String returns a human-readable description of the recurrence
+func (r *Recurrence) String() string {
+	n := r.interval()
+
+	units := map[Frequency]string{
+		FrequencyDaily:   "day",
+		FrequencyWeekly:  "week",
+		FrequencyMonthly: "month",
+		FrequencyYearly:  "year",
+	}
+	unit := units[r.Frequency]
+
+	var desc string
+	if n == 1 {
+		desc = "every " + unit
+	} else {
+		desc = fmt.Sprintf("every %d %ss", n, unit)
+	}
+
+	if len(r.Weekdays) > 0 {
+		names := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			names[i] = day.String()[:3]
+		}
+		desc += " on " + strings.Join(names, ", ")
+	}
+
+	if r.AfterCompletion {
+		desc += " after completion"
+	}
+
+	return desc
+}
+
+// Expression returns the rule in a form accepted by ParseRecurrence
+func (r *Recurrence) Expression() string {
+	n := r.interval()
+
+	if len(r.Weekdays) > 0 {
+		if n == 1 {
+			names := make([]string, len(r.Weekdays))
+			for i, day := range r.Weekdays {
+				names[i] = strings.ToLower(day.String()[:3])
+			}
+			return "every " + strings.Join(names, ",")
+		}
+
+		codes := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			codes[i] = strings.ToUpper(day.String()[:2])
+		}
+		return fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d;BYDAY=%s", n, strings.Join(codes, ","))
+	}
+
+	units := map[Frequency]string{
+		FrequencyDaily:   "days",
+		FrequencyWeekly:  "weeks",
+		FrequencyMonthly: "months",
+		FrequencyYearly:  "years",
+	}
+
+	expr := fmt.Sprintf("every %d %s", n, units[r.Frequency])
+	if r.AfterCompletion {
+		expr += " after completion"
+	}
+	return expr
+}
+
+// NextOccurrence creates the next occurrence of a recurring task completed at
+// the given time, or nil if the task does not repeat. Occurrences that would
+// already be in the past are skipped. The reminder keeps the same offset from
+// the due date as on the original task.
+func NextOccurrence(task *Task, completedAt time.Time) *Task {
+	if task.Recurrence == nil {
+		return nil
+	}
+	rec := *task.Recurrence
+
+	// Schedule from the due date, falling back to the reminder if there is none
+	anchor := task.DueDate
+	if anchor.IsZero() {
+		anchor = task.ReminderAt
+	}
+
+	var next time.Time
+	switch {
+	case anchor.IsZero() || rec.AfterCompletion:
+		next = rec.Next(completedAt)
+	case len(rec.Weekdays) > 0:
+		next = rec.Next(anchor)
+		for !next.After(completedAt) {
+			next = rec.Next(next)
+		}
+	default:
+		if rec.Start.IsZero() || rec.Start.After(anchor) {
+			rec.Start = anchor
+		}
+		next = rec.nth(rec.Start, 1)
+		for k := 2; !next.After(anchor) || !next.After(completedAt); k++ {
+			next = rec.nth(rec.Start, k)
+		}
+	}
+
+	var dueDate, reminderAt time.Time
+	switch {
+	case !task.DueDate.IsZero():
+		dueDate = next
+		if !task.ReminderAt.IsZero() {
+			reminderAt = next.Add(task.ReminderAt.Sub(task.DueDate))
+		}
+	case !task.ReminderAt.IsZero():
+		reminderAt = next
+	default:
+		dueDate = next
+	}
+
+	occurrence := NewTaskFromSpec(TaskSpec{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		Category:    task.Category,
+		DueDate:     dueDate,
+		ReminderAt:  reminderAt,
+		Recurrence:  &rec,
+		Tags:        task.Tags,
+		Context:     task.Context,
+		Estimate:    task.Estimate,
+		Energy:      task.Energy,
+		Project:     task.Project,
+	}, completedAt)
+	occurrence.ParentID = task.ParentID
+
+	return occurrence
+}
+
+// weekdayNames maps accepted weekday spellings to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday, "su": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday, "mo": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday, "tu": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday, "we": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday, "th": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday, "fr": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday, "sa": time.Saturday,
+}
+
+// ParseRecurrence parses a repeat expression. It understands plain frequencies
+// ("daily", "weekly", "monthly", "yearly", "weekdays"), "every" phrases
+// ("every 2 weeks", "every mon,wed,fri"), completion-relative rules
+// ("3 days after completion") and RRULE syntax ("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH").
+func ParseRecurrence(input string) (*Recurrence, error) {
+	expr := strings.ToLower(strings.TrimSpace(input))
+	if expr == "" {
+		return nil, Invalidf("empty repeat rule")
+	}
+
+	var rec *Recurrence
+	var err error
+	if strings.Contains(expr, "freq=") {
+		rec, err = parseRRule(expr)
+	} else {
+		rec, err = parseRepeatPhrase(expr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rec.Validate(); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// parseRRule parses the subset of RFC 5545 RRULE syntax supported by Recurrence
+func parseRRule(expr string) (*Recurrence, error) {
+	expr = strings.TrimPrefix(expr, "rrule:")
+	rec := &Recurrence{}
+
+	for _, part := range strings.Split(expr, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, Invalidf("malformed repeat rule part %q", part)
+		}
+
+		switch key {
+		case "freq":
+			rec.Frequency = Frequency(value)
+		case "interval":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, Invalidf("bad repeat interval %q", value)
+			}
+			rec.Interval = n
+		case "byday":
+			for _, name := range strings.Split(value, ",") {
+				day, ok := weekdayNames[name]
+				if !ok {
+					return nil, Invalidf("unknown weekday %q", name)
+				}
+				rec.Weekdays = append(rec.Weekdays, day)
+			}
+		default:
+			return nil, Invalidf("unsupported repeat rule part %q", key)
+		}
+	}
+
+	return rec, nil
+}
+
+// parseRepeatPhrase parses an English repeat phrase
+func parseRepeatPhrase(expr string) (*Recurrence, error) {
+	rec := &Recurrence{}
+
+	if strings.HasSuffix(expr, "after completion") {
+		rec.AfterCompletion = true
+		expr = strings.TrimSpace(strings.TrimSuffix(expr, "after completion"))
+	}
+	expr = strings.TrimSpace(strings.TrimPrefix(expr, "every"))
+	if expr == "" {
+		return nil, Invalidf("repeat rule does not say how often to repeat (try 'daily', 'every 2 weeks' or 'every mon,fri')")
+	}
+
+	switch expr {
+	case "daily", "day":
+		rec.Frequency = FrequencyDaily
+		return rec, nil
+	case "weekly", "week":
+		rec.Frequency = FrequencyWeekly
+		return rec, nil
+	case "monthly", "month":
+		rec.Frequency = FrequencyMonthly
+		return rec, nil
+	case "yearly", "annually", "year":
+		rec.Frequency = FrequencyYearly
+		return rec, nil
+	case "weekday", "weekdays":
+		rec.Frequency = FrequencyWeekly
+		rec.Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return rec, nil
+	case "weekend", "weekends":
+		rec.Frequency = FrequencyWeekly
+		rec.Weekdays = []time.Weekday{time.Saturday, time.Sunday}
+		return rec, nil
+	}
+
+	// "<n> <unit>" such as "2 weeks" or "3 days"
+	fields := strings.Fields(expr)
+	if len(fields) == 2 {
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			if n < 1 {
+				return nil, Invalidf("repeat interval must be positive")
+			}
+			rec.Interval = n
+
+			switch strings.TrimSuffix(fields[1], "s") {
+			case "day":
+				rec.Frequency = FrequencyDaily
+			case "week":
+				rec.Frequency = FrequencyWeekly
+			case "month":
+				rec.Frequency = FrequencyMonthly
+			case "year":
+				rec.Frequency = FrequencyYearly
+			default:
+				return nil, Invalidf("unknown repeat unit %q", fields[1])
+			}
+			return rec, nil
+		}
+	}
+
+	// A list of weekday names such as "mon,wed,fri" or "monday and thursday"
+	expr = strings.NewReplacer(",", " ", " and ", " ").Replace(expr)
+	for _, name := range strings.Fields(expr) {
+		day, ok := weekdayNames[strings.TrimSuffix(name, "s")]
+		if !ok {
+			day, ok = weekdayNames[name]
+		}
+		if !ok {
+			return nil, Invalidf("unknown repeat rule %q (try 'daily', 'every 2 weeks', 'every mon,fri' or '3 days after completion')", expr)
+		}
+		rec.Weekdays = append(rec.Weekdays, day)
+	}
+	rec.Frequency = FrequencyWeekly
+
+	return rec, nil
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestParseRecurrence(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	tests := []struct {
+		input string
+		want  Recurrence
+	}{
+		{"daily", Recurrence{Frequency: FrequencyDaily}},
+		{"Every Month", Recurrence{Frequency: FrequencyMonthly}},
+		{"annually", Recurrence{Frequency: FrequencyYearly}},
+		{"weekdays", Recurrence{Frequency: FrequencyWeekly, Weekdays: weekdays}},
+		{"every 2 weeks", Recurrence{Frequency: FrequencyWeekly, Interval: 2}},
+		{"every mon,fri", Recurrence{Frequency: FrequencyWeekly, Weekdays: []time.Weekday{time.Monday, time.Friday}}},
+		{"every tuesdays and thursdays", Recurrence{Frequency: FrequencyWeekly, Weekdays: []time.Weekday{time.Tuesday, time.Thursday}}},
+		{"3 days after completion", Recurrence{Frequency: FrequencyDaily, Interval: 3, AfterCompletion: true}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", Recurrence{Frequency: FrequencyWeekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Thursday}}},
+		{"RRULE:FREQ=MONTHLY", Recurrence{Frequency: FrequencyMonthly}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRecurrence(tt.input)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("ParseRecurrence(%q) = %+v, want %+v", tt.input, *got, tt.want)
+		}
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	inputs := []string{
+		"",
+		"every",
+		"after completion",
+		"every 0 days",
+		"every 2 fortnights",
+		"every someday",
+		"FREQ=HOURLY",
+		"FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=WEEKLY;COUNT=3",
+		"every mon after completion",
+	}
+	for _, input := range inputs {
+		if rec, err := ParseRecurrence(input); err == nil {
+			t.Errorf("ParseRecurrence(%q) = %+v, want an error", input, *rec)
+		} else if !errors.As(err, new(*ValidationError)) {
+			t.Errorf("ParseRecurrence(%q) failed with %v, want a validation error", input, err)
+		}
+	}
+}
+
+// TestRecurrenceExpression checks that every rule survives a round trip
+// through the expression stored for display and editing
+func TestRecurrenceExpression(t *testing.T) {
+	inputs := []string{
+		"daily",
+		"every 3 months",
+		"yearly",
+		"weekends",
+		"every mon,wed",
+		"2 weeks after completion",
+		"FREQ=WEEKLY;INTERVAL=3;BYDAY=TU,SA",
+	}
+	for _, input := range inputs {
+		rec, err := ParseRecurrence(input)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q) failed: %v", input, err)
+		}
+		again, err := ParseRecurrence(rec.Expression())
+		if err != nil {
+			t.Errorf("expression %q of %q does not parse: %v", rec.Expression(), input, err)
+			continue
+		}
+		if again.interval() != rec.interval() || again.Frequency != rec.Frequency ||
+			again.AfterCompletion != rec.AfterCompletion || !reflect.DeepEqual(again.Weekdays, rec.Weekdays) {
+			t.Errorf("%q became %q, which parses as %+v instead of %+v", input, rec.Expression(), *again, *rec)
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		due         time.Time
+		reminder    time.Time
+		completedAt time.Time
+		// Due dates of the following occurrences, each completed on its due date
+		want []time.Time
+	}{
+		{"month end is clamped and restored", "monthly", date(2025, time.January, 31, 9), time.Time{}, date(2025, time.January, 31, 10),
+			[]time.Time{date(2025, time.February, 28, 9), date(2025, time.March, 31, 9), date(2025, time.April, 30, 9)}},
+		{"leap day", "yearly", date(2024, time.February, 29, 9), time.Time{}, date(2024, time.February, 29, 10),
+			[]time.Time{date(2025, time.February, 28, 9), date(2026, time.February, 28, 9)}},
+		// Monday 10 March 2025; the next Thursday, then Monday two weeks on
+		{"weekdays every other week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", date(2025, time.March, 10, 9), time.Time{}, date(2025, time.March, 10, 10),
+			[]time.Time{date(2025, time.March, 13, 9), date(2025, time.March, 24, 9), date(2025, time.March, 27, 9)}},
+		{"after completion counts from the completion", "3 days after completion", date(2025, time.March, 1, 9), time.Time{}, date(2025, time.March, 10, 14),
+			[]time.Time{date(2025, time.March, 13, 14)}},
+		{"missed occurrences are skipped", "daily", date(2025, time.March, 1, 9), time.Time{}, date(2025, time.March, 5, 12),
+			[]time.Time{date(2025, time.March, 6, 9), date(2025, time.March, 7, 9)}},
+		{"without a due date the reminder is moved", "weekly", time.Time{}, date(2025, time.March, 10, 8), date(2025, time.March, 10, 9),
+			[]time.Time{date(2025, time.March, 17, 8)}},
+	}
+	for _, tt := range tests {
+		rec, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		task := &Task{Title: tt.name, DueDate: tt.due, ReminderAt: tt.reminder, Recurrence: rec}
+		completedAt := tt.completedAt
+		for i, want := range tt.want {
+			next := NextOccurrence(task, completedAt)
+			if next == nil {
+				t.Fatalf("%s: no occurrence %d", tt.name, i+1)
+			}
+			got := next.DueDate
+			if tt.due.IsZero() {
+				got = next.ReminderAt
+			}
+			if !got.Equal(want) {
+				t.Errorf("%s: occurrence %d at %s, want %s", tt.name, i+1, got, want)
+				break
+			}
+			task, completedAt = next, got
+		}
+	}
+}
+
+func TestNextOccurrenceKeepsReminderOffset(t *testing.T) {
+	rec, _ := ParseRecurrence("weekly")
+	task := &Task{
+		Title:      "Team meeting",
+		DueDate:    date(2025, time.March, 10, 9),
+		ReminderAt: date(2025, time.March, 10, 8),
+		Tags:       []string{"work"},
+		Recurrence: rec,
+	}
+
+	next := NextOccurrence(task, date(2025, time.March, 10, 9))
+	if !next.DueDate.Equal(date(2025, time.March, 17, 9)) || !next.ReminderAt.Equal(date(2025, time.March, 17, 8)) {
+		t.Errorf("next occurrence due %s with reminder %s, want the 17th at 9:00 and 8:00", next.DueDate, next.ReminderAt)
+	}
+	if next.Title != task.Title || len(next.Tags) != 1 || next.Recurrence == nil || next.Completed {
+		t.Errorf("next occurrence %+v does not carry over the task", next)
+	}
+
+	if NextOccurrence(&Task{Title: "Once", DueDate: task.DueDate}, task.DueDate) != nil {
+		t.Error("a task without a repeat rule has a next occurrence")
+	}
+}
@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+// ReminderState tracks the delivery of a task's reminders
+type ReminderState struct {
+	NotifiedAt     time.Time `json:"notified_at,omitempty"`
+	SnoozedUntil   time.Time `json:"snoozed_until,omitempty"`
+	AcknowledgedAt time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// alertTimes returns the times at which a task alerts: its reminder and due date
+func (t *Task) alertTimes() []time.Time {
+	var times []time.Time
+	if !t.ReminderAt.IsZero() {
+		times = append(times, t.ReminderAt)
+	}
+	if !t.DueDate.IsZero() {
+		times = append(times, t.DueDate)
+	}
+	return times
+}
+
+func (t *Task) reminderState() ReminderState {
+	if t.Reminder == nil {
+		return ReminderState{}
+	}
+	return *t.Reminder
+}
+
+// PendingAlert returns the alert that has passed and not been acknowledged yet.
+// A snoozed alert becomes pending again once the snooze expires.
+func (t *Task) PendingAlert(now time.Time) (time.Time, bool) {
+	if t.Completed {
+		return time.Time{}, false
+	}
+
+	state := t.reminderState()
+	if now.Before(state.SnoozedUntil) {
+		return time.Time{}, false
+	}
+
+	var latest time.Time
+	for _, at := range t.alertTimes() {
+		if !at.After(now) && at.After(state.AcknowledgedAt) && at.After(latest) {
+			latest = at
+		}
+	}
+	if latest.IsZero() {
+		return time.Time{}, false
+	}
+
+	if state.SnoozedUntil.After(latest) {
+		latest = state.SnoozedUntil
+	}
+	return latest, true
+}
+
+// NextAlert returns the next time after now at which the task alerts, or the
+// zero time if there is none
+func (t *Task) NextAlert(now time.Time) time.Time {
+	if t.Completed {
+		return time.Time{}
+	}
+
+	var next time.Time
+	for _, at := range append(t.alertTimes(), t.reminderState().SnoozedUntil) {
+		if at.After(now) && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	return next
+}
+
+// AlertKind describes the pending alert: "due" once the due date has passed,
+// otherwise "reminder"
+func (t *Task) AlertKind(now time.Time) string {
+	if !t.DueDate.IsZero() && !now.Before(t.DueDate) {
+		return "due"
+	}
+	return "reminder"
+}
+
+// MarkNotified records that the task's pending alert was delivered
+func (t *Task) MarkNotified(now time.Time) {
+	state := t.reminderState()
+	state.NotifiedAt = now
+	t.Reminder = &state
+}
+
+// Snooze silences the task's alerts until the given time
+func (t *Task) Snooze(until time.Time) {
+	state := t.reminderState()
+	state.SnoozedUntil = until
+	t.Reminder = &state
+}
+
+// AcknowledgeAlerts marks every alert up to now as seen
+func (t *Task) AcknowledgeAlerts(now time.Time) {
+	t.Reminder = &ReminderState{AcknowledgedAt: now, NotifiedAt: t.reminderState().NotifiedAt}
+}
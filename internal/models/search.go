@@ -0,0 +1,27 @@
+package models
+
+// SearchQuery is a free-text search over the title, description and notes of
+// tasks
+type SearchQuery struct {
+	Text string `json:"text"`
+	// Limit caps the number of results; zero returns all of them
+	Limit int `json:"limit,omitempty"`
+	// All includes done and cancelled tasks
+	All bool `json:"all,omitempty"`
+}
+
+// SearchResult is a task matching a search, with its relevance score and the
+// passages that matched
+type SearchResult struct {
+	Task     *Task     `json:"task"`
+	Score    float64   `json:"score"`
+	Snippets []Snippet `json:"snippets,omitempty"`
+}
+
+// Snippet is a passage of a task field. Matches holds the byte ranges of the
+// words that matched the search, as [start, end) pairs into Text.
+type Snippet struct {
+	Field   string   `json:"field"`
+	Text    string   `json:"text"`
+	Matches [][2]int `json:"matches"`
+}
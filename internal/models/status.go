@@ -0,0 +1,122 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Status is where a task is in its lifecycle
+type Status string
+
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in_progress"
+	StatusWaiting    Status = "waiting"
+	StatusBlocked    Status = "blocked"
+	StatusDone       Status = "done"
+	StatusCancelled  Status = "cancelled"
+)
+
+// Statuses lists all statuses in lifecycle order
+var Statuses = []Status{StatusTodo, StatusInProgress, StatusWaiting, StatusBlocked, StatusDone, StatusCancelled}
+
+// ParseStatus parses a status. Common spellings such as "in-progress",
+// "doing", "completed" and "wontdo" are accepted as well.
+func ParseStatus(value string) (Status, error) {
+	normalized := strings.NewReplacer("-", "_", " ", "_", "'", "").Replace(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case "todo", "open":
+		return StatusTodo, nil
+	case "in_progress", "inprogress", "doing", "started", "wip":
+		return StatusInProgress, nil
+	case "waiting", "waiting_on", "on_hold":
+		return StatusWaiting, nil
+	case "blocked":
+		return StatusBlocked, nil
+	case "done", "completed", "complete":
+		return StatusDone, nil
+	case "cancelled", "canceled", "wontdo", "wont_do", "dropped":
+		return StatusCancelled, nil
+	}
+	return "", Invalidf("invalid status: %s (must be todo, in_progress, waiting, blocked, done, or cancelled)", value)
+}
+
+// IsClosed checks if the status ends the task: done or cancelled
+func (s Status) IsClosed() bool {
+	return s == StatusDone || s == StatusCancelled
+}
+
+// IsOnHold checks if the task is open but cannot be worked on right now
+func (s Status) IsOnHold() bool {
+	return s == StatusWaiting || s == StatusBlocked
+}
+
+// String returns the status as shown to users, e.g. "in progress"
+func (s Status) String() string {
+	return strings.ReplaceAll(string(s), "_", " ")
+}
+
+// Marker returns the checkbox shown in front of a task with this status
+func (s Status) Marker() string {
+	switch s {
+	case StatusInProgress:
+		return "[>]"
+	case StatusWaiting, StatusBlocked:
+		return "[…]"
+	case StatusDone:
+		return "[✓]"
+	case StatusCancelled:
+		return "[✗]"
+	}
+	return "[ ]"
+}
+
+// StatusChange records a transition between two statuses
+type StatusChange struct {
+	From Status    `json:"from,omitempty"`
+	To   Status    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+// SetStatus moves the task to a new status at the given time and records the
+// transition. Closing a task sets CompletedAt; reopening it clears it.
+func (t *Task) SetStatus(status Status, now time.Time) {
+	t.MigrateStatus()
+	if t.Status == status {
+		return
+	}
+
+	history := append([]StatusChange(nil), t.StatusHistory...)
+	t.StatusHistory = append(history, StatusChange{From: t.Status, To: status, At: now})
+	t.Status = status
+	t.Completed = status.IsClosed()
+	if t.Completed {
+		t.CompletedAt = now
+	} else {
+		t.CompletedAt = time.Time{}
+	}
+}
+
+// StatusSince returns when the task entered its current status. It returns
+// the zero time for tasks closed before status changes were recorded.
+func (t *Task) StatusSince() time.Time {
+	if len(t.StatusHistory) > 0 {
+		return t.StatusHistory[len(t.StatusHistory)-1].At
+	}
+	if t.Status == StatusTodo {
+		return t.CreatedAt
+	}
+	return time.Time{}
+}
+
+// MigrateStatus fills in the status of a task stored before statuses existed,
+// and keeps Completed in line with the status for older clients
+func (t *Task) MigrateStatus() {
+	if t.Status == "" {
+		t.Status = StatusTodo
+		if t.Completed {
+			t.Status = StatusDone
+		}
+	}
+	t.Completed = t.Status.IsClosed()
+}
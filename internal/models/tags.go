@@ -0,0 +1,122 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeTag lowercases a tag and strips surrounding spaces and the "+"
+// used for tags in quick-add syntax
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+}
+
+// ParseTags normalizes tags and removes duplicates, keeping their order.
+// Tags must not be empty or contain spaces or commas.
+func ParseTags(tags []string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || strings.Contains(tag, ",") || strings.IndexFunc(tag, unicode.IsSpace) >= 0 {
+			return nil, Invalidf("invalid tag %q (tags cannot be empty or contain spaces or commas)", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// HasTag checks if the task has the given tag
+func (t *Task) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, own := range t.Tags {
+		if own == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds tags the task does not have yet and reports whether any were added
+func (t *Task) AddTags(tags ...string) bool {
+	updated := append([]string(nil), t.Tags...)
+	for _, tag := range tags {
+		if !t.HasTag(tag) {
+			updated = append(updated, NormalizeTag(tag))
+		}
+	}
+
+	changed := len(updated) != len(t.Tags)
+	t.Tags = updated
+	return changed
+}
+
+// RemoveTags removes tags from the task and reports whether any were removed
+func (t *Task) RemoveTags(tags ...string) bool {
+	remove := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		remove[NormalizeTag(tag)] = true
+	}
+
+	var updated []string
+	for _, own := range t.Tags {
+		if !remove[own] {
+			updated = append(updated, own)
+		}
+	}
+
+	changed := len(updated) != len(t.Tags)
+	t.Tags = updated
+	return changed
+}
+
+// TagFilter selects tasks by their tags. Empty lists match every task.
+type TagFilter struct {
+	Any  []string `json:"any,omitempty"`  // at least one of these tags
+	All  []string `json:"all,omitempty"`  // every one of these tags
+	None []string `json:"none,omitempty"` // none of these tags
+}
+
+// IsEmpty reports whether the filter matches every task
+func (f TagFilter) IsEmpty() bool {
+	return len(f.Any) == 0 && len(f.All) == 0 && len(f.None) == 0
+}
+
+// Matches checks if the task's tags pass the filter
+func (f TagFilter) Matches(task *Task) bool {
+	if len(f.Any) > 0 {
+		found := false
+		for _, tag := range f.Any {
+			if task.HasTag(tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, tag := range f.All {
+		if !task.HasTag(tag) {
+			return false
+		}
+	}
+
+	for _, tag := range f.None {
+		if task.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// TagCount is how many tasks carry a tag, and how many of them are still open
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+	Open  int    `json:"open"`
+}
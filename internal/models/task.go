@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,58 +16,193 @@ const (
 	PriorityHigh   Priority = "high"
 )
 
+// Energy is how much mental energy a task takes
+type Energy string
+
+const (
+	EnergyLow    Energy = "low"
+	EnergyMedium Energy = "medium"
+	EnergyHigh   Energy = "high"
+)
+
+// ParseEnergy parses an energy level; "l", "m" and "h" are accepted as well
+func ParseEnergy(value string) (Energy, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "low", "l":
+		return EnergyLow, nil
+	case "medium", "med", "m":
+		return EnergyMedium, nil
+	case "high", "h":
+		return EnergyHigh, nil
+	}
+	return "", Invalidf("invalid energy level: %s (must be low, medium, or high)", value)
+}
+
+// Level returns the energy as a number from 1 (low) to 3 (high), or 0 if unset
+func (e Energy) Level() int {
+	switch e {
+	case EnergyLow:
+		return 1
+	case EnergyMedium:
+		return 2
+	case EnergyHigh:
+		return 3
+	}
+	return 0
+}
+
 // Category represents the grouping of a task
 type Category string
 
 // Task represents a to-do item
 type Task struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Priority    Priority  `json:"priority"`
-	Category    Category  `json:"category"`
-	DueDate     time.Time `json:"due_date"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	ReminderAt  time.Time `json:"reminder_at"`
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Priority    Priority       `json:"priority"`
+	Category    Category       `json:"category"`
+	Project     string         `json:"project,omitempty"`
+	DueDate     time.Time      `json:"due_date"`
+	Completed   bool           `json:"completed"`
+	Status      Status         `json:"status"`
+	CreatedAt   time.Time      `json:"created_at"`
+	CompletedAt time.Time      `json:"completed_at,omitempty"`
+	ReminderAt  time.Time      `json:"reminder_at"`
+	ParentID    string         `json:"parent_id,omitempty"`
+	BlockedBy   []string       `json:"blocked_by,omitempty"`
+	Recurrence  *Recurrence    `json:"recurrence,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Context     string         `json:"context,omitempty"`
+	Estimate    time.Duration  `json:"estimate,omitempty"`
+	Energy      Energy         `json:"energy,omitempty"`
+	TimeSpent   time.Duration  `json:"time_spent,omitempty"`
+	Reminder    *ReminderState `json:"reminder,omitempty"`
+	Focus       *FocusState    `json:"focus,omitempty"`
+
+	StatusHistory []StatusChange `json:"status_history,omitempty"`
+	Notes         []Note         `json:"notes,omitempty"`
+}
+
+// TaskSpec holds the user-supplied fields used to create a new task
+type TaskSpec struct {
+	Title       string
+	Description string
+	Priority    Priority
+	Category    Category
+	Project     string
+	DueDate     time.Time
+	ReminderAt  time.Time
+	Recurrence  *Recurrence
+	Tags        []string
+	Context     string
+	Estimate    time.Duration
+	Energy      Energy
+	BlockedBy   []string
+}
+
+// lastID is the most recent ID handed out by newID
+var lastID int64
+
+// newID returns an ID taken from the given time. IDs handed out by this
+// process always increase, even when the clock does not move.
+func newID(now time.Time) string {
+	for {
+		last := atomic.LoadInt64(&lastID)
+		id := now.UnixNano()
+		if id <= last {
+			id = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&lastID, last, id) {
+			return fmt.Sprintf("%d", id)
+		}
+	}
 }
 
-// NewTask creates a new task with the given parameters
-func NewTask(title, description string, priority Priority, category Category, dueDate, reminderAt time.Time) *Task {
+// NewTask creates a new task with the given parameters, created at now
+func NewTask(title, description string, priority Priority, category Category, dueDate, reminderAt, now time.Time) *Task {
 	return &Task{
-		ID:          fmt.Sprintf("%d", time.Now().UnixNano()), // Simple ID generation
+		ID:          newID(now),
 		Title:       title,
 		Description: description,
 		Priority:    priority,
 		Category:    category,
 		DueDate:     dueDate,
 		Completed:   false,
-		CreatedAt:   time.Now(),
+		Status:      StatusTodo,
+		CreatedAt:   now,
 		ReminderAt:  reminderAt,
 	}
 }
 
-// IsOverdue checks if the task is past its due date
-func (t *Task) IsOverdue() bool {
-	return !t.DueDate.IsZero() && time.Now().After(t.DueDate) && !t.Completed
+// NewTaskFromSpec creates a new task from the given specification, created
+// at now
+func NewTaskFromSpec(spec TaskSpec, now time.Time) *Task {
+	task := NewTask(spec.Title, spec.Description, spec.Priority, spec.Category, spec.DueDate, spec.ReminderAt, now)
+	task.Recurrence = spec.Recurrence
+	task.Tags = spec.Tags
+	task.Context = spec.Context
+	task.Estimate = spec.Estimate
+	task.Energy = spec.Energy
+	task.BlockedBy = spec.BlockedBy
+	task.Project = spec.Project
+	return task
 }
 
-// IsReminderDue checks if it's time to remind about this task
-func (t *Task) IsReminderDue() bool {
-	return !t.ReminderAt.IsZero() && time.Now().After(t.ReminderAt) && !t.Completed
+// Clone returns a deep copy of the task, so changing the copy leaves the
+// original untouched
+func (t *Task) Clone() *Task {
+	c := *t
+	if t.BlockedBy != nil {
+		c.BlockedBy = append([]string{}, t.BlockedBy...)
+	}
+	if t.Tags != nil {
+		c.Tags = append([]string{}, t.Tags...)
+	}
+	if t.Recurrence != nil {
+		recurrence := *t.Recurrence
+		if t.Recurrence.Weekdays != nil {
+			recurrence.Weekdays = append([]time.Weekday{}, t.Recurrence.Weekdays...)
+		}
+		c.Recurrence = &recurrence
+	}
+	if t.Reminder != nil {
+		reminder := *t.Reminder
+		c.Reminder = &reminder
+	}
+	if t.Focus != nil {
+		focus := *t.Focus
+		if t.Focus.Skips != nil {
+			focus.Skips = append([]FocusSkip{}, t.Focus.Skips...)
+		}
+		c.Focus = &focus
+	}
+	if t.StatusHistory != nil {
+		c.StatusHistory = append([]StatusChange{}, t.StatusHistory...)
+	}
+	if t.Notes != nil {
+		c.Notes = append([]Note{}, t.Notes...)
+	}
+	return &c
+}
+
+// IsOverdue checks if the task is past its due date at the given time
+func (t *Task) IsOverdue(now time.Time) bool {
+	return !t.DueDate.IsZero() && now.After(t.DueDate) && !t.Completed
+}
+
+// IsReminderDue checks if it's time to remind about this task at the given time
+func (t *Task) IsReminderDue(now time.Time) bool {
+	return !t.ReminderAt.IsZero() && now.After(t.ReminderAt) && !t.Completed
 }
 
-// MarkComplete marks the task as completed
-func (t *Task) MarkComplete() {
-	t.Completed = true
+// MarkComplete marks the task as done at the given time
+func (t *Task) MarkComplete(now time.Time) {
+	t.SetStatus(StatusDone, now)
 }
 
 // String returns a string representation of the task
 func (t *Task) String() string {
-	status := "[ ]"
-	if t.Completed {
-		status = "[✓]"
-	}
+	status := t.Status.Marker()
 
 	dueStr := "No due date"
 	if !t.DueDate.IsZero() {
@@ -75,3 +212,22 @@ func (t *Task) String() string {
 	return fmt.Sprintf("%s %s (Priority: %s, Category: %s, Due: %s)",
 		status, t.Title, t.Priority, t.Category, dueStr)
 }
+
+// TaskQuery selects a page of tasks with a query expression, such as
+// "priority:high due:<friday -tag:someday", sorted by a comma-separated list
+// of fields ("due,-priority")
+type TaskQuery struct {
+	Where  string `json:"where,omitempty"`
+	Sort   string `json:"sort,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// TaskPage is a page of tasks matching a query, with the total number of
+// matching tasks
+type TaskPage struct {
+	Tasks  []*Task `json:"tasks"`
+	Total  int     `json:"total"`
+	Offset int     `json:"offset"`
+	Limit  int     `json:"limit"`
+}
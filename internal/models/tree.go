@@ -0,0 +1,112 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TaskNode represents a task together with its nested subtasks
+type TaskNode struct {
+	Task     *Task
+	Children []*TaskNode
+}
+
+// BuildTaskTree arranges tasks into a forest using their parent links.
+// Tasks whose parent is not part of the given list are treated as roots.
+// Siblings are ordered by creation time.
+func BuildTaskTree(tasks []*Task) []*TaskNode {
+	nodes := make(map[string]*TaskNode, len(tasks))
+	for _, task := range tasks {
+		nodes[task.ID] = &TaskNode{Task: task}
+	}
+
+	var roots []*TaskNode
+	for _, task := range tasks {
+		node := nodes[task.ID]
+		if parent, ok := nodes[task.ParentID]; ok && task.ParentID != task.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+// sortNodes orders nodes and their children by creation time
+func sortNodes(nodes []*TaskNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Task.CreatedAt.Before(nodes[j].Task.CreatedAt)
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+// Progress returns how many of the node's descendants are completed, and how
+// many descendants it has in total
+func (n *TaskNode) Progress() (done, total int) {
+	for _, child := range n.Children {
+		total++
+		if child.Task.Completed {
+			done++
+		}
+
+		childDone, childTotal := child.Progress()
+		done += childDone
+		total += childTotal
+	}
+	return done, total
+}
+
+// ProgressString returns a short roll-up such as "3/7 steps done", or an empty
+// string if the node has no subtasks
+func (n *TaskNode) ProgressString() string {
+	done, total := n.Progress()
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d steps done", done, total)
+}
+
+// Descendants returns all tasks nested under the task with the given ID,
+// ordered so that children always come after their parents
+func Descendants(tasks []*Task, id string) []*Task {
+	children := make(map[string][]*Task)
+	for _, task := range tasks {
+		if task.ParentID != "" {
+			children[task.ParentID] = append(children[task.ParentID], task)
+		}
+	}
+
+	var result []*Task
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, child := range children[current] {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			result = append(result, child)
+			queue = append(queue, child.ID)
+		}
+	}
+
+	return result
+}
+
+// IsDescendant reports whether the task with the given ID is nested anywhere
+// under the task with ancestorID
+func IsDescendant(tasks []*Task, ancestorID, id string) bool {
+	for _, task := range Descendants(tasks, ancestorID) {
+		if task.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Package output writes command results in machine-readable formats: JSON
+// and YAML documents with a versioned schema, and CSV, TSV and aligned tables.
+// The schema is documented in README_OUTPUT.md.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// Format is an output format selected with --output
+type Format string
+
+// Output formats. Plain is the human-readable output of each command.
+const (
+	FormatPlain Format = "plain"
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
+	FormatTSV   Format = "tsv"
+)
+
+// Formats lists the supported output formats
+var Formats = []Format{FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatTable, FormatPlain}
+
+// ParseFormat parses an output format name
+func ParseFormat(value string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return "", models.Invalidf("unknown output format %q (must be one of %s)", value, strings.Join(names, ", "))
+}
+
+// IsDocument reports whether the format writes a whole document (JSON or
+// YAML) rather than rows
+func (f Format) IsDocument() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// SchemaVersion is the version of the JSON and YAML document schema. It is
+// increased when a field is removed or changes meaning; fields may be added
+// without a new version.
+const SchemaVersion = 1
+
+// Document is the top level of JSON and YAML output. Kind tells which type
+// Data holds; failed commands write a document of kind "error" instead.
+type Document struct {
+	SchemaVersion int         `json:"schema_version"`
+	Kind          string      `json:"kind"`
+	Data          interface{} `json:"data,omitempty"`
+	Error         *Error      `json:"error,omitempty"`
+}
+
+// Error describes a failed command
+type Error struct {
+	Code     string `json:"code"`
+	ExitCode int    `json:"exit_code"`
+	Message  string `json:"message"`
+}
+
+// Table is the row form of a result, used by the CSV, TSV and table formats
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Add appends a row
+func (t *Table) Add(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Write writes a result in a format other than plain: data as a document of
+// the given kind for JSON and YAML, or the table for the row formats
+func Write(w io.Writer, format Format, kind string, data interface{}, table Table) error {
+	switch format {
+	case FormatJSON, FormatYAML:
+		return writeDocument(w, format, Document{SchemaVersion: SchemaVersion, Kind: kind, Data: data})
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		writer.Write(table.Columns)
+		writer.WriteAll(table.Rows)
+		return writer.Error()
+	case FormatTSV:
+		return writeTSV(w, table)
+	case FormatTable:
+		return writeTable(w, table)
+	}
+	return fmt.Errorf("output format %s has no structured form", format)
+}
+
+// WriteError writes an error document in JSON or YAML
+func WriteError(w io.Writer, format Format, e Error) error {
+	return writeDocument(w, format, Document{SchemaVersion: SchemaVersion, Kind: "error", Error: &e})
+}
+
+func writeDocument(w io.Writer, format Format, doc Document) error {
+	if format == FormatYAML {
+		return writeYAML(w, doc)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// writeTSV writes tab-separated rows. Tabs and line breaks inside values are
+// replaced by spaces, so every line is one row.
+func writeTSV(w io.Writer, table Table) error {
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	for _, row := range append([][]string{table.Columns}, table.Rows...) {
+		values := make([]string, len(row))
+		for i, value := range row {
+			values[i] = clean.Replace(value)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable writes the rows as aligned columns under an upper-case header
+func writeTable(w io.Writer, table Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+	header := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range table.Rows {
+		values := make([]string, len(row))
+		for i, value := range row {
+			values[i] = clean.Replace(value)
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
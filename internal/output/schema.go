@@ -0,0 +1,447 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// Document kinds
+const (
+	KindTaskList      = "task_list"
+	KindSearchResults = "search_results"
+	KindFocus         = "focus"
+	KindBackups       = "backups"
+	KindBackup        = "backup"
+	KindTimeStats     = "time_stats"
+	KindSkipStats     = "skip_stats"
+	KindTags          = "tags"
+	KindProjects      = "projects"
+	KindProject       = "project"
+	KindTask          = "task"
+	KindTaskStatus    = "task_status"
+	KindCompletion    = "completion"
+	KindDeletion      = "deletion"
+	KindReminders     = "reminders"
+	KindReminder      = "reminder"
+	KindTagRename     = "tag_rename"
+	KindProjectDelete = "project_deletion"
+	KindRestore       = "restore"
+	KindFocusSkip     = "focus_skip"
+	KindFocusSnooze   = "focus_snooze"
+	KindPomodoro      = "pomodoro"
+	KindBrainDump     = "brain_dump"
+	KindVersion       = "version"
+)
+
+// Task is a task in structured output. Optional fields are left out when
+// they are not set; times are RFC 3339 and durations are whole seconds.
+type Task struct {
+	ID               string      `json:"id"`
+	Title            string      `json:"title"`
+	Description      string      `json:"description,omitempty"`
+	Status           string      `json:"status"`
+	Completed        bool        `json:"completed"`
+	Priority         string      `json:"priority"`
+	Category         string      `json:"category"`
+	Project          *ProjectRef `json:"project,omitempty"`
+	Tags             []string    `json:"tags"`
+	Context          string      `json:"context,omitempty"`
+	Energy           string      `json:"energy,omitempty"`
+	EstimateSeconds  int64       `json:"estimate_seconds,omitempty"`
+	TimeSpentSeconds int64       `json:"time_spent_seconds,omitempty"`
+	Due              string      `json:"due,omitempty"`
+	Reminder         string      `json:"reminder,omitempty"`
+	Repeat           string      `json:"repeat,omitempty"`
+	ParentID         string      `json:"parent_id,omitempty"`
+	BlockedBy        []string    `json:"blocked_by,omitempty"`
+	Notes            []Note      `json:"notes,omitempty"`
+	CreatedAt        string      `json:"created_at"`
+	ClosedAt         string      `json:"closed_at,omitempty"`
+}
+
+// ProjectRef names the project of a task
+type ProjectRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Note is a note on a task
+type Note struct {
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+}
+
+// NewTask converts a task for output. projects maps project IDs to names.
+func NewTask(task *models.Task, projects map[string]string) Task {
+	result := Task{
+		ID:               task.ID,
+		Title:            task.Title,
+		Description:      task.Description,
+		Status:           string(task.Status),
+		Completed:        task.Completed,
+		Priority:         string(task.Priority),
+		Category:         string(task.Category),
+		Tags:             append([]string{}, task.Tags...),
+		Context:          task.Context,
+		Energy:           string(task.Energy),
+		EstimateSeconds:  Seconds(task.Estimate),
+		TimeSpentSeconds: Seconds(task.TimeSpent),
+		Due:              FormatTime(task.DueDate),
+		Reminder:         FormatTime(task.ReminderAt),
+		ParentID:         task.ParentID,
+		BlockedBy:        task.BlockedBy,
+		CreatedAt:        FormatTime(task.CreatedAt),
+	}
+	if task.Completed {
+		result.ClosedAt = FormatTime(task.CompletedAt)
+	}
+	if task.Project != "" {
+		name, ok := projects[task.Project]
+		if !ok {
+			name = task.Project
+		}
+		result.Project = &ProjectRef{ID: task.Project, Name: name}
+	}
+	if task.Recurrence != nil {
+		result.Repeat = task.Recurrence.Expression()
+	}
+	for _, note := range task.Notes {
+		result.Notes = append(result.Notes, Note{Text: note.Text, CreatedAt: FormatTime(note.CreatedAt)})
+	}
+	return result
+}
+
+// NewTasks converts tasks for output
+func NewTasks(tasks []*models.Task, projects map[string]string) []Task {
+	result := make([]Task, len(tasks))
+	for i, task := range tasks {
+		result[i] = NewTask(task, projects)
+	}
+	return result
+}
+
+// TaskColumns are the columns of task tables
+var TaskColumns = []string{"id", "status", "priority", "category", "project", "due", "title", "tags"}
+
+// TaskRow returns the values of a task for the TaskColumns
+func TaskRow(task Task) []string {
+	project := ""
+	if task.Project != nil {
+		project = task.Project.Name
+	}
+	return []string{task.ID, task.Status, task.Priority, task.Category, project, task.Due, task.Title, strings.Join(task.Tags, ",")}
+}
+
+// TaskList is the data of a task_list document: one page of the matching
+// tasks and how many match in total
+type TaskList struct {
+	Tasks  []Task `json:"tasks"`
+	Total  int    `json:"total"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// TaskTable returns the table form of a list of tasks
+func TaskTable(tasks []Task) Table {
+	table := Table{Columns: TaskColumns}
+	for _, task := range tasks {
+		table.Add(TaskRow(task)...)
+	}
+	return table
+}
+
+// TaskStatus is the data of a task_status document: a task with when it
+// entered its status and its status changes, oldest first
+type TaskStatus struct {
+	Since   string         `json:"since,omitempty"`
+	History []StatusChange `json:"history"`
+	Task    Task           `json:"task"`
+}
+
+// StatusChange is a change of the status of a task
+type StatusChange struct {
+	At   string `json:"at"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NewTaskStatus converts a task and its status history for output
+func NewTaskStatus(task *models.Task, projects map[string]string) TaskStatus {
+	result := TaskStatus{
+		Since:   FormatTime(task.StatusSince()),
+		History: []StatusChange{},
+		Task:    NewTask(task, projects),
+	}
+	for _, change := range task.StatusHistory {
+		result.History = append(result.History, StatusChange{At: FormatTime(change.At), From: string(change.From), To: string(change.To)})
+	}
+	return result
+}
+
+// Completion is the data of a completion document. NextOccurrence is when
+// the next occurrence of a recurring task is due; Unblocked lists the tasks
+// that no longer wait for anything.
+type Completion struct {
+	AlreadyCompleted bool   `json:"already_completed"`
+	NextOccurrence   string `json:"next_occurrence,omitempty"`
+	Unblocked        []Task `json:"unblocked"`
+	Task             Task   `json:"task"`
+}
+
+// Deletion is the data of a deletion document. Deleted is false when the
+// deletion was not confirmed.
+type Deletion struct {
+	Deleted  bool `json:"deleted"`
+	Subtasks int  `json:"subtasks"`
+	Task     Task `json:"task"`
+}
+
+// Reminder is the data of a reminder document for an acknowledged or
+// snoozed reminder. SnoozedUntil is left out for an acknowledgement.
+type Reminder struct {
+	Acknowledged bool   `json:"acknowledged"`
+	SnoozedUntil string `json:"snoozed_until,omitempty"`
+	Task         Task   `json:"task"`
+}
+
+// Restore is the data of a restore document. Restored is false when the
+// restore was not confirmed.
+type Restore struct {
+	Restored bool   `json:"restored"`
+	Path     string `json:"path"`
+}
+
+// SearchResult is an entry of a search_results document
+type SearchResult struct {
+	Score    float64          `json:"score"`
+	Task     Task             `json:"task"`
+	Snippets []models.Snippet `json:"snippets"`
+}
+
+// FocusSuggestion is an entry of a focus document, best first
+type FocusSuggestion struct {
+	Rank    int                  `json:"rank"`
+	Score   float64              `json:"score"`
+	Factors []models.ScoreFactor `json:"factors"`
+	Task    Task                 `json:"task"`
+}
+
+// FocusSkip is the data of a focus_skip document: the skipped task, until
+// when it is left out of focus mode and the suggestion that comes next
+type FocusSkip struct {
+	Until string `json:"until,omitempty"`
+	Skips int    `json:"skips"`
+	Task  Task   `json:"task"`
+	Next  *Task  `json:"next,omitempty"`
+}
+
+// FocusSnooze is the data of a focus_snooze document. Until is left out
+// when the snooze was cleared.
+type FocusSnooze struct {
+	Until string `json:"until,omitempty"`
+	Task  Task   `json:"task"`
+}
+
+// Pomodoro is the data of a pomodoro document: the state of the timer, or
+// how a session went once it has ended
+type Pomodoro struct {
+	Active           bool   `json:"active"`
+	TaskID           string `json:"task_id,omitempty"`
+	TaskTitle        string `json:"task_title,omitempty"`
+	Phase            string `json:"phase,omitempty"`
+	Cycle            int    `json:"cycle,omitempty"`
+	Cycles           int    `json:"cycles,omitempty"`
+	CompletedCycles  int    `json:"completed_cycles"`
+	Paused           bool   `json:"paused"`
+	StopAfterPhase   bool   `json:"stop_after_phase"`
+	RemainingSeconds int64  `json:"remaining_seconds,omitempty"`
+	PhaseEnd         string `json:"phase_end,omitempty"`
+	StartedAt        string `json:"started_at,omitempty"`
+	EndedAt          string `json:"ended_at,omitempty"`
+	WorkSeconds      int64  `json:"work_seconds"`
+	Interrupted      int    `json:"interrupted"`
+	Interruptions    int    `json:"interruptions"`
+}
+
+// NewPomodoro converts the state of a Pomodoro timer for output
+func NewPomodoro(status *models.PomodoroStatus) Pomodoro {
+	return Pomodoro{
+		Active:           status.Active,
+		TaskID:           status.TaskID,
+		TaskTitle:        status.TaskTitle,
+		Phase:            string(status.Phase),
+		Cycle:            status.Cycle,
+		Cycles:           status.Cycles,
+		CompletedCycles:  status.CompletedCycles,
+		Paused:           status.Paused,
+		StopAfterPhase:   status.StopAfterPhase,
+		RemainingSeconds: Seconds(status.Remaining),
+		PhaseEnd:         FormatTime(status.PhaseEnd),
+		StartedAt:        FormatTime(status.StartedAt),
+		EndedAt:          FormatTime(status.EndedAt),
+		WorkSeconds:      Seconds(status.WorkTime),
+		Interrupted:      status.Interrupted,
+		Interruptions:    status.Interruptions,
+	}
+}
+
+// PomodoroTable returns the table form of the state of a Pomodoro timer
+func PomodoroTable(p Pomodoro) Table {
+	table := Table{Columns: []string{"active", "task_id", "task_title", "phase", "cycle", "completed_cycles", "paused", "remaining_seconds", "work_seconds"}}
+	table.Add(strconv.FormatBool(p.Active), p.TaskID, p.TaskTitle, p.Phase, strconv.Itoa(p.Cycle),
+		strconv.Itoa(p.CompletedCycles), strconv.FormatBool(p.Paused),
+		strconv.FormatInt(p.RemainingSeconds, 10), strconv.FormatInt(p.WorkSeconds, 10))
+	return table
+}
+
+// BrainDump is the data of a brain_dump document: the tasks added, the
+// number of lines skipped as invalid and the number of lines kept to be
+// sent to the server later
+type BrainDump struct {
+	Added   []Task `json:"added"`
+	Skipped int    `json:"skipped"`
+	Pending int    `json:"pending"`
+}
+
+// Version is the data of a version document
+type Version struct {
+	Version string `json:"version"`
+}
+
+// Backup is an entry of a backups document, or the data of a backup
+// document for a newly created backup
+type Backup struct {
+	Path string `json:"path"`
+}
+
+// TimeStats is the data of a time_stats document. Since is left out when
+// the statistics cover all history.
+type TimeStats struct {
+	Since         string        `json:"since,omitempty"`
+	SpentSeconds  int64         `json:"spent_seconds"`
+	Completed     int           `json:"completed"`
+	Interrupted   int           `json:"interrupted"`
+	ByTask        []TimeGroup   `json:"by_task"`
+	ByCategory    []TimeGroup   `json:"by_category"`
+	ByDay         []TimeGroup   `json:"by_day"`
+	Interruptions []ReasonCount `json:"interruptions"`
+}
+
+// TimeGroup is the Pomodoro time of one task, category or day. Key is the
+// task ID, the category or the date (YYYY-MM-DD).
+type TimeGroup struct {
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	SpentSeconds int64  `json:"spent_seconds"`
+	Completed    int    `json:"completed"`
+	Interrupted  int    `json:"interrupted"`
+}
+
+// ReasonCount counts how often a reason was given; an empty reason means none
+// was given
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// SkipStats is the data of a skip_stats document
+type SkipStats struct {
+	Since string        `json:"since,omitempty"`
+	Skips int           `json:"skips"`
+	Tasks []SkippedTask `json:"tasks"`
+}
+
+// SkippedTask is a task skipped in focus mode, most skipped first
+type SkippedTask struct {
+	Skips   int           `json:"skips"`
+	Reasons []ReasonCount `json:"reasons"`
+	Task    Task          `json:"task"`
+}
+
+// Tag is an entry of a tags document
+type Tag struct {
+	Tag   string `json:"tag"`
+	Tasks int    `json:"tasks"`
+	Open  int    `json:"open"`
+}
+
+// Project is an entry of a projects document
+type Project struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	Deadline    string   `json:"deadline,omitempty"`
+	Archived    bool     `json:"archived"`
+	Total       int      `json:"total"`
+	Done        int      `json:"done"`
+	Percent     int      `json:"percent"`
+	Overdue     int      `json:"overdue"`
+	NextAction  *TaskRef `json:"next_action,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// ProjectDetail is the data of a project document: a project and its tasks
+type ProjectDetail struct {
+	Project
+	Tasks []Task `json:"tasks"`
+}
+
+// ProjectDeletion is the data of a project_deletion document. Unassigned
+// counts the tasks that no longer belong to a project.
+type ProjectDeletion struct {
+	Unassigned int     `json:"unassigned"`
+	Project    Project `json:"project"`
+}
+
+// TagRename is the data of a tag_rename document: the tags that were
+// replaced, the tag that replaced them and how many tasks changed
+type TagRename struct {
+	From    []string `json:"from"`
+	To      string   `json:"to"`
+	Updated int      `json:"updated"`
+}
+
+// TaskRef names a task
+type TaskRef struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// NewProject converts a project summary for output
+func NewProject(summary models.ProjectSummary) Project {
+	project := summary.Project
+	result := Project{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		Color:       project.Color,
+		Deadline:    FormatTime(project.Deadline),
+		Archived:    project.Archived,
+		Total:       summary.Total,
+		Done:        summary.Done,
+		Percent:     summary.Percent(),
+		Overdue:     summary.Overdue,
+		CreatedAt:   FormatTime(project.CreatedAt),
+	}
+	if summary.NextAction != nil {
+		result.NextAction = &TaskRef{ID: summary.NextAction.ID, Title: summary.NextAction.Title}
+	}
+	return result
+}
+
+// FormatTime formats a time as RFC 3339, or returns "" for the zero time
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// Seconds returns a duration in whole seconds
+func Seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
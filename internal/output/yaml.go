@@ -0,0 +1,153 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// yamlNode is a JSON value with the order of object keys kept
+type yamlNode struct {
+	keys   []string    // object keys, nil for arrays and scalars
+	items  []*yamlNode // object values or array items
+	scalar string      // formatted scalar
+	isList bool
+	isMap  bool
+}
+
+// writeYAML writes a value as YAML. The value is encoded as JSON first, so
+// field names, omitted fields and value formats are the same in both.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	node, err := decodeYAMLNode(decoder)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	var b strings.Builder
+	node.write(&b, 0)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// decodeYAMLNode reads the next JSON value
+func decodeYAMLNode(decoder *json.Decoder) (*yamlNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		node := &yamlNode{isMap: value == '{', isList: value == '['}
+		for decoder.More() {
+			if node.isMap {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, key.(string))
+			}
+			item, err := decodeYAMLNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.items = append(node.items, item)
+		}
+		// Closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yamlNode{scalar: yamlString(value)}, nil
+	case json.Number:
+		return &yamlNode{scalar: value.String()}, nil
+	case bool:
+		return &yamlNode{scalar: strconv.FormatBool(value)}, nil
+	case nil:
+		return &yamlNode{scalar: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", token)
+}
+
+// inline returns the node as a single line if it is a scalar or empty
+func (n *yamlNode) inline() (string, bool) {
+	switch {
+	case n.isMap && len(n.items) == 0:
+		return "{}", true
+	case n.isList && len(n.items) == 0:
+		return "[]", true
+	case !n.isMap && !n.isList:
+		return n.scalar, true
+	}
+	return "", false
+}
+
+// write writes a block node indented by the given number of spaces
+func (n *yamlNode) write(b *strings.Builder, indent int) {
+	if line, ok := n.inline(); ok {
+		b.WriteString(line + "\n")
+		return
+	}
+
+	pad := strings.Repeat(" ", indent)
+	for i, item := range n.items {
+		if n.isMap {
+			b.WriteString(pad + yamlString(n.keys[i]) + ":")
+			if line, ok := item.inline(); ok {
+				b.WriteString(" " + line + "\n")
+			} else {
+				b.WriteString("\n")
+				item.write(b, indent+2)
+			}
+			continue
+		}
+
+		if line, ok := item.inline(); ok {
+			b.WriteString(pad + "- " + line + "\n")
+			continue
+		}
+		// Write the item one level deeper and put the dash into the
+		// indentation of its first line
+		var child strings.Builder
+		item.write(&child, indent+2)
+		b.WriteString(pad + "- " + child.String()[indent+2:])
+	}
+}
+
+// yamlString formats a string scalar, quoting it when it would otherwise be
+// read as something else
+func yamlString(s string) string {
+	if s == "" || s != strings.TrimSpace(s) {
+		return strconv.Quote(s)
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return strconv.Quote(s)
+	}
+	// Numbers, and dates and times that parsers would convert
+	if s[0] >= '0' && s[0] <= '9' || s[0] == '.' || s[0] == '+' {
+		return strconv.Quote(s)
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
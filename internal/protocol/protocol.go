@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/user/todolist/internal/events"
 	"github.com/user/todolist/internal/models"
 )
 
@@ -15,42 +16,180 @@ const (
 	OpGetAllTasks        = "GET_ALL_TASKS"
 	OpGetTasksByCategory = "GET_TASKS_BY_CATEGORY"
 	OpGetTasksByPriority = "GET_TASKS_BY_PRIORITY"
+	OpQuery              = "QUERY"
+	OpSearch             = "SEARCH"
 	OpUpdateTask         = "UPDATE_TASK"
+	OpPatchTask          = "PATCH_TASK"
 	OpDeleteTask         = "DELETE_TASK"
 	OpCompleteTask       = "COMPLETE_TASK"
+	OpSetStatus          = "SET_STATUS"
+	OpReopenTask         = "REOPEN_TASK"
+	OpAddNote            = "ADD_NOTE"
+	OpBatchAdd           = "BATCH_ADD"
+	OpAddSubtask         = "ADD_SUBTASK"
+	OpMoveTask           = "MOVE_TASK"
+	OpBlockTask          = "BLOCK_TASK"
+	OpUnblockTask        = "UNBLOCK_TASK"
+
+	// Tag operations
+	OpAddTags    = "ADD_TAGS"
+	OpRemoveTags = "REMOVE_TAGS"
+	OpRenameTags = "RENAME_TAGS"
+	OpGetTags    = "GET_TAGS"
+
+	// Project operations
+	OpAddProject          = "ADD_PROJECT"
+	OpUpdateProject       = "UPDATE_PROJECT"
+	OpArchiveProject      = "ARCHIVE_PROJECT"
+	OpDeleteProject       = "DELETE_PROJECT"
+	OpGetProjects         = "GET_PROJECTS"
+	OpGetProjectSummary   = "GET_PROJECT_SUMMARY"
+	OpGetProjectSummaries = "GET_PROJECT_SUMMARIES"
+
+	// Reminder operations
+	OpGetReminders        = "GET_REMINDERS"
+	OpAcknowledgeReminder = "ACK_REMINDER"
+	OpSnoozeReminder      = "SNOOZE_REMINDER"
 
 	// Data operations
 	OpBackup      = "BACKUP"
 	OpRestore     = "RESTORE"
 	OpListBackups = "LIST_BACKUPS"
 
+	// Event operations
+	OpSubscribe = "SUBSCRIBE"
+
 	// Other operations
-	OpBrainDump     = "BRAIN_DUMP"
 	OpFocusMode     = "FOCUS_MODE"
+	OpSkipFocus     = "SKIP_FOCUS"
+	OpSnoozeFocus   = "SNOOZE_FOCUS"
 	OpStartPomodoro = "START_POMODORO"
+
+	// Pomodoro session operations
+	OpPomodoroStatus  = "POMODORO_STATUS"
+	OpPausePomodoro   = "PAUSE_POMODORO"
+	OpResumePomodoro  = "RESUME_POMODORO"
+	OpSkipPomodoro    = "SKIP_POMODORO"
+	OpFinishPomodoro  = "FINISH_POMODORO"
+	OpStopPomodoro    = "STOP_POMODORO"
+	OpLogInterruption = "LOG_INTERRUPTION"
+
+	// Pomodoro history operations
+	OpGetPomodoroHistory = "GET_POMODORO_HISTORY"
 )
 
-// Request represents a client request to the server
+// Error codes tell the client what kind of error a failed request ran into
+const (
+	CodeInvalidInput    = "invalid_input"
+	CodeTaskNotFound    = "task_not_found"
+	CodeProjectNotFound = "project_not_found"
+)
+
+// Request represents a client request to the server. The optional ID is
+// echoed in the response so several requests can be in flight on one
+// connection.
 type Request struct {
+	ID        string          `json:"id,omitempty"`
 	Operation string          `json:"operation"`
 	Payload   json.RawMessage `json:"payload"`
 }
 
-// Response represents a server response to the client
+// Response represents a server response to the client. After a SUBSCRIBE
+// request the server also sends event messages, which carry the ID of the
+// SUBSCRIBE request and an Event instead of a payload. A failed response
+// may carry one of the error codes along with its message.
 type Response struct {
+	ID      string          `json:"id,omitempty"`
 	Success bool            `json:"success"`
 	Error   string          `json:"error,omitempty"`
+	Code    string          `json:"code,omitempty"`
 	Payload json.RawMessage `json:"payload,omitempty"`
+	Event   *events.Event   `json:"event,omitempty"`
 }
 
 // AddTaskRequest represents the payload for adding a task
 type AddTaskRequest struct {
-	Title       string          `json:"title"`
-	Description string          `json:"description"`
-	Priority    models.Priority `json:"priority"`
-	Category    models.Category `json:"category"`
-	DueDate     time.Time       `json:"due_date"`
-	ReminderAt  time.Time       `json:"reminder_at"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Priority    models.Priority    `json:"priority"`
+	Category    models.Category    `json:"category"`
+	Project     string             `json:"project,omitempty"`
+	DueDate     time.Time          `json:"due_date"`
+	ReminderAt  time.Time          `json:"reminder_at"`
+	Recurrence  *models.Recurrence `json:"recurrence,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	Context     string             `json:"context,omitempty"`
+	Estimate    time.Duration      `json:"estimate,omitempty"`
+	Energy      models.Energy      `json:"energy,omitempty"`
+	BlockedBy   []string           `json:"blocked_by,omitempty"`
+	// DueExpr and ReminderExpr are natural-language alternatives to DueDate
+	// and ReminderAt (e.g. "friday 5pm"), resolved with the server's clock
+	DueExpr      string `json:"due,omitempty"`
+	ReminderExpr string `json:"reminder,omitempty"`
+}
+
+// NewAddTaskRequest creates an add task payload from a task specification
+func NewAddTaskRequest(spec models.TaskSpec) AddTaskRequest {
+	return AddTaskRequest{
+		Title:       spec.Title,
+		Description: spec.Description,
+		Priority:    spec.Priority,
+		Category:    spec.Category,
+		Project:     spec.Project,
+		DueDate:     spec.DueDate,
+		ReminderAt:  spec.ReminderAt,
+		Recurrence:  spec.Recurrence,
+		Tags:        spec.Tags,
+		Context:     spec.Context,
+		Estimate:    spec.Estimate,
+		Energy:      spec.Energy,
+		BlockedBy:   spec.BlockedBy,
+	}
+}
+
+// Spec converts the payload into a task specification
+func (r AddTaskRequest) Spec() models.TaskSpec {
+	return models.TaskSpec{
+		Title:       r.Title,
+		Description: r.Description,
+		Priority:    r.Priority,
+		Category:    r.Category,
+		Project:     r.Project,
+		DueDate:     r.DueDate,
+		ReminderAt:  r.ReminderAt,
+		Recurrence:  r.Recurrence,
+		Tags:        r.Tags,
+		Context:     r.Context,
+		Estimate:    r.Estimate,
+		Energy:      r.Energy,
+		BlockedBy:   r.BlockedBy,
+	}
+}
+
+// BatchAddRequest represents the payload for adding several tasks at once,
+// e.g. the lines of a brain dump
+type BatchAddRequest struct {
+	Tasks []AddTaskRequest `json:"tasks"`
+}
+
+// AddSubtaskRequest represents the payload for adding a subtask under a parent task
+type AddSubtaskRequest struct {
+	ParentID string `json:"parent_id"`
+	AddTaskRequest
+}
+
+// MoveTaskRequest represents a request to move a task under a new parent.
+// An empty parent ID moves the task to the top level.
+type MoveTaskRequest struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id"`
+}
+
+// PatchTaskRequest represents a partial update of a task. Only the fields set
+// in the patch are changed.
+type PatchTaskRequest struct {
+	ID    string           `json:"id"`
+	Patch models.TaskPatch `json:"patch"`
 }
 
 // TaskResponse represents a task in a response
@@ -63,6 +202,27 @@ type TasksResponse struct {
 	Tasks []*models.Task `json:"tasks"`
 }
 
+// QueryRequest represents a request for the page of tasks matching a query
+// expression
+type QueryRequest struct {
+	Query models.TaskQuery `json:"query"`
+}
+
+// QueryResponse represents a page of tasks in a response
+type QueryResponse struct {
+	Page *models.TaskPage `json:"page"`
+}
+
+// SearchRequest represents a full-text search request
+type SearchRequest struct {
+	Query models.SearchQuery `json:"query"`
+}
+
+// SearchResponse represents ranked search results in a response
+type SearchResponse struct {
+	Results []*models.SearchResult `json:"results"`
+}
+
 // IDRequest represents a request with just an ID
 type IDRequest struct {
 	ID string `json:"id"`
@@ -98,15 +258,161 @@ type ListBackupsResponse struct {
 	Backups []string `json:"backups"`
 }
 
-// BrainDumpResponse represents the response to a brain dump request
-type BrainDumpResponse struct {
-	Success bool `json:"success"`
+// SnoozeReminderRequest represents a request to silence a task's alerts
+type SnoozeReminderRequest struct {
+	ID    string    `json:"id"`
+	Until time.Time `json:"until"`
+}
+
+// FocusModeRequest represents a request for focus suggestions
+type FocusModeRequest struct {
+	Options models.FocusOptions `json:"options"`
+}
+
+// FocusModeResponse represents focus suggestions in a response. Task is the
+// best suggestion, kept for clients that only show one.
+type FocusModeResponse struct {
+	Task        *models.Task       `json:"task"`
+	Suggestions []models.TaskScore `json:"suggestions"`
+}
+
+// TagsRequest represents a request to add tags to or remove tags from a task
+type TagsRequest struct {
+	ID   string   `json:"id"`
+	Tags []string `json:"tags"`
+}
+
+// RenameTagsRequest represents a request to replace tags with another tag
+// on every task; several source tags are merged into the new one
+type RenameTagsRequest struct {
+	From []string `json:"from"`
+	To   string   `json:"to"`
+}
+
+// RenameTagsResponse represents the number of tasks changed by a rename
+type RenameTagsResponse struct {
+	Updated int `json:"updated"`
+}
+
+// TagCountsResponse represents the tags in use in a response
+type TagCountsResponse struct {
+	Tags []models.TagCount `json:"tags"`
+}
+
+// ProjectRequest represents a request to create a project
+type ProjectRequest struct {
+	Project models.Project `json:"project"`
+}
+
+// ProjectResponse represents a project in a response
+type ProjectResponse struct {
+	Project *models.Project `json:"project"`
+}
+
+// ProjectRefRequest represents a request naming a project by ID or name
+type ProjectRefRequest struct {
+	Project string `json:"project"`
+}
+
+// UpdateProjectRequest represents a partial update of a project
+type UpdateProjectRequest struct {
+	Project string              `json:"project"`
+	Patch   models.ProjectPatch `json:"patch"`
+}
+
+// ArchiveProjectRequest represents a request to archive or unarchive a project
+type ArchiveProjectRequest struct {
+	Project  string `json:"project"`
+	Archived bool   `json:"archived"`
+}
+
+// DeleteProjectResponse represents the number of tasks moved out of a
+// deleted project
+type DeleteProjectResponse struct {
+	Moved int `json:"moved"`
+}
+
+// ProjectsRequest represents a request for projects; archived projects are
+// only included if asked for
+type ProjectsRequest struct {
+	IncludeArchived bool `json:"include_archived,omitempty"`
+}
+
+// ProjectsResponse represents multiple projects in a response
+type ProjectsResponse struct {
+	Projects []*models.Project `json:"projects"`
+}
+
+// ProjectSummaryResponse represents the progress of a project in a response
+type ProjectSummaryResponse struct {
+	Summary *models.ProjectSummary `json:"summary"`
+}
+
+// ProjectSummariesResponse represents the progress of several projects
+type ProjectSummariesResponse struct {
+	Summaries []models.ProjectSummary `json:"summaries"`
+}
+
+// SetStatusRequest represents a request to move a task to a new status
+type SetStatusRequest struct {
+	ID     string        `json:"id"`
+	Status models.Status `json:"status"`
+}
+
+// AddNoteRequest represents a request to append a note to a task
+type AddNoteRequest struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// BlockTaskRequest represents a request to add or remove a "blocked by"
+// link. An empty blocker ID in an unblock request removes all blockers.
+type BlockTaskRequest struct {
+	ID        string `json:"id"`
+	BlockerID string `json:"blocker_id"`
+}
+
+// SkipFocusRequest represents a request to skip a focus suggestion
+type SkipFocusRequest struct {
+	ID     string    `json:"id"`
+	Reason string    `json:"reason,omitempty"`
+	Until  time.Time `json:"until,omitempty"`
+}
+
+// SnoozeFocusRequest represents a request to keep a task out of focus
+// suggestions; the zero time ends the snooze
+type SnoozeFocusRequest struct {
+	ID    string    `json:"id"`
+	Until time.Time `json:"until"`
 }
 
 // PomodoroRequest represents a request to start a pomodoro timer
 type PomodoroRequest struct {
 	TaskID         string        `json:"task_id"`
 	CustomDuration time.Duration `json:"custom_duration,omitempty"`
+	Cycles         int           `json:"cycles,omitempty"`
+}
+
+// PomodoroResponse represents the state of the Pomodoro session
+type PomodoroResponse struct {
+	Status *models.PomodoroStatus `json:"status"`
+}
+
+// StopPomodoroRequest represents a request to stop the Pomodoro session. The
+// reason is recorded if a work phase is interrupted.
+type StopPomodoroRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// PomodoroHistoryRequest represents a request for the work intervals that
+// ended at or after Since
+type PomodoroHistoryRequest struct {
+	Since time.Time `json:"since"`
+}
+
+// PomodoroHistoryResponse represents the Pomodoro history in a response
+type PomodoroHistoryResponse struct {
+	Records []*models.PomodoroRecord `json:"records"`
 }
 
 // StringResponse represents a simple string response
@@ -0,0 +1,331 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// Expr is a node of a parsed filter expression
+type Expr interface {
+	// Match reports whether a task passes the filter
+	Match(task *models.Task) bool
+	// String formats the expression in query syntax
+	String() string
+}
+
+// And matches tasks that match all of its expressions
+type And struct {
+	Exprs []Expr
+}
+
+// Or matches tasks that match at least one of its expressions
+type Or struct {
+	Exprs []Expr
+}
+
+// Not matches tasks that do not match its expression
+type Not struct {
+	Expr Expr
+}
+
+// Field names a task attribute that can be filtered on
+type Field string
+
+// Query fields
+const (
+	FieldPriority    Field = "priority"
+	FieldCategory    Field = "category"
+	FieldProject     Field = "project"
+	FieldTag         Field = "tag"
+	FieldContext     Field = "context"
+	FieldStatus      Field = "status"
+	FieldEnergy      Field = "energy"
+	FieldEstimate    Field = "estimate"
+	FieldDue         Field = "due"
+	FieldCreated     Field = "created"
+	FieldClosed      Field = "closed"
+	FieldText        Field = "text"
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldIs          Field = "is"
+)
+
+// Op is the comparison a term makes
+type Op string
+
+// Term operators. OpEqual is written as a plain colon, as in "priority:high".
+const (
+	OpEqual    Op = ":"
+	OpLess     Op = "<"
+	OpLessEq   Op = "<="
+	OpGreater  Op = ">"
+	OpGreatEq  Op = ">="
+	OpContains Op = "~"
+)
+
+// Term compares one task field with a value
+type Term struct {
+	Field Field
+	Op    Op
+	// Value is the normalized value as written, or "none" for terms that
+	// match tasks without a value
+	Value string
+
+	// Date terms match times in [From, To); a zero bound is open
+	From, To time.Time
+	// Estimate terms compare against Duration
+	Duration time.Duration
+	// Now is the time the query was parsed at, used by is:overdue and
+	// is:snoozed
+	Now time.Time
+}
+
+// IsNone reports whether the term matches tasks without a value
+func (t *Term) IsNone() bool {
+	return t.Value == "none"
+}
+
+// priorityLevels orders the priorities from lowest to highest
+var priorityLevels = map[models.Priority]int{
+	models.PriorityLow:    1,
+	models.PriorityMedium: 2,
+	models.PriorityHigh:   3,
+}
+
+// Priorities returns the priorities a priority term matches
+func (t *Term) Priorities() []models.Priority {
+	var result []models.Priority
+	for _, priority := range []models.Priority{models.PriorityLow, models.PriorityMedium, models.PriorityHigh} {
+		if compareInts(priorityLevels[priority], priorityLevels[models.Priority(t.Value)], t.Op) {
+			result = append(result, priority)
+		}
+	}
+	return result
+}
+
+// compareInts compares a with b using the operator
+func compareInts(a, b int, op Op) bool {
+	switch op {
+	case OpLess:
+		return a < b
+	case OpLessEq:
+		return a <= b
+	case OpGreater:
+		return a > b
+	case OpGreatEq:
+		return a >= b
+	}
+	return a == b
+}
+
+// Match reports whether all expressions match the task
+func (e *And) Match(task *models.Task) bool {
+	for _, expr := range e.Exprs {
+		if !expr.Match(task) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether any expression matches the task
+func (e *Or) Match(task *models.Task) bool {
+	for _, expr := range e.Exprs {
+		if expr.Match(task) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether the expression does not match the task
+func (e *Not) Match(task *models.Task) bool {
+	return !e.Expr.Match(task)
+}
+
+// Match reports whether the task's field passes the comparison
+func (t *Term) Match(task *models.Task) bool {
+	switch t.Field {
+	case FieldPriority:
+		return compareInts(priorityLevels[task.Priority], priorityLevels[models.Priority(t.Value)], t.Op)
+	case FieldCategory:
+		return strings.EqualFold(string(task.Category), t.Value)
+	case FieldProject:
+		if t.IsNone() {
+			return task.Project == ""
+		}
+		return task.Project == t.Value
+	case FieldTag:
+		if t.IsNone() {
+			return len(task.Tags) == 0
+		}
+		return task.HasTag(t.Value)
+	case FieldContext:
+		if t.IsNone() {
+			return task.Context == ""
+		}
+		return strings.EqualFold(task.Context, t.Value)
+	case FieldStatus:
+		switch t.Value {
+		case "open":
+			return !task.Status.IsClosed()
+		case "closed":
+			return task.Status.IsClosed()
+		}
+		return string(task.Status) == t.Value
+	case FieldEnergy:
+		if t.IsNone() {
+			return task.Energy == ""
+		}
+		return task.Energy != "" && compareInts(task.Energy.Level(), models.Energy(t.Value).Level(), t.Op)
+	case FieldEstimate:
+		if t.IsNone() {
+			return task.Estimate == 0
+		}
+		return task.Estimate > 0 && compareDurations(task.Estimate, t.Duration, t.Op)
+	case FieldDue:
+		return t.matchTime(task.DueDate)
+	case FieldCreated:
+		return t.matchTime(task.CreatedAt)
+	case FieldClosed:
+		return t.matchTime(task.CompletedAt)
+	case FieldText:
+		return containsFold(task.Title, t.Value) || containsFold(task.Description, t.Value)
+	case FieldTitle:
+		return containsFold(task.Title, t.Value)
+	case FieldDescription:
+		return containsFold(task.Description, t.Value)
+	case FieldIs:
+		switch t.Value {
+		case "overdue":
+			return task.IsOverdue(t.Now)
+		case "blocked":
+			return len(task.BlockedBy) > 0 && !task.Completed
+		case "recurring":
+			return task.Recurrence != nil
+		case "subtask":
+			return task.ParentID != ""
+		case "snoozed":
+			return task.IsFocusSnoozed(t.Now)
+		}
+	}
+	return false
+}
+
+// matchTime checks a date field against the term's range. Unset dates only
+// match "none".
+func (t *Term) matchTime(value time.Time) bool {
+	if t.IsNone() {
+		return value.IsZero()
+	}
+	if value.IsZero() {
+		return false
+	}
+	if !t.From.IsZero() && value.Before(t.From) {
+		return false
+	}
+	if !t.To.IsZero() && !value.Before(t.To) {
+		return false
+	}
+	return true
+}
+
+func compareDurations(a, b time.Duration, op Op) bool {
+	switch {
+	case a < b:
+		return compareInts(0, 1, op)
+	case a > b:
+		return compareInts(1, 0, op)
+	}
+	return compareInts(0, 0, op)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// String formats the expressions joined by spaces
+func (e *And) String() string {
+	parts := make([]string, len(e.Exprs))
+	for i, expr := range e.Exprs {
+		parts[i] = groupString(expr)
+	}
+	return strings.Join(parts, " ")
+}
+
+// String formats the expressions joined by OR
+func (e *Or) String() string {
+	parts := make([]string, len(e.Exprs))
+	for i, expr := range e.Exprs {
+		parts[i] = groupString(expr)
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// String formats the negated expression with a leading "-"
+func (e *Not) String() string {
+	return "-" + groupString(e.Expr)
+}
+
+// String formats the term as field, operator and value
+func (t *Term) String() string {
+	op := string(t.Op)
+	switch t.Op {
+	case OpEqual, OpContains:
+	default:
+		op = ":" + op
+	}
+	return string(t.Field) + op + quoteValue(t.Value)
+}
+
+// groupString formats an expression, wrapping AND and OR groups in parentheses
+func groupString(expr Expr) string {
+	switch expr.(type) {
+	case *And, *Or:
+		return "(" + expr.String() + ")"
+	}
+	return expr.String()
+}
+
+// quoteValue quotes a value if it contains characters with a meaning in
+// query syntax
+func quoteValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\"()") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// Walk calls fn for every term in the expression
+func Walk(expr Expr, fn func(term *Term)) {
+	switch e := expr.(type) {
+	case *And:
+		for _, child := range e.Exprs {
+			Walk(child, fn)
+		}
+	case *Or:
+		for _, child := range e.Exprs {
+			Walk(child, fn)
+		}
+	case *Not:
+		Walk(e.Expr, fn)
+	case *Term:
+		fn(e)
+	}
+}
+
+// HasField reports whether the expression filters on any of the fields
+func HasField(expr Expr, fields ...Field) bool {
+	found := false
+	Walk(expr, func(term *Term) {
+		for _, field := range fields {
+			if term.Field == field {
+				found = true
+			}
+		}
+	})
+	return found
+}
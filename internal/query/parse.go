@@ -0,0 +1,480 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/todolist/internal/dateparse"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/quickadd"
+)
+
+// fieldNames maps the names and aliases accepted in queries to fields
+var fieldNames = map[string]Field{
+	"priority": FieldPriority, "p": FieldPriority,
+	"category": FieldCategory, "cat": FieldCategory,
+	"project": FieldProject,
+	"tag":     FieldTag, "tags": FieldTag,
+	"context": FieldContext, "ctx": FieldContext,
+	"status":   FieldStatus,
+	"energy":   FieldEnergy,
+	"estimate": FieldEstimate, "est": FieldEstimate,
+	"due":     FieldDue,
+	"created": FieldCreated,
+	"closed":  FieldClosed, "completed": FieldClosed,
+	"text":        FieldText,
+	"title":       FieldTitle,
+	"description": FieldDescription, "desc": FieldDescription,
+	"is": FieldIs,
+}
+
+// FieldList lists the fields for help and error messages
+const FieldList = "priority, category, project, tag, context, status, energy, estimate, due, created, closed, text, title, description, is"
+
+// isValues lists the values accepted by the "is" field
+var isValues = []string{"overdue", "blocked", "recurring", "subtask", "snoozed"}
+
+// tokenKind is the kind of a query token
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+// token is a lexical element of a query. Terms keep their text with quotes
+// removed; field is set when the text had a "field:" or "field~" prefix.
+type token struct {
+	kind   tokenKind
+	text   string
+	field  string
+	sep    byte
+	quoted bool
+}
+
+// Parse parses a query expression such as
+//
+//	priority:high due:<friday -tag:someday status:open text~"report"
+//
+// into a filter. Terms separated by spaces must all match; OR, NOT or a
+// leading "-", and parentheses combine them further. Words without a field
+// search the title and description, and the quick-add markers +tag, #category,
+// @context and !priority can be used as shorthands. Relative dates are
+// resolved against now and compared by day. An empty query returns a nil
+// expression, which matches every task.
+func Parse(input string, now time.Time) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &parser{tokens: tokens, now: now, dates: dateparse.NewWithClock(func() time.Time { return now })}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, models.Invalidf("unexpected %s in query", p.tokens[p.pos].describe())
+	}
+	return expr, nil
+}
+
+// lex splits a query into tokens
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		switch ch := runes[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+		case ch == '-' && i+1 < len(runes) && runes[i+1] != ' ':
+			tokens = append(tokens, token{kind: tokenNot, text: "-"})
+			i++
+		default:
+			tok, next, err := lexWord(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		}
+	}
+	return tokens, nil
+}
+
+// lexWord reads a term or keyword starting at runes[start] and returns it
+// with the position after it
+func lexWord(runes []rune, start int) (token, int, error) {
+	tok := token{kind: tokenTerm}
+	var text strings.Builder
+	i := start
+	for i < len(runes) {
+		ch := runes[i]
+		if ch == ' ' || ch == '\t' || ch == '\n' || ch == '(' || ch == ')' {
+			break
+		}
+
+		if ch == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				text.WriteRune(runes[end])
+				end++
+			}
+			if end >= len(runes) {
+				return tok, 0, models.Invalidf("unterminated quote in query")
+			}
+			tok.quoted = true
+			i = end + 1
+			continue
+		}
+
+		// The first ":" or "~" before any quote separates the field
+		if (ch == ':' || ch == '~') && tok.sep == 0 && !tok.quoted {
+			tok.field = text.String()
+			tok.sep = byte(ch)
+			text.Reset()
+			i++
+			continue
+		}
+
+		text.WriteRune(ch)
+		i++
+	}
+	tok.text = text.String()
+
+	if tok.sep == 0 && !tok.quoted {
+		switch strings.ToUpper(tok.text) {
+		case "OR", "|":
+			tok.kind = tokenOr
+		case "AND", "&":
+			tok.kind = tokenAnd
+		case "NOT":
+			tok.kind = tokenNot
+		}
+	}
+	return tok, i, nil
+}
+
+// describe formats a token for error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokenOpen, tokenClose:
+		return fmt.Sprintf("%q", t.text)
+	case tokenAnd, tokenOr, tokenNot:
+		return strings.ToUpper(t.text)
+	}
+	if t.sep != 0 {
+		return fmt.Sprintf("%q", t.field+string(t.sep)+t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// parser builds an expression from tokens by recursive descent. OR binds
+// loosest, then AND (written as a space), then NOT.
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+	dates  *dateparse.Parser
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	var exprs []Expr
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		tok, ok := p.peek()
+		if !ok || tok.kind != tokenOr {
+			break
+		}
+		p.pos++
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return &Or{Exprs: exprs}, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	var exprs []Expr
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokenOr || tok.kind == tokenClose {
+			break
+		}
+		if tok.kind == tokenAnd {
+			p.pos++
+			continue
+		}
+
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	switch len(exprs) {
+	case 0:
+		if tok, ok := p.peek(); ok {
+			return nil, models.Invalidf("expected a term before %s in query", tok.describe())
+		}
+		return nil, models.Invalidf("query ends where a term was expected")
+	case 1:
+		return exprs[0], nil
+	}
+	return &And{Exprs: exprs}, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, models.Invalidf("query ends where a term was expected")
+	}
+
+	switch tok.kind {
+	case tokenNot:
+		p.pos++
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+
+	case tokenOpen:
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok, ok := p.peek(); !ok || tok.kind != tokenClose {
+			return nil, models.Invalidf("missing \")\" in query")
+		}
+		p.pos++
+		return expr, nil
+
+	case tokenTerm:
+		p.pos++
+		return p.parseTerm(tok)
+	}
+	return nil, models.Invalidf("unexpected %s in query", tok.describe())
+}
+
+// parseTerm turns a term token into a term with a validated, normalized value
+func (p *parser) parseTerm(tok token) (Expr, error) {
+	if tok.sep == 0 {
+		return p.parseShorthand(tok)
+	}
+
+	field, ok := fieldNames[strings.ToLower(tok.field)]
+	if !ok {
+		return nil, models.Invalidf("unknown query field %q (use %s)", tok.field, FieldList)
+	}
+
+	term := &Term{Field: field, Op: OpEqual, Value: tok.text, Now: p.now}
+	if tok.sep == '~' {
+		term.Op = OpContains
+	} else if field != FieldText && field != FieldTitle && field != FieldDescription {
+		for _, op := range []Op{OpLessEq, OpGreatEq, OpLess, OpGreater, "="} {
+			if strings.HasPrefix(term.Value, string(op)) {
+				term.Value = strings.TrimPrefix(term.Value, string(op))
+				if op != "=" {
+					term.Op = op
+				}
+				break
+			}
+		}
+	}
+	term.Value = strings.TrimSpace(term.Value)
+	if term.Value == "" {
+		return nil, models.Invalidf("%s needs a value in query", tok.describe())
+	}
+
+	if err := p.normalize(term); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// parseShorthand parses a term without a field: a quick-add marker or text
+// to search for
+func (p *parser) parseShorthand(tok token) (Expr, error) {
+	term := &Term{Field: FieldText, Op: OpContains, Value: tok.text, Now: p.now}
+	if !tok.quoted && len(tok.text) > 1 {
+		switch tok.text[0] {
+		case '+':
+			term.Field, term.Op, term.Value = FieldTag, OpEqual, tok.text[1:]
+		case '#':
+			term.Field, term.Op, term.Value = FieldCategory, OpEqual, tok.text[1:]
+		case '@':
+			term.Field, term.Op, term.Value = FieldContext, OpEqual, tok.text[1:]
+		case '!':
+			term.Field, term.Op, term.Value = FieldPriority, OpEqual, tok.text[1:]
+		}
+	}
+
+	if err := p.normalize(term); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// normalize validates a term's operator and value and resolves dates and
+// durations
+func (p *parser) normalize(term *Term) error {
+	value := strings.ToLower(term.Value)
+	none := value == "none"
+
+	switch term.Field {
+	case FieldPriority, FieldEnergy, FieldEstimate, FieldDue, FieldCreated, FieldClosed:
+		if term.Op == OpContains {
+			return models.Invalidf("%s cannot be searched with \"~\" in query", term.Field)
+		}
+	case FieldText, FieldTitle, FieldDescription:
+		if term.Op != OpEqual && term.Op != OpContains {
+			return models.Invalidf("%s can only be searched with \"~\" in query", term.Field)
+		}
+		term.Op = OpContains
+		return nil
+	default:
+		if term.Op != OpEqual {
+			return models.Invalidf("%s can only be compared with \":\" in query", term.Field)
+		}
+	}
+	if none && term.Op != OpEqual {
+		return models.Invalidf("\"none\" can only be compared with \":\" in query")
+	}
+
+	switch term.Field {
+	case FieldPriority:
+		switch value {
+		case "high", "h":
+			term.Value = string(models.PriorityHigh)
+		case "medium", "med", "m":
+			term.Value = string(models.PriorityMedium)
+		case "low", "l":
+			term.Value = string(models.PriorityLow)
+		default:
+			return models.Invalidf("invalid priority %q in query (must be low, medium, or high)", term.Value)
+		}
+
+	case FieldCategory:
+		term.Value = strings.TrimPrefix(value, "#")
+
+	case FieldProject:
+		if none {
+			term.Value = "none"
+		}
+
+	case FieldTag:
+		term.Value = models.NormalizeTag(value)
+
+	case FieldContext:
+		term.Value = strings.TrimPrefix(value, "@")
+
+	case FieldStatus:
+		if value == "open" || value == "closed" {
+			term.Value = value
+			return nil
+		}
+		status, err := models.ParseStatus(value)
+		if err != nil {
+			return err
+		}
+		term.Value = string(status)
+
+	case FieldEnergy:
+		if none {
+			term.Value = "none"
+			return nil
+		}
+		energy, err := models.ParseEnergy(value)
+		if err != nil {
+			return err
+		}
+		term.Value = string(energy)
+
+	case FieldEstimate:
+		if none {
+			term.Value = "none"
+			return nil
+		}
+		estimate, err := quickadd.ParseEstimate(value)
+		if err != nil {
+			return err
+		}
+		term.Duration = estimate
+		term.Value = value
+
+	case FieldDue, FieldCreated, FieldClosed:
+		if none {
+			term.Value = "none"
+			return nil
+		}
+		when, err := p.dates.Parse(value)
+		if err != nil {
+			return models.Invalidf("invalid %s date in query: %w", term.Field, err)
+		}
+		term.Value = value
+		term.From, term.To = dayRange(when, term.Op)
+
+	case FieldIs:
+		for _, known := range isValues {
+			if value == known {
+				term.Value = value
+				return nil
+			}
+		}
+		return models.Invalidf("invalid value is:%s in query (use %s)", term.Value, strings.Join(isValues, ", "))
+	}
+	return nil
+}
+
+// dayRange returns the times a date comparison matches, in [from, to). Dates
+// are compared by day, so "due:<friday" means due before Friday and
+// "due:<=friday" due by the end of Friday.
+func dayRange(when time.Time, op Op) (from, to time.Time) {
+	day := time.Date(when.Year(), when.Month(), when.Day(), 0, 0, 0, 0, when.Location())
+	next := day.AddDate(0, 0, 1)
+
+	switch op {
+	case OpLess:
+		return time.Time{}, day
+	case OpLessEq:
+		return time.Time{}, next
+	case OpGreater:
+		return next, time.Time{}
+	case OpGreatEq:
+		return day, time.Time{}
+	}
+	return day, next
+}
@@ -0,0 +1,240 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// now is Wednesday 12 March 2025, 10:30
+var now = time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+
+func day(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseStructure(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// OR binds looser than AND, which binds looser than NOT
+		{"priority:high tag:work OR tag:home", "(priority:high tag:work) OR tag:home"},
+		{"tag:home OR priority:high tag:work", "tag:home OR (priority:high tag:work)"},
+		{"tag:a AND tag:b OR tag:c", "(tag:a tag:b) OR tag:c"},
+		{"tag:a | tag:b & tag:c", "tag:a OR (tag:b tag:c)"},
+		{"NOT tag:someday status:open", "-tag:someday status:open"},
+		{"-tag:a OR tag:b", "-tag:a OR tag:b"},
+
+		// Parentheses override precedence
+		{"priority:high (tag:work OR tag:home)", "priority:high (tag:work OR tag:home)"},
+		{"(tag:a OR tag:b) (tag:c OR tag:d)", "(tag:a OR tag:b) (tag:c OR tag:d)"},
+		{"((tag:a))", "tag:a"},
+
+		// Negation
+		{"-tag:someday", "-tag:someday"},
+		{"not tag:someday", "-tag:someday"},
+		{"-(tag:a OR tag:b)", "-(tag:a OR tag:b)"},
+		{"NOT -tag:a", "--tag:a"},
+		{"well-known", "text~well-known"},
+
+		// Quoted values keep spaces, parentheses and keywords
+		{`text~"weekly report"`, `text~"weekly report"`},
+		{`title~"plan (draft)"`, `title~"plan (draft)"`},
+		{`"weekly report"`, `text~"weekly report"`},
+		{`"OR"`, "text~OR"},
+		{`text~"say \"hi\""`, `text~"say \"hi\""`},
+		{`-"weekly report"`, `-text~"weekly report"`},
+
+		// Shorthands and normalized values
+		{"+Work #home @phone !h", "tag:work category:home context:phone priority:high"},
+		{"p:>=med est:<=30m", "priority:>=medium estimate:<=30m"},
+		{"status:open energy:none", "status:open energy:none"},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got := expr.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, input := range []string{"", "   "} {
+		expr, err := Parse(input, now)
+		if err != nil || expr != nil {
+			t.Errorf("Parse(%q) = %v, %v, want nil, nil", input, expr, err)
+		}
+	}
+}
+
+func TestParseMatch(t *testing.T) {
+	report := &models.Task{Title: "Send the Weekly Report", Priority: models.PriorityHigh, Tags: []string{"work"}}
+	status := &models.Task{Title: "Weekly status report", Priority: models.PriorityLow, Tags: []string{"work", "someday"}}
+	home := &models.Task{Title: "Clean", Description: "weekly report of chores", Priority: models.PriorityMedium, Tags: []string{"home"}}
+
+	tests := []struct {
+		input string
+		want  []*models.Task
+	}{
+		{`text~"weekly report"`, []*models.Task{report, home}},
+		{`title~"weekly report"`, []*models.Task{report}},
+		{`weekly report`, []*models.Task{report, status, home}},
+		{`-"weekly report"`, []*models.Task{status}},
+		{"-tag:someday", []*models.Task{report, home}},
+		{"NOT tag:work", []*models.Task{home}},
+		{"-(tag:work OR tag:home)", nil},
+		{"tag:work -tag:someday", []*models.Task{report}},
+		{"priority:high OR priority:low tag:someday", []*models.Task{report, status}},
+		{"(priority:high OR priority:low) -tag:someday", []*models.Task{report}},
+		{"priority:>medium OR tag:home", []*models.Task{report, home}},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.input, err)
+			continue
+		}
+
+		var got []*models.Task
+		for _, task := range []*models.Task{report, status, home} {
+			if expr.Match(task) {
+				got = append(got, task)
+			}
+		}
+		if !sameTasks(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.input, titles(got), titles(tt.want))
+		}
+	}
+}
+
+func TestParseRelativeDates(t *testing.T) {
+	tests := []struct {
+		input    string
+		from, to time.Time
+	}{
+		{"due:today", day(time.March, 12), day(time.March, 13)},
+		{"due:tomorrow", day(time.March, 13), day(time.March, 14)},
+		{"due:<friday", time.Time{}, day(time.March, 14)},
+		{"due:<=friday", time.Time{}, day(time.March, 15)},
+		{"due:>friday", day(time.March, 15), time.Time{}},
+		{"due:>=friday", day(time.March, 14), time.Time{}},
+		{"due:=2025-04-01", day(time.April, 1), day(time.April, 2)},
+		{`due:<"next week"`, time.Time{}, day(time.March, 19)},
+		{"created:>=monday", day(time.March, 17), time.Time{}},
+		{`closed:<"in 3 days"`, time.Time{}, day(time.March, 15)},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		term, ok := expr.(*Term)
+		if !ok {
+			t.Errorf("Parse(%q) = %s, want a date term", tt.input, expr)
+			continue
+		}
+		if !term.From.Equal(tt.from) || !term.To.Equal(tt.to) {
+			t.Errorf("Parse(%q) = [%v, %v), want [%v, %v)", tt.input, term.From, term.To, tt.from, tt.to)
+		}
+	}
+}
+
+func TestParseDateMatch(t *testing.T) {
+	dueFriday := &models.Task{Title: "Friday", DueDate: time.Date(2025, time.March, 14, 17, 0, 0, 0, time.UTC)}
+	dueToday := &models.Task{Title: "Today", DueDate: time.Date(2025, time.March, 12, 23, 59, 59, 0, time.UTC)}
+	noDue := &models.Task{Title: "Someday"}
+
+	tests := []struct {
+		input string
+		want  []*models.Task
+	}{
+		{"due:<friday", []*models.Task{dueToday}},
+		{"due:<=friday", []*models.Task{dueFriday, dueToday}},
+		{"due:friday", []*models.Task{dueFriday}},
+		{"due:>today", []*models.Task{dueFriday}},
+		{"due:none", []*models.Task{noDue}},
+		{"-due:none", []*models.Task{dueFriday, dueToday}},
+		{"-due:<friday", []*models.Task{dueFriday, noDue}},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.input, err)
+			continue
+		}
+
+		var got []*models.Task
+		for _, task := range []*models.Task{dueFriday, dueToday, noDue} {
+			if expr.Match(task) {
+				got = append(got, task)
+			}
+		}
+		if !sameTasks(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.input, titles(got), titles(tt.want))
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"(tag:a",
+		"tag:a)",
+		"()",
+		"tag:",
+		"OR tag:a",
+		"tag:a OR",
+		"NOT",
+		`text~"open`,
+		"color:red",
+		"priority:urgent",
+		"priority~high",
+		"due:~friday",
+		"due:someday",
+		"tag:<a",
+		"is:late",
+		"status:later",
+		"energy:max",
+		"estimate:soon",
+	}
+
+	for _, input := range tests {
+		_, err := Parse(input, now)
+		var validation *models.ValidationError
+		if !errors.As(err, &validation) {
+			t.Errorf("Parse(%q) = %v, want a validation error", input, err)
+		}
+	}
+}
+
+// sameTasks reports whether two slices hold the same tasks in the same order
+func sameTasks(a, b []*models.Task) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func titles(tasks []*models.Task) []string {
+	result := make([]string, len(tasks))
+	for i, task := range tasks {
+		result[i] = task.Title
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+// Package query parses and evaluates task filter expressions such as
+// `priority:high due:<friday -tag:someday status:open text~"report"`, and
+// sorts and pages the tasks that match.
+package query
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// SortKey orders tasks by one field
+type SortKey struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// SortFields lists the fields tasks can be sorted by
+var SortFields = []string{"due", "priority", "created", "closed", "title", "status", "estimate", "energy", "category"}
+
+// Query selects a page of tasks: the tasks matching Where, in Sort order,
+// skipping Offset tasks and returning at most Limit (0 for all)
+type Query struct {
+	Where  Expr
+	Sort   []SortKey
+	Offset int
+	Limit  int
+}
+
+// ParseSort parses a comma-separated list of sort fields such as
+// "due,-priority"; a leading "-" sorts in descending order
+func ParseSort(input string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(input, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		valid := false
+		for _, field := range SortFields {
+			if key.Field == field {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, models.Invalidf("cannot sort by %q (use %s)", key.Field, strings.Join(SortFields, ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Run filters, sorts and pages tasks. The slice is sorted in place.
+func (q Query) Run(tasks []*models.Task) *models.TaskPage {
+	matched := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if q.Where == nil || q.Where.Match(task) {
+			matched = append(matched, task)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return q.less(matched[i], matched[j])
+	})
+
+	page := &models.TaskPage{Total: len(matched), Offset: q.Offset, Limit: q.Limit}
+	start := q.Offset
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := len(matched)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+	page.Tasks = matched[start:end]
+	return page
+}
+
+// less orders two tasks by the sort keys, then by creation time
+func (q Query) less(a, b *models.Task) bool {
+	for _, key := range q.Sort {
+		if c := compareField(a, b, key.Field); c != 0 {
+			// Tasks without a value come last in either direction
+			if missing(a, key.Field) != missing(b, key.Field) {
+				return missing(b, key.Field)
+			}
+			if key.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// missing reports whether a task has no value for a sort field
+func missing(task *models.Task, field string) bool {
+	switch field {
+	case "due":
+		return task.DueDate.IsZero()
+	case "closed":
+		return task.CompletedAt.IsZero()
+	case "estimate":
+		return task.Estimate == 0
+	case "energy":
+		return task.Energy == ""
+	}
+	return false
+}
+
+// compareField compares a sort field of two tasks, returning -1, 0 or 1
+func compareField(a, b *models.Task, field string) int {
+	switch field {
+	case "due":
+		return compareTimes(a.DueDate, b.DueDate)
+	case "priority":
+		return compareLevels(priorityLevels[a.Priority], priorityLevels[b.Priority])
+	case "created":
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	case "closed":
+		return compareTimes(a.CompletedAt, b.CompletedAt)
+	case "title":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "status":
+		return compareLevels(statusIndex(a.Status), statusIndex(b.Status))
+	case "estimate":
+		return compareLevels(int(a.Estimate), int(b.Estimate))
+	case "energy":
+		return compareLevels(a.Energy.Level(), b.Energy.Level())
+	case "category":
+		return strings.Compare(string(a.Category), string(b.Category))
+	}
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func compareLevels(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// statusIndex returns the position of a status in the task lifecycle
+func statusIndex(status models.Status) int {
+	for i, s := range models.Statuses {
+		if s == status {
+			return i
+		}
+	}
+	return len(models.Statuses)
+}
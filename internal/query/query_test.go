@@ -0,0 +1,120 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// sortTasks returns tasks created a minute apart in the order given, so ties
+// fall back to that order
+func sortTasks() []*models.Task {
+	tasks := []*models.Task{
+		{ID: "a", Title: "No due date", Priority: models.PriorityHigh},
+		{ID: "b", Title: "Due friday", Priority: models.PriorityLow, DueDate: day(time.March, 14), Estimate: 30 * time.Minute},
+		{ID: "c", Title: "No due date either", Priority: models.PriorityMedium, Energy: models.EnergyHigh},
+		{ID: "d", Title: "Due today", Priority: models.PriorityHigh, DueDate: day(time.March, 12), Estimate: time.Hour, Energy: models.EnergyLow},
+	}
+	for i, task := range tasks {
+		task.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+	}
+	return tasks
+}
+
+func ids(tasks []*models.Task) []string {
+	result := make([]string, len(tasks))
+	for i, task := range tasks {
+		result[i] = task.ID
+	}
+	return result
+}
+
+func TestRunSort(t *testing.T) {
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"", []string{"a", "b", "c", "d"}},
+		{"due", []string{"d", "b", "a", "c"}},
+		{"-priority", []string{"a", "d", "c", "b"}},
+		{"-priority,due", []string{"d", "a", "c", "b"}},
+		{"title", []string{"b", "d", "a", "c"}},
+
+		// Tasks without a value come last in both directions
+		{"-due", []string{"b", "d", "a", "c"}},
+		{"estimate", []string{"b", "d", "a", "c"}},
+		{"-estimate", []string{"d", "b", "a", "c"}},
+		{"energy", []string{"d", "c", "a", "b"}},
+		{"-energy", []string{"c", "d", "a", "b"}},
+		{"closed", []string{"a", "b", "c", "d"}},
+		{"-closed", []string{"a", "b", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		keys, err := ParseSort(tt.sort)
+		if err != nil {
+			t.Fatalf("ParseSort(%q) returned error: %v", tt.sort, err)
+		}
+
+		page := Query{Sort: keys}.Run(sortTasks())
+		if got := ids(page.Tasks); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %q = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestParseSortErrors(t *testing.T) {
+	for _, input := range []string{"colour", "due,-size", "--due"} {
+		_, err := ParseSort(input)
+		var validation *models.ValidationError
+		if !errors.As(err, &validation) {
+			t.Errorf("ParseSort(%q) = %v, want a validation error", input, err)
+		}
+	}
+}
+
+func TestRunPaging(t *testing.T) {
+	tests := []struct {
+		name          string
+		offset, limit int
+		want          []string
+	}{
+		{"everything", 0, 0, []string{"a", "b", "c", "d"}},
+		{"first page", 0, 3, []string{"a", "b", "c"}},
+		{"last partial page", 3, 3, []string{"d"}},
+		{"limit exactly reaching the end", 2, 2, []string{"c", "d"}},
+		{"limit past the end", 2, 10, []string{"c", "d"}},
+		{"offset without limit", 1, 0, []string{"b", "c", "d"}},
+		{"offset at the end", 4, 2, []string{}},
+		{"offset past the end", 9, 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		page := Query{Offset: tt.offset, Limit: tt.limit}.Run(sortTasks())
+		if got := ids(page.Tasks); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if page.Total != 4 || page.Offset != tt.offset || page.Limit != tt.limit {
+			t.Errorf("%s: page has total %d, offset %d, limit %d, want 4, %d, %d",
+				tt.name, page.Total, page.Offset, page.Limit, tt.offset, tt.limit)
+		}
+	}
+}
+
+func TestRunFiltersBeforePaging(t *testing.T) {
+	where, err := Parse("priority:>=medium", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := Query{Where: where, Offset: 1, Limit: 1}.Run(sortTasks())
+	if got := ids(page.Tasks); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("got %v, want [c]", got)
+	}
+	if page.Total != 3 {
+		t.Errorf("total = %d, want 3", page.Total)
+	}
+}
@@ -0,0 +1,174 @@
+// Package quickadd extracts task metadata written inline in a task title, as
+// in "Call dentist tomorrow 3pm !high #health +errand @phone ~15m".
+package quickadd
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/user/todolist/internal/dateparse"
+	"github.com/user/todolist/internal/models"
+)
+
+// Syntax summarizes the inline markers understood by Parse
+const Syntax = "!priority #category +tag @context ~estimate %energy, with a due date such as 'tomorrow 3pm' or 'by friday' at the end"
+
+// priorities maps inline priority markers to priorities
+var priorities = map[string]models.Priority{
+	"high": models.PriorityHigh, "h": models.PriorityHigh, "!": models.PriorityHigh,
+	"medium": models.PriorityMedium, "med": models.PriorityMedium, "m": models.PriorityMedium,
+	"low": models.PriorityLow, "l": models.PriorityLow,
+}
+
+// Parse extracts metadata from a quick-add line and returns it as a task
+// specification. Markers are:
+//
+//	!high, !medium, !low   priority (also !h, !m, !l and !!)
+//	#name                  category
+//	+name                  tag (may be repeated)
+//	@name                  context, such as @phone or @computer
+//	~15m, ~1h30m, ~20      time estimate (a bare number means minutes)
+//	%low, %medium, %high   energy the task takes (also %l, %m, %h)
+//
+// Markers other than ~ must be followed by a letter, so "issue #42" stays in
+// the title. A date expression at the end of the line is used as the due date
+// when it follows "by", "on", "at", "due" or "before" ("by friday", "due
+// tomorrow"), or when it clearly is a date on its own ("tomorrow 3pm", "next
+// tuesday", "in 2 days"). Fields that are not present are left empty so that
+// callers can apply their own defaults.
+func Parse(input string, parser *dateparse.Parser) (models.TaskSpec, error) {
+	var spec models.TaskSpec
+	var words []string
+
+	for _, word := range strings.Fields(input) {
+		if !isMarker(word) {
+			words = append(words, word)
+			continue
+		}
+
+		marker, value := word[0], word[1:]
+		switch marker {
+		case '!':
+			priority, ok := priorities[strings.ToLower(value)]
+			if !ok {
+				return spec, models.Invalidf("unknown priority %q (use !high, !medium or !low)", word)
+			}
+			spec.Priority = priority
+		case '#':
+			spec.Category = models.Category(strings.ToLower(value))
+		case '+':
+			spec.Tags = appendUnique(spec.Tags, strings.ToLower(value))
+		case '@':
+			spec.Context = strings.ToLower(value)
+		case '~':
+			estimate, err := ParseEstimate(value)
+			if err != nil {
+				return spec, err
+			}
+			spec.Estimate = estimate
+		case '%':
+			energy, err := models.ParseEnergy(value)
+			if err != nil {
+				return spec, models.Invalidf("unknown energy level %q (use %%low, %%medium or %%high)", word)
+			}
+			spec.Energy = energy
+		default:
+			words = append(words, word)
+		}
+	}
+
+	// Use the longest trailing run of words that reads as a date, but always
+	// leave at least one word for the title
+	for start := 1; start < len(words); start++ {
+		cued := start > 1 && dateCues[strings.ToLower(words[start-1])]
+		if !cued && !startsDate(words[start]) {
+			continue
+		}
+
+		due, err := parser.Parse(strings.Join(words[start:], " "))
+		if err == nil {
+			spec.DueDate = due
+			words = words[:start]
+			if cued {
+				words = words[:start-1]
+			}
+			break
+		}
+	}
+
+	spec.Title = strings.Join(words, " ")
+	if spec.Title == "" {
+		return spec, models.Invalidf("title cannot be empty")
+	}
+
+	return spec, nil
+}
+
+// isMarker reports whether a word is an inline marker rather than part of the
+// title. Estimates start with a digit, the others with a letter ("!!" is the
+// short form of !high).
+func isMarker(word string) bool {
+	if len(word) < 2 || !strings.ContainsRune("!#+@~%", rune(word[0])) {
+		return false
+	}
+
+	first := []rune(word[1:])[0]
+	switch word[0] {
+	case '~':
+		return unicode.IsDigit(first)
+	case '!':
+		return word == "!!" || unicode.IsLetter(first)
+	}
+	return unicode.IsLetter(first)
+}
+
+// dateCues are words that introduce a due date at the end of a line. They are
+// dropped from the title.
+var dateCues = map[string]bool{"by": true, "on": true, "at": true, "due": true, "before": true}
+
+// dateStarts are words that begin a date expression and are rarely the end of
+// a title. Bare times of day ("lunch", "morning"), "now" and abbreviated
+// weekdays ("sun", "wed") are left out, since titles end with them too; they
+// are still read as dates after a cue word.
+var dateStarts = map[string]bool{
+	"today": true, "tonight": true, "tomorrow": true, "tmrw": true, "tmr": true,
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true, "saturday": true, "sunday": true,
+	"next": true, "in": true, "end": true, "eod": true, "eow": true, "eom": true, "eoy": true,
+}
+
+// startsDate reports whether a word can begin a due date without a cue word:
+// a word from dateStarts, or a number as in "2025-03-14", "5pm", "17:30" or
+// "2 weeks from now"
+func startsDate(word string) bool {
+	word = strings.ToLower(word)
+	if dateStarts[word] {
+		return true
+	}
+	return word[0] == '+' || unicode.IsDigit(rune(word[0]))
+}
+
+// ParseEstimate parses a time estimate such as "15m", "1h30m" or "20"
+// (a bare number is read as minutes)
+func ParseEstimate(value string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, models.Invalidf("bad estimate %q (use e.g. 15m, 1h or 1h30m)", value)
+	}
+	return d, nil
+}
+
+// appendUnique appends value to list if it is not already present
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
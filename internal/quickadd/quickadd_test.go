@@ -0,0 +1,115 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/dateparse"
+	"github.com/user/todolist/internal/models"
+)
+
+// now is Wednesday 12 March 2025, 10:30
+var now = time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	parser := dateparse.NewWithClock(func() time.Time { return now })
+	endOfDay := func(day int) time.Time { return time.Date(2025, time.March, day, 23, 59, 59, 0, time.UTC) }
+
+	tests := []struct {
+		input string
+		want  models.TaskSpec
+	}{
+		{
+			"Call dentist tomorrow 3pm !high #health +errand @phone ~15m",
+			models.TaskSpec{
+				Title:    "Call dentist",
+				Priority: models.PriorityHigh,
+				Category: "health",
+				Tags:     []string{"errand"},
+				Context:  "phone",
+				Estimate: 15 * time.Minute,
+				DueDate:  time.Date(2025, time.March, 13, 15, 0, 0, 0, time.UTC),
+			},
+		},
+		{"Buy milk !! %low", models.TaskSpec{Title: "Buy milk", Priority: models.PriorityHigh, Energy: models.EnergyLow}},
+		{"Tag it +A +a +b", models.TaskSpec{Title: "Tag it", Tags: []string{"a", "b"}}},
+
+		// Dates that clearly are dates
+		{"Pay rent next tuesday", models.TaskSpec{Title: "Pay rent", DueDate: endOfDay(18)}},
+		{"Check the oven in 2h", models.TaskSpec{Title: "Check the oven", DueDate: now.Add(2 * time.Hour)}},
+		{"Submit form 2025-03-20", models.TaskSpec{Title: "Submit form", DueDate: time.Date(2025, time.March, 20, 23, 59, 59, 0, time.UTC)}},
+		{"Buy 2 apples friday", models.TaskSpec{Title: "Buy 2 apples", DueDate: endOfDay(14)}},
+
+		// Dates after a cue word, which is dropped
+		{"Report due friday", models.TaskSpec{Title: "Report", DueDate: endOfDay(14)}},
+		{"Pay invoice by eow", models.TaskSpec{Title: "Pay invoice", DueDate: endOfDay(14)}},
+		{"Meet Sam at lunch", models.TaskSpec{Title: "Meet Sam", DueDate: time.Date(2025, time.March, 12, 12, 0, 0, 0, time.UTC)}},
+		{"Go to the market on sat", models.TaskSpec{Title: "Go to the market", DueDate: endOfDay(15)}},
+
+		// Ordinary words stay in the title
+		{"Get lunch", models.TaskSpec{Title: "Get lunch"}},
+		{"Plan the weekend", models.TaskSpec{Title: "Plan the weekend"}},
+		{"Start now", models.TaskSpec{Title: "Start now"}},
+		{"Enjoy the morning", models.TaskSpec{Title: "Enjoy the morning"}},
+		{"Sit in the sun", models.TaskSpec{Title: "Sit in the sun"}},
+		{"Look at 3 options", models.TaskSpec{Title: "Look at 3 options"}},
+		{"Due friday", models.TaskSpec{Title: "Due", DueDate: endOfDay(14)}},
+
+		// Markers must start with a letter
+		{"Fix issue #42 in parser", models.TaskSpec{Title: "Fix issue #42 in parser"}},
+		{"Email @ noon +1", models.TaskSpec{Title: "Email @ noon +1"}},
+		{"Grow 50% faster", models.TaskSpec{Title: "Grow 50% faster"}},
+		{"Say hi ~approx", models.TaskSpec{Title: "Say hi ~approx"}},
+		{"Wow !42", models.TaskSpec{Title: "Wow !42"}},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input, parser)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q)\n got %+v\nwant %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	parser := dateparse.NewWithClock(func() time.Time { return now })
+
+	for _, input := range []string{
+		"Do it !urgent",
+		"Do it %extreme",
+		"Do it ~0",
+		"!high #work",
+	} {
+		if got, err := Parse(input, parser); err == nil {
+			t.Errorf("Parse(%q) = %+v, want an error", input, got)
+		}
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"15m", 15 * time.Minute},
+		{"20", 20 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseEstimate(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseEstimate(%q) = %s, %v; want %s", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "0", "-5", "soon"} {
+		if _, err := ParseEstimate(input); err == nil {
+			t.Errorf("ParseEstimate(%q) succeeded, want an error", input)
+		}
+	}
+}
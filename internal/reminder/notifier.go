@@ -0,0 +1,102 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Notifier delivers alerts to the user
+type Notifier interface {
+	Notify(alert Alert) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(alert Alert) error
+
+// Notify calls f(alert)
+func (f NotifierFunc) Notify(alert Alert) error {
+	return f(alert)
+}
+
+// BellNotifier rings the terminal bell and prints the alert
+type BellNotifier struct {
+	Out io.Writer
+}
+
+// Notify writes the bell character and the alert message
+func (n BellNotifier) Notify(alert Alert) error {
+	_, err := fmt.Fprintf(n.Out, "\a%s\n", alert.Message())
+	return err
+}
+
+// CommandNotifier runs a shell command for every alert, e.g. a desktop
+// notification tool. The alert is passed in the TODOLIST_TASK_ID,
+// TODOLIST_TITLE, TODOLIST_KIND and TODOLIST_MESSAGE environment variables.
+type CommandNotifier struct {
+	Command string
+	Timeout time.Duration
+}
+
+// Notify runs the command and waits for it to finish
+func (n CommandNotifier) Notify(alert Alert) error {
+	timeout := n.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.Command)
+	cmd.Env = append(os.Environ(),
+		"TODOLIST_TASK_ID="+alert.Task.ID,
+		"TODOLIST_TITLE="+alert.Task.Title,
+		"TODOLIST_KIND="+alert.Kind,
+		"TODOLIST_MESSAGE="+alert.Message(),
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify command failed: %w: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// WebhookNotifier posts every alert as JSON to a URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify posts the alert to the webhook
+func (n WebhookNotifier) Notify(alert Alert) error {
+	body, err := json.Marshal(struct {
+		Alert
+		Message string `json:"message"`
+	}{alert, alert.Message()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package reminder delivers task reminders and due-date alerts at the right
+// time to a set of pluggable notifiers.
+package reminder
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// missedAfter is how late an alert can be delivered before it counts as missed
+const missedAfter = time.Minute
+
+// Alert is a reminder or due-date alert for a task
+type Alert struct {
+	Task   *models.Task `json:"task"`
+	Kind   string       `json:"kind"`
+	At     time.Time    `json:"at"`
+	Missed bool         `json:"missed,omitempty"`
+	Repeat bool         `json:"repeat,omitempty"`
+}
+
+// Message returns a human-readable description of the alert
+func (a Alert) Message() string {
+	var msg string
+	if a.Kind == "due" {
+		msg = fmt.Sprintf("Due: %s (due %s)", a.Task.Title, a.Task.DueDate.Format("2006-01-02 15:04"))
+	} else {
+		msg = fmt.Sprintf("Reminder: %s", a.Task.Title)
+	}
+
+	if a.Missed {
+		msg += fmt.Sprintf(" [missed at %s]", a.At.Format("2006-01-02 15:04"))
+	}
+	return msg
+}
+
+// Source provides the tasks to watch and records delivered alerts
+type Source interface {
+	GetAllTasks() ([]*models.Task, error)
+	MarkReminderNotified(id string, at time.Time) error
+}
+
+// Scheduler watches task reminders and due dates and delivers alerts when
+// they come up. Alerts are repeated every Repeat interval until they are
+// acknowledged or snoozed; a zero Repeat delivers each alert once.
+type Scheduler struct {
+	Source       Source
+	Notifiers    []Notifier
+	Repeat       time.Duration
+	PollInterval time.Duration
+	Now          func() time.Time
+
+	wake chan struct{}
+}
+
+// NewScheduler creates a scheduler delivering alerts to the given notifiers
+func NewScheduler(source Source, notifiers ...Notifier) *Scheduler {
+	return &Scheduler{
+		Source:       source,
+		Notifiers:    notifiers,
+		PollInterval: time.Minute,
+		Now:          time.Now,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Wake makes the scheduler re-read the tasks, e.g. after a task was changed
+func (s *Scheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers alerts until stop is closed. Alerts that came up while the
+// scheduler was not running are delivered immediately.
+func (s *Scheduler) Run(stop <-chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		case <-s.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+
+		next := s.Check()
+		timer.Reset(next.Sub(s.Now()))
+	}
+}
+
+// Check delivers every alert that is due and returns when to check next
+func (s *Scheduler) Check() time.Time {
+	now := s.Now()
+	next := now.Add(s.PollInterval)
+
+	tasks, err := s.Source.GetAllTasks()
+	if err != nil {
+		log.Printf("Reminder scheduler failed to get tasks: %v", err)
+		return next
+	}
+
+	for _, task := range tasks {
+		if at := task.NextAlert(now); !at.IsZero() && at.Before(next) {
+			next = at
+		}
+
+		at, ok := task.PendingAlert(now)
+		if !ok {
+			continue
+		}
+
+		var notifiedAt time.Time
+		if task.Reminder != nil {
+			notifiedAt = task.Reminder.NotifiedAt
+		}
+
+		alert := Alert{Task: task, Kind: task.AlertKind(now), At: at}
+		switch {
+		case notifiedAt.Before(at):
+			alert.Missed = now.Sub(at) > missedAfter
+		case s.Repeat > 0 && now.Sub(notifiedAt) >= s.Repeat:
+			alert.Repeat = true
+		default:
+			if s.Repeat > 0 && notifiedAt.Add(s.Repeat).Before(next) {
+				next = notifiedAt.Add(s.Repeat)
+			}
+			continue
+		}
+
+		s.deliver(alert)
+		if err := s.Source.MarkReminderNotified(task.ID, now); err != nil {
+			log.Printf("Reminder scheduler failed to record alert for task %s: %v", task.ID, err)
+		}
+		if s.Repeat > 0 && now.Add(s.Repeat).Before(next) {
+			next = now.Add(s.Repeat)
+		}
+	}
+
+	return next
+}
+
+func (s *Scheduler) deliver(alert Alert) {
+	for _, notifier := range s.Notifiers {
+		if err := notifier.Notify(alert); err != nil {
+			log.Printf("Failed to deliver reminder for task %s: %v", alert.Task.ID, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// Indexed task fields
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldNotes       = "notes"
+)
+
+// fieldWeights makes a match in the title count more than one in the
+// description or notes
+var fieldWeights = map[string]float64{
+	FieldTitle:       3,
+	FieldDescription: 1,
+	FieldNotes:       1,
+}
+
+// Posting records how often a term occurs in one field of a task
+type Posting struct {
+	TaskID string
+	Field  string
+	Count  int
+}
+
+// Source is an inverted index that searches can run against. Storage
+// implementations maintain one, in memory or on disk, as tasks change.
+type Source interface {
+	// DocumentCount returns the number of indexed tasks
+	DocumentCount() (int, error)
+	// Terms returns the indexed terms starting with prefix, or all of them
+	// for an empty prefix
+	Terms(prefix string) ([]string, error)
+	// Postings returns the postings of each of the given terms
+	Postings(terms []string) (map[string][]Posting, error)
+}
+
+// fieldTexts returns the indexed text of a task by field
+func fieldTexts(task *models.Task) map[string]string {
+	notes := make([]string, len(task.Notes))
+	for i, note := range task.Notes {
+		notes[i] = note.Text
+	}
+	return map[string]string{
+		FieldTitle:       task.Title,
+		FieldDescription: task.Description,
+		FieldNotes:       strings.Join(notes, "\n"),
+	}
+}
+
+// TaskPostings returns the postings of a task, keyed by term
+func TaskPostings(task *models.Task) map[string][]Posting {
+	postings := make(map[string][]Posting)
+	for field, text := range fieldTexts(task) {
+		counts := make(map[string]int)
+		for _, term := range Terms(text) {
+			counts[term]++
+		}
+		for term, count := range counts {
+			postings[term] = append(postings[term], Posting{TaskID: task.ID, Field: field, Count: count})
+		}
+	}
+	return postings
+}
+
+// Index is an in-memory inverted index. It is not safe for concurrent use.
+type Index struct {
+	postings map[string][]Posting
+	docs     map[string][]string // terms of each task, for removal
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string][]Posting),
+		docs:     make(map[string][]string),
+	}
+}
+
+// Add indexes a task, replacing an earlier version of it
+func (idx *Index) Add(task *models.Task) {
+	idx.Remove(task.ID)
+
+	postings := TaskPostings(task)
+	terms := make([]string, 0, len(postings))
+	for term, termPostings := range postings {
+		idx.postings[term] = append(idx.postings[term], termPostings...)
+		terms = append(terms, term)
+	}
+	idx.docs[task.ID] = terms
+}
+
+// Remove drops a task from the index
+func (idx *Index) Remove(id string) {
+	for _, term := range idx.docs[id] {
+		kept := idx.postings[term][:0]
+		for _, posting := range idx.postings[term] {
+			if posting.TaskID != id {
+				kept = append(kept, posting)
+			}
+		}
+		if len(kept) == 0 {
+			delete(idx.postings, term)
+		} else {
+			idx.postings[term] = kept
+		}
+	}
+	delete(idx.docs, id)
+}
+
+// DocumentCount returns the number of indexed tasks
+func (idx *Index) DocumentCount() (int, error) {
+	return len(idx.docs), nil
+}
+
+// Terms returns the indexed terms starting with prefix in sorted order
+func (idx *Index) Terms(prefix string) ([]string, error) {
+	var terms []string
+	for term := range idx.postings {
+		if strings.HasPrefix(term, prefix) {
+			terms = append(terms, term)
+		}
+	}
+	sort.Strings(terms)
+	return terms, nil
+}
+
+// Postings returns the postings of each of the given terms
+func (idx *Index) Postings(terms []string) (map[string][]Posting, error) {
+	result := make(map[string][]Posting, len(terms))
+	for _, term := range terms {
+		if postings, ok := idx.postings[term]; ok {
+			result[term] = postings
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,301 @@
+// Package search implements full-text search over tasks: an inverted index of
+// stemmed words, ranked matching with prefix and fuzzy lookups, and snippets
+// with the matching words marked.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// How much a term counts depending on how it matched a query word
+const (
+	exactWeight  = 1.0
+	prefixWeight = 0.6
+	fuzzyWeight  = 0.4
+)
+
+// Snippet limits
+const (
+	snippetContext = 30  // bytes shown before the first match
+	snippetLength  = 100 // maximum bytes of a snippet, without ellipses
+	maxSnippets    = 3
+)
+
+// Run searches the index for tasks containing every word of the query,
+// trying exact, prefix and fuzzy matches in turn, and returns them best first.
+// load fetches the tasks with the given IDs; missing ones are skipped.
+func Run(src Source, q models.SearchQuery, load func(ids []string) ([]*models.Task, error)) ([]*models.SearchResult, error) {
+	words := queryWords(q.Text)
+	if len(words) == 0 {
+		return nil, models.Invalidf("search text must contain at least one word")
+	}
+
+	// Find the index terms each query word matches
+	expansions := make([]map[string]float64, len(words))
+	highlight := make(map[string]bool)
+	var allTerms []string
+	for i, word := range words {
+		terms, err := expand(src, word, &allTerms)
+		if err != nil {
+			return nil, err
+		}
+		if len(terms) == 0 {
+			return []*models.SearchResult{}, nil
+		}
+		expansions[i] = terms
+		for term := range terms {
+			highlight[term] = true
+		}
+	}
+
+	lookup := make([]string, 0, len(highlight))
+	for term := range highlight {
+		lookup = append(lookup, term)
+	}
+	postings, err := src.Postings(lookup)
+	if err != nil {
+		return nil, err
+	}
+	docCount, err := src.DocumentCount()
+	if err != nil {
+		return nil, err
+	}
+
+	// Score the tasks, requiring a match for every word
+	scores := make(map[string]float64)
+	for i, terms := range expansions {
+		wordScores := make(map[string]float64)
+		for term, weight := range terms {
+			idf := inverseFrequency(postings[term], docCount)
+			termScores := make(map[string]float64)
+			for _, posting := range postings[term] {
+				termScores[posting.TaskID] += fieldWeights[posting.Field] * (1 + math.Log(float64(posting.Count)))
+			}
+			for id, score := range termScores {
+				wordScores[id] = math.Max(wordScores[id], weight*idf*score)
+			}
+		}
+
+		if i == 0 {
+			scores = wordScores
+			continue
+		}
+		for id := range scores {
+			if wordScore, ok := wordScores[id]; ok {
+				scores[id] += wordScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	tasks, err := load(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*models.SearchResult, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Status.IsClosed() && !q.All {
+			continue
+		}
+		results = append(results, &models.SearchResult{
+			Task:     task,
+			Score:    math.Round(scores[task.ID]*1000) / 1000,
+			Snippets: Snippets(task, highlight),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Task.CreatedAt.Equal(b.Task.CreatedAt) {
+			return a.Task.CreatedAt.After(b.Task.CreatedAt)
+		}
+		return a.Task.ID < b.Task.ID
+	})
+
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// queryWords returns the lowercase words of a search, without stop words
+// unless the search consists only of them
+func queryWords(text string) []string {
+	var words, stop []string
+	for _, token := range Tokenize(text) {
+		if IsStopWord(token.Word) {
+			stop = append(stop, token.Word)
+		} else {
+			words = append(words, token.Word)
+		}
+	}
+	if len(words) == 0 {
+		return stop
+	}
+	return words
+}
+
+// expand returns the index terms a query word matches with their weights:
+// its stem, terms the word is a prefix of and, failing those, terms within a
+// small edit distance. The full term list is loaded into allTerms on first use.
+func expand(src Source, word string, allTerms *[]string) (map[string]float64, error) {
+	stem := Stem(word)
+	terms := make(map[string]float64)
+
+	exact, err := src.Postings([]string{stem})
+	if err != nil {
+		return nil, err
+	}
+	if len(exact[stem]) > 0 {
+		terms[stem] = exactWeight
+	}
+
+	// The word as typed, since stemming a partial word can cut off too much
+	// ("pres" would become "pre")
+	if utf8.RuneCountInString(word) >= 2 {
+		matches, err := src.Terms(word)
+		if err != nil {
+			return nil, err
+		}
+		for _, term := range matches {
+			if _, ok := terms[term]; !ok {
+				terms[term] = prefixWeight
+			}
+		}
+	}
+	if len(terms) > 0 {
+		return terms, nil
+	}
+
+	maxDistance := 0
+	switch n := utf8.RuneCountInString(stem); {
+	case n >= 8:
+		maxDistance = 2
+	case n >= 4:
+		maxDistance = 1
+	}
+	if maxDistance == 0 {
+		return terms, nil
+	}
+
+	if *allTerms == nil {
+		loaded, err := src.Terms("")
+		if err != nil {
+			return nil, err
+		}
+		*allTerms = loaded
+	}
+	for _, term := range *allTerms {
+		if distance(stem, term, maxDistance) <= maxDistance {
+			terms[term] = fuzzyWeight
+		}
+	}
+	return terms, nil
+}
+
+// inverseFrequency weighs a term by how rare it is among the indexed tasks
+func inverseFrequency(postings []Posting, docCount int) float64 {
+	tasks := make(map[string]bool)
+	for _, posting := range postings {
+		tasks[posting.TaskID] = true
+	}
+	if len(tasks) == 0 {
+		return 0
+	}
+	return math.Log(1 + float64(docCount)/float64(len(tasks)))
+}
+
+// Snippets returns the passages of a task's title, description and notes
+// containing words whose stem is in terms
+func Snippets(task *models.Task, terms map[string]bool) []models.Snippet {
+	type passage struct{ field, text string }
+	passages := []passage{{FieldTitle, task.Title}, {FieldDescription, task.Description}}
+	for _, note := range task.Notes {
+		passages = append(passages, passage{FieldNotes, note.Text})
+	}
+
+	var snippets []models.Snippet
+	for _, p := range passages {
+		if snippet, ok := snippet(p.field, p.text, terms); ok {
+			snippets = append(snippets, snippet)
+			if len(snippets) == maxSnippets {
+				break
+			}
+		}
+	}
+	return snippets
+}
+
+// snippet cuts the passage around the first match out of a text
+func snippet(field, text string, terms map[string]bool) (models.Snippet, bool) {
+	tokens := Tokenize(text)
+	var matches []Token
+	for _, token := range tokens {
+		if terms[Stem(token.Word)] {
+			matches = append(matches, token)
+		}
+	}
+	if len(matches) == 0 {
+		return models.Snippet{}, false
+	}
+
+	// Start and end the window on word boundaries
+	start, end := 0, len(text)
+	if len(text) > snippetLength {
+		start = matches[0].Start - snippetContext
+		if start < 0 {
+			start = 0
+		}
+		for _, token := range tokens {
+			if token.Start >= start {
+				start = token.Start
+				break
+			}
+		}
+		end = start
+		for _, token := range tokens {
+			if token.Start >= start && token.End-start <= snippetLength {
+				end = token.End
+			}
+		}
+		if end == start {
+			end = matches[0].End
+		}
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(text) {
+		suffix = "…"
+	}
+
+	result := models.Snippet{
+		Field: field,
+		Text:  prefix + strings.Join(strings.Fields(text[start:end]), " ") + suffix,
+	}
+
+	// Collapsing whitespace moves the matches, so find them again
+	offset := len(prefix)
+	for _, token := range Tokenize(result.Text[offset : len(result.Text)-len(suffix)]) {
+		if terms[Stem(token.Word)] {
+			result.Matches = append(result.Matches, [2]int{token.Start + offset, token.End + offset})
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,235 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+var created = time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+
+// run indexes the tasks and searches them
+func run(t *testing.T, tasks []*models.Task, q models.SearchQuery) []*models.SearchResult {
+	t.Helper()
+
+	idx := NewIndex()
+	byID := make(map[string]*models.Task)
+	for _, task := range tasks {
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = created
+		}
+		if task.Status == "" {
+			task.Status = models.StatusTodo
+		}
+		idx.Add(task)
+		byID[task.ID] = task
+	}
+
+	results, err := Run(idx, q, func(ids []string) ([]*models.Task, error) {
+		var loaded []*models.Task
+		for _, id := range ids {
+			if task, ok := byID[id]; ok {
+				loaded = append(loaded, task)
+			}
+		}
+		return loaded, nil
+	})
+	if err != nil {
+		t.Fatalf("search %q returned error: %v", q.Text, err)
+	}
+	return results
+}
+
+func resultIDs(results []*models.SearchResult) []string {
+	ids := []string{}
+	for _, result := range results {
+		ids = append(ids, result.Task.ID)
+	}
+	return ids
+}
+
+func TestRunMatching(t *testing.T) {
+	tasks := []*models.Task{
+		{ID: "report", Title: "Reporting the quarterly numbers"},
+		{ID: "dentist", Title: "Call the dentist"},
+		{ID: "presentation", Title: "Renew presentation slides"},
+		{ID: "appointment", Title: "Book appointment"},
+		{ID: "plan", Title: "Planned trip"},
+		{ID: "mountain", Title: "Climb a mountain"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Inflections match through their shared stem
+		{"reports", []string{"report"}},
+		{"reported", []string{"report"}},
+		{"planning", []string{"plan"}},
+
+		// A word that is only the start of an indexed word matches by prefix
+		{"pres", []string{"presentation"}},
+		{"quart", []string{"report"}},
+		{"appoint", []string{"appointment"}},
+		{"d", []string{}},
+
+		// Short words allow one typo, words of eight letters or more two
+		{"dentst", []string{"dentist"}},
+		{"dentsit", []string{"dentist"}},
+		{"dntst", []string{}},
+		{"apointmnt", []string{"appointment"}},
+		{"apntmnt", []string{}},
+		{"muontian", []string{"mountain"}},
+		{"mountin", []string{"mountain"}},
+		{"muntin", []string{}},
+		{"cll", []string{}},
+
+		// Every word must match
+		{"call dentist", []string{"dentist"}},
+		{"call report", []string{}},
+
+		// Stop words are left out of the index, so they match nothing
+		{"the dentist", []string{"dentist"}},
+		{"the", []string{}},
+	}
+
+	for _, tt := range tests {
+		got := resultIDs(run(t, tasks, models.SearchQuery{Text: tt.query}))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("search %q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRunTitleAboveNotes(t *testing.T) {
+	tasks := []*models.Task{
+		{ID: "note", Title: "Quarterly review", Notes: []models.Note{{Text: "budget"}}, CreatedAt: created.Add(time.Hour)},
+		{ID: "title", Title: "Budget"},
+	}
+
+	results := run(t, tasks, models.SearchQuery{Text: "budget"})
+	if got := resultIDs(results); !reflect.DeepEqual(got, []string{"title", "note"}) {
+		t.Fatalf("search returned %v, want the title match first", got)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("title score %v is not above note score %v", results[0].Score, results[1].Score)
+	}
+}
+
+func TestRunClosedAndLimit(t *testing.T) {
+	tasks := []*models.Task{
+		{ID: "open", Title: "Write report"},
+		{ID: "done", Title: "Write report", Status: models.StatusDone, CreatedAt: created.Add(time.Hour)},
+		{ID: "newer", Title: "Write report", CreatedAt: created.Add(2 * time.Hour)},
+	}
+
+	if got := resultIDs(run(t, tasks, models.SearchQuery{Text: "report"})); !reflect.DeepEqual(got, []string{"newer", "open"}) {
+		t.Errorf("search = %v, want [newer open]", got)
+	}
+	if got := resultIDs(run(t, tasks, models.SearchQuery{Text: "report", All: true})); !reflect.DeepEqual(got, []string{"newer", "done", "open"}) {
+		t.Errorf("search --all = %v, want [newer done open]", got)
+	}
+	if got := resultIDs(run(t, tasks, models.SearchQuery{Text: "report", Limit: 1})); !reflect.DeepEqual(got, []string{"newer"}) {
+		t.Errorf("search --limit 1 = %v, want [newer]", got)
+	}
+}
+
+func TestRunWithoutWords(t *testing.T) {
+	for _, text := range []string{"", "  ", "?!"} {
+		_, err := Run(NewIndex(), models.SearchQuery{Text: text}, nil)
+		var validation *models.ValidationError
+		if !errors.As(err, &validation) {
+			t.Errorf("search %q = %v, want a validation error", text, err)
+		}
+	}
+}
+
+func TestIndexRemove(t *testing.T) {
+	task := &models.Task{ID: "1", Title: "Renew passport"}
+	other := &models.Task{ID: "2", Title: "Renew license"}
+
+	idx := NewIndex()
+	idx.Add(task)
+	idx.Add(other)
+	task.Title = "Renew visa"
+	idx.Add(task)
+	if terms, _ := idx.Terms("passport"); len(terms) != 0 {
+		t.Errorf("replaced title still indexed: %v", terms)
+	}
+	idx.Remove("2")
+	if terms, _ := idx.Terms("licen"); len(terms) != 0 {
+		t.Errorf("removed task still indexed: %v", terms)
+	}
+	if count, _ := idx.DocumentCount(); count != 1 {
+		t.Errorf("DocumentCount() = %d, want 1", count)
+	}
+}
+
+func TestSnippets(t *testing.T) {
+	long := "Before the trip we need to sort out travel insurance, book the kennel for the dog, " +
+		"and renew the passport at the town hall, which is only open in the mornings on weekdays."
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"short text is kept whole with whitespace collapsed", "Renew   the\n passport", "Renew the passport"},
+		{"match near the start", "Passport " + long, "Passport Before the trip we need to sort out travel insurance, book the kennel for the dog, and…"},
+		{"match in the middle", long, "…for the dog, and renew the passport at the town hall, which is only open in the mornings on weekdays…"},
+		{"match at the end", strings.Repeat("word ", 30) + "passport", "…word word word word word word passport"},
+	}
+
+	for _, tt := range tests {
+		task := &models.Task{Title: "Errands", Description: tt.text}
+		snippets := Snippets(task, map[string]bool{Stem("passport"): true})
+		if len(snippets) != 1 {
+			t.Errorf("%s: got %d snippets, want 1", tt.name, len(snippets))
+			continue
+		}
+
+		snippet := snippets[0]
+		if snippet.Field != FieldDescription {
+			t.Errorf("%s: field = %q, want %q", tt.name, snippet.Field, FieldDescription)
+		}
+		if snippet.Text != tt.want {
+			t.Errorf("%s: text = %q, want %q", tt.name, snippet.Text, tt.want)
+		}
+		body := strings.TrimSuffix(strings.TrimPrefix(snippet.Text, "…"), "…")
+		if len(body) > snippetLength {
+			t.Errorf("%s: snippet is %d bytes, longer than %d", tt.name, len(body), snippetLength)
+		}
+		for _, match := range snippet.Matches {
+			if got := strings.ToLower(snippet.Text[match[0]:match[1]]); got != "passport" {
+				t.Errorf("%s: match [%d, %d) covers %q, want \"passport\"", tt.name, match[0], match[1], got)
+			}
+		}
+		if len(snippet.Matches) == 0 {
+			t.Errorf("%s: no matches marked", tt.name)
+		}
+	}
+}
+
+func TestSnippetsLimit(t *testing.T) {
+	task := &models.Task{
+		Title:       "Plan",
+		Description: "Plan the week",
+		Notes:       []models.Note{{Text: "plan A"}, {Text: "plan B"}, {Text: "plan C"}},
+	}
+
+	snippets := Snippets(task, map[string]bool{"plan": true})
+	if len(snippets) != maxSnippets {
+		t.Fatalf("got %d snippets, want %d", len(snippets), maxSnippets)
+	}
+	fields := []string{snippets[0].Field, snippets[1].Field, snippets[2].Field}
+	if want := []string{FieldTitle, FieldDescription, FieldNotes}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("snippet fields = %v, want %v", fields, want)
+	}
+	if len(Snippets(task, map[string]bool{"week": false})) != 0 {
+		t.Error("snippets returned without a matching term")
+	}
+}
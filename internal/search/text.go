@@ -0,0 +1,178 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a word of a text with its byte offsets
+type Token struct {
+	Word       string // lowercase word
+	Start, End int
+}
+
+// Tokenize splits text into words made of letters and digits
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, Token{Word: strings.ToLower(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{Word: strings.ToLower(text[start:]), Start: start, End: len(text)})
+	}
+	return tokens
+}
+
+// stopWords are too common to be worth indexing
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+	"with": true,
+}
+
+// IsStopWord reports whether a lowercase word is left out of the index
+func IsStopWord(word string) bool {
+	return stopWords[word]
+}
+
+// Stem reduces a lowercase English word to its stem by stripping common
+// inflections, so that "reports", "reported" and "reporting" all become
+// "report". It is deliberately light: the same stem is produced for the index
+// and for queries, which is all matching needs.
+func Stem(word string) string {
+	if utf8.RuneCountInString(word) <= 3 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = word[:len(word)-1]
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed", "ly"} {
+		stem := strings.TrimSuffix(word, suffix)
+		if suffix == "ed" && strings.HasSuffix(stem, "e") {
+			// "need" and "speed" are not inflected
+			continue
+		}
+		if stem != word && len(stem) >= 3 && hasVowel(stem) {
+			word = undouble(stem)
+			break
+		}
+	}
+
+	// "make" and "making" both become "mak"
+	if len(word) > 3 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// hasVowel reports whether a word contains a vowel
+func hasVowel(word string) bool {
+	return strings.ContainsAny(word, "aeiouy")
+}
+
+// undouble removes a doubled final consonant, as in "planned" -> "plan"
+func undouble(word string) string {
+	n := len(word)
+	if n < 3 || word[n-1] != word[n-2] {
+		return word
+	}
+	switch word[n-1] {
+	case 'l', 's', 'z':
+		// "called", "passed" and "buzzed" keep both letters
+		return word
+	}
+	if word[n-1] < 'a' || word[n-1] > 'z' || strings.IndexByte("aeiou", word[n-1]) >= 0 {
+		return word
+	}
+	return word[:n-1]
+}
+
+// Terms returns the index terms of a text: the stems of its words, without
+// stop words
+func Terms(text string) []string {
+	var terms []string
+	for _, token := range Tokenize(text) {
+		if !IsStopWord(token.Word) {
+			terms = append(terms, Stem(token.Word))
+		}
+	}
+	return terms
+}
+
+// distance returns the edit distance between two words, counting a swap of
+// two adjacent letters as a single edit. It stops counting at max+1.
+func distance(a, b string, max int) int {
+	s, t := []rune(a), []rune(b)
+	if abs(len(s)-len(t)) > max {
+		return max + 1
+	}
+
+	// rows[i%3] holds row i of the classic dynamic programming table
+	var rows [3][]int
+	for i := range rows {
+		rows[i] = make([]int, len(t)+1)
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		row, prev, prev2 := rows[i%3], rows[(i-1)%3], rows[(i+1)%3]
+		row[0] = i
+		best := row[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			row[j] = min3(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && prev2[j-2]+1 < row[j] {
+				row[j] = prev2[j-2] + 1
+			}
+			if row[j] < best {
+				best = row[j]
+			}
+		}
+		if best > max {
+			return max + 1
+		}
+	}
+	return rows[len(s)%3][len(t)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
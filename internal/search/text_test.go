@@ -0,0 +1,94 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		// Inflections of a word share its stem
+		{[]string{"report", "reports", "reported", "reporting"}, "report"},
+		{[]string{"plan", "plans", "planned", "planning"}, "plan"},
+		{[]string{"make", "makes", "making"}, "mak"},
+		{[]string{"story", "stories"}, "story"},
+		{[]string{"call", "calls", "called", "calling"}, "call"},
+		{[]string{"pass", "passes", "passed"}, "pass"},
+
+		// Words that only look inflected are left alone
+		{[]string{"need"}, "need"},
+		{[]string{"speed"}, "speed"},
+		{[]string{"status"}, "status"},
+		{[]string{"analysis"}, "analysis"},
+		{[]string{"bus"}, "bus"},
+		{[]string{"sing"}, "sing"},
+	}
+
+	for _, tt := range tests {
+		for _, word := range tt.words {
+			if got := Stem(word); got != tt.want {
+				t.Errorf("Stem(%q) = %q, want %q", word, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestStemKeepsWordsApart(t *testing.T) {
+	pairs := [][2]string{
+		{"report", "repo"},
+		{"planet", "plan"},
+		{"called", "cal"},
+		{"string", "str"},
+	}
+	for _, pair := range pairs {
+		if Stem(pair[0]) == Stem(pair[1]) {
+			t.Errorf("Stem(%q) and Stem(%q) are both %q", pair[0], pair[1], Stem(pair[0]))
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("Planning the Q3 reports, and call Anna!")
+	want := []string{"plan", "q3", "report", "call", "anna"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms() = %v, want %v", got, want)
+	}
+}
+
+func TestTokenizeOffsets(t *testing.T) {
+	text := "Café — naïve plan"
+	if got := len(Tokenize(text)); got != 3 {
+		t.Fatalf("Tokenize() returned %d tokens, want 3", got)
+	}
+	for _, token := range Tokenize(text) {
+		if got := text[token.Start:token.End]; strings.ToLower(got) != token.Word {
+			t.Errorf("token %q has offsets [%d, %d) covering %q", token.Word, token.Start, token.End, got)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{"dentist", "dentist", 1, 0},
+		{"dentst", "dentist", 1, 1},
+		{"dentsit", "dentist", 1, 1}, // swapped letters count once
+		{"dntst", "dentist", 1, 2},   // stops counting at max+1
+		{"apointmnt", "appointment", 2, 2},
+		{"apntmnt", "appointment", 2, 3},
+		{"kitten", "sitting", 5, 3},
+	}
+
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("distance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,784 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/user/todolist/internal/app"
+	"github.com/user/todolist/internal/dateparse"
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/protocol"
+	"github.com/user/todolist/internal/storage"
+)
+
+func processRequest(todoApp *app.App, request protocol.Request) protocol.Response {
+	var response protocol.Response
+
+	switch request.Operation {
+	case protocol.OpAddTask:
+		var addReq protocol.AddTaskRequest
+		if err := json.Unmarshal(request.Payload, &addReq); err != nil {
+			return failureResponse("Invalid add task request", err)
+		}
+
+		if err := resolveDateExpressions(todoApp, &addReq); err != nil {
+			return failureResponse("Invalid add task request", err)
+		}
+
+		task, err := todoApp.AddTask(addReq.Spec())
+		if err != nil {
+			return failureResponse("Failed to add task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpBatchAdd:
+		var batchReq protocol.BatchAddRequest
+		if err := json.Unmarshal(request.Payload, &batchReq); err != nil {
+			return failureResponse("Invalid batch add request", err)
+		}
+
+		specs := make([]models.TaskSpec, len(batchReq.Tasks))
+		for i := range batchReq.Tasks {
+			if err := resolveDateExpressions(todoApp, &batchReq.Tasks[i]); err != nil {
+				return failureResponse(fmt.Sprintf("Invalid batch add request: task %d", i+1), err)
+			}
+			specs[i] = batchReq.Tasks[i].Spec()
+		}
+
+		tasks, err := todoApp.AddTasks(specs)
+		if err != nil {
+			return failureResponse("Failed to add tasks", err)
+		}
+
+		tasksResp := protocol.TasksResponse{Tasks: tasks}
+		payload, _ := json.Marshal(tasksResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpAddSubtask:
+		var addReq protocol.AddSubtaskRequest
+		if err := json.Unmarshal(request.Payload, &addReq); err != nil {
+			return failureResponse("Invalid add subtask request", err)
+		}
+
+		if err := resolveDateExpressions(todoApp, &addReq.AddTaskRequest); err != nil {
+			return failureResponse("Invalid add subtask request", err)
+		}
+
+		task, err := todoApp.AddSubtask(addReq.ParentID, addReq.Spec())
+		if err != nil {
+			return failureResponse("Failed to add subtask", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpMoveTask:
+		var moveReq protocol.MoveTaskRequest
+		if err := json.Unmarshal(request.Payload, &moveReq); err != nil {
+			return failureResponse("Invalid move task request", err)
+		}
+
+		if err := todoApp.MoveTask(moveReq.ID, moveReq.ParentID); err != nil {
+			return failureResponse("Failed to move task", err)
+		}
+
+		response.Success = true
+
+	case protocol.OpSetStatus:
+		var statusReq protocol.SetStatusRequest
+		if err := json.Unmarshal(request.Payload, &statusReq); err != nil {
+			return failureResponse("Invalid set status request", err)
+		}
+
+		task, err := todoApp.SetTaskStatus(statusReq.ID, statusReq.Status)
+		if err != nil {
+			return failureResponse("Failed to set status", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpAddNote:
+		var noteReq protocol.AddNoteRequest
+		if err := json.Unmarshal(request.Payload, &noteReq); err != nil {
+			return failureResponse("Invalid add note request", err)
+		}
+
+		task, err := todoApp.AddNote(noteReq.ID, noteReq.Text)
+		if err != nil {
+			return failureResponse("Failed to add note", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpReopenTask:
+		var idReq protocol.IDRequest
+		if err := json.Unmarshal(request.Payload, &idReq); err != nil {
+			return failureResponse("Invalid reopen task request", err)
+		}
+
+		task, err := todoApp.ReopenTask(idReq.ID)
+		if err != nil {
+			return failureResponse("Failed to reopen task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpBlockTask:
+		var blockReq protocol.BlockTaskRequest
+		if err := json.Unmarshal(request.Payload, &blockReq); err != nil {
+			return failureResponse("Invalid block task request", err)
+		}
+
+		task, err := todoApp.BlockTask(blockReq.ID, blockReq.BlockerID)
+		if err != nil {
+			return failureResponse("Failed to block task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpUnblockTask:
+		var blockReq protocol.BlockTaskRequest
+		if err := json.Unmarshal(request.Payload, &blockReq); err != nil {
+			return failureResponse("Invalid unblock task request", err)
+		}
+
+		task, err := todoApp.UnblockTask(blockReq.ID, blockReq.BlockerID)
+		if err != nil {
+			return failureResponse("Failed to unblock task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetTask:
+		var idReq protocol.IDRequest
+		if err := json.Unmarshal(request.Payload, &idReq); err != nil {
+			return failureResponse("Invalid get task request", err)
+		}
+
+		task, err := todoApp.GetTask(idReq.ID)
+		if err != nil {
+			return failureResponse("Failed to get task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetAllTasks:
+		tasks, err := todoApp.GetAllTasks()
+		if err != nil {
+			return failureResponse("Failed to get tasks", err)
+		}
+
+		tasksResp := protocol.TasksResponse{Tasks: tasks}
+		payload, _ := json.Marshal(tasksResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetTasksByCategory:
+		var catReq protocol.CategoryRequest
+		if err := json.Unmarshal(request.Payload, &catReq); err != nil {
+			return failureResponse("Invalid category request", err)
+		}
+
+		tasks, err := todoApp.GetTasksByCategory(catReq.Category)
+		if err != nil {
+			return failureResponse("Failed to get tasks by category", err)
+		}
+
+		tasksResp := protocol.TasksResponse{Tasks: tasks}
+		payload, _ := json.Marshal(tasksResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetTasksByPriority:
+		var prioReq protocol.PriorityRequest
+		if err := json.Unmarshal(request.Payload, &prioReq); err != nil {
+			return failureResponse("Invalid priority request", err)
+		}
+
+		tasks, err := todoApp.GetTasksByPriority(prioReq.Priority)
+		if err != nil {
+			return failureResponse("Failed to get tasks by priority", err)
+		}
+
+		tasksResp := protocol.TasksResponse{Tasks: tasks}
+		payload, _ := json.Marshal(tasksResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpQuery:
+		var queryReq protocol.QueryRequest
+		if err := json.Unmarshal(request.Payload, &queryReq); err != nil {
+			return failureResponse("Invalid query request", err)
+		}
+
+		page, err := todoApp.QueryTasks(queryReq.Query)
+		if err != nil {
+			return failureResponse("Failed to query tasks", err)
+		}
+
+		queryResp := protocol.QueryResponse{Page: page}
+		payload, _ := json.Marshal(queryResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpSearch:
+		var searchReq protocol.SearchRequest
+		if err := json.Unmarshal(request.Payload, &searchReq); err != nil {
+			return failureResponse("Invalid search request", err)
+		}
+
+		results, err := todoApp.SearchTasks(searchReq.Query)
+		if err != nil {
+			return failureResponse("Failed to search tasks", err)
+		}
+
+		searchResp := protocol.SearchResponse{Results: results}
+		payload, _ := json.Marshal(searchResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpUpdateTask:
+		var taskReq protocol.TaskResponse
+		if err := json.Unmarshal(request.Payload, &taskReq); err != nil {
+			return failureResponse("Invalid update task request", err)
+		}
+
+		if err := todoApp.UpdateTask(taskReq.Task); err != nil {
+			return failureResponse("Failed to update task", err)
+		}
+
+		response.Success = true
+
+	case protocol.OpPatchTask:
+		var patchReq protocol.PatchTaskRequest
+		if err := json.Unmarshal(request.Payload, &patchReq); err != nil {
+			return failureResponse("Invalid patch task request", err)
+		}
+
+		task, err := todoApp.PatchTask(patchReq.ID, patchReq.Patch)
+		if err != nil {
+			return failureResponse("Failed to patch task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpDeleteTask:
+		var idReq protocol.IDRequest
+		if err := json.Unmarshal(request.Payload, &idReq); err != nil {
+			return failureResponse("Invalid delete task request", err)
+		}
+
+		if err := todoApp.DeleteTask(idReq.ID); err != nil {
+			return failureResponse("Failed to delete task", err)
+		}
+
+		response.Success = true
+
+	case protocol.OpCompleteTask:
+		var idReq protocol.IDRequest
+		if err := json.Unmarshal(request.Payload, &idReq); err != nil {
+			return failureResponse("Invalid complete task request", err)
+		}
+
+		if err := todoApp.CompleteTask(idReq.ID); err != nil {
+			return failureResponse("Failed to complete task", err)
+		}
+
+		response.Success = true
+
+	case protocol.OpAddTags:
+		var tagsReq protocol.TagsRequest
+		if err := json.Unmarshal(request.Payload, &tagsReq); err != nil {
+			return failureResponse("Invalid add tags request", err)
+		}
+
+		task, err := todoApp.AddTags(tagsReq.ID, tagsReq.Tags)
+		if err != nil {
+			return failureResponse("Failed to add tags", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpRemoveTags:
+		var tagsReq protocol.TagsRequest
+		if err := json.Unmarshal(request.Payload, &tagsReq); err != nil {
+			return failureResponse("Invalid remove tags request", err)
+		}
+
+		task, err := todoApp.RemoveTags(tagsReq.ID, tagsReq.Tags)
+		if err != nil {
+			return failureResponse("Failed to remove tags", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpRenameTags:
+		var renameReq protocol.RenameTagsRequest
+		if err := json.Unmarshal(request.Payload, &renameReq); err != nil {
+			return failureResponse("Invalid rename tags request", err)
+		}
+
+		updated, err := todoApp.RenameTags(renameReq.From, renameReq.To)
+		if err != nil {
+			return failureResponse("Failed to rename tags", err)
+		}
+
+		renameResp := protocol.RenameTagsResponse{Updated: updated}
+		payload, _ := json.Marshal(renameResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetTags:
+		tags, err := todoApp.TagCounts()
+		if err != nil {
+			return failureResponse("Failed to get tags", err)
+		}
+
+		tagsResp := protocol.TagCountsResponse{Tags: tags}
+		payload, _ := json.Marshal(tagsResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpAddProject:
+		var projectReq protocol.ProjectRequest
+		if err := json.Unmarshal(request.Payload, &projectReq); err != nil {
+			return failureResponse("Invalid add project request", err)
+		}
+
+		project, err := todoApp.AddProject(projectReq.Project)
+		if err != nil {
+			return failureResponse("Failed to add project", err)
+		}
+
+		projectResp := protocol.ProjectResponse{Project: project}
+		payload, _ := json.Marshal(projectResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpUpdateProject:
+		var updateReq protocol.UpdateProjectRequest
+		if err := json.Unmarshal(request.Payload, &updateReq); err != nil {
+			return failureResponse("Invalid update project request", err)
+		}
+
+		project, err := todoApp.UpdateProject(updateReq.Project, updateReq.Patch)
+		if err != nil {
+			return failureResponse("Failed to update project", err)
+		}
+
+		projectResp := protocol.ProjectResponse{Project: project}
+		payload, _ := json.Marshal(projectResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpArchiveProject:
+		var archiveReq protocol.ArchiveProjectRequest
+		if err := json.Unmarshal(request.Payload, &archiveReq); err != nil {
+			return failureResponse("Invalid archive project request", err)
+		}
+
+		project, err := todoApp.ArchiveProject(archiveReq.Project, archiveReq.Archived)
+		if err != nil {
+			return failureResponse("Failed to archive project", err)
+		}
+
+		projectResp := protocol.ProjectResponse{Project: project}
+		payload, _ := json.Marshal(projectResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpDeleteProject:
+		var refReq protocol.ProjectRefRequest
+		if err := json.Unmarshal(request.Payload, &refReq); err != nil {
+			return failureResponse("Invalid delete project request", err)
+		}
+
+		moved, err := todoApp.DeleteProject(refReq.Project)
+		if err != nil {
+			return failureResponse("Failed to delete project", err)
+		}
+
+		deleteResp := protocol.DeleteProjectResponse{Moved: moved}
+		payload, _ := json.Marshal(deleteResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetProjects:
+		var projectsReq protocol.ProjectsRequest
+		if len(request.Payload) > 0 {
+			if err := json.Unmarshal(request.Payload, &projectsReq); err != nil {
+				return failureResponse("Invalid get projects request", err)
+			}
+		}
+
+		projects, err := todoApp.GetProjects(projectsReq.IncludeArchived)
+		if err != nil {
+			return failureResponse("Failed to get projects", err)
+		}
+
+		projectsResp := protocol.ProjectsResponse{Projects: projects}
+		payload, _ := json.Marshal(projectsResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetProjectSummary:
+		var refReq protocol.ProjectRefRequest
+		if err := json.Unmarshal(request.Payload, &refReq); err != nil {
+			return failureResponse("Invalid get project summary request", err)
+		}
+
+		summary, err := todoApp.ProjectSummary(refReq.Project)
+		if err != nil {
+			return failureResponse("Failed to get project summary", err)
+		}
+
+		summaryResp := protocol.ProjectSummaryResponse{Summary: summary}
+		payload, _ := json.Marshal(summaryResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetProjectSummaries:
+		var projectsReq protocol.ProjectsRequest
+		if len(request.Payload) > 0 {
+			if err := json.Unmarshal(request.Payload, &projectsReq); err != nil {
+				return failureResponse("Invalid get project summaries request", err)
+			}
+		}
+
+		summaries, err := todoApp.ProjectSummaries(projectsReq.IncludeArchived)
+		if err != nil {
+			return failureResponse("Failed to get project summaries", err)
+		}
+
+		summariesResp := protocol.ProjectSummariesResponse{Summaries: summaries}
+		payload, _ := json.Marshal(summariesResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpGetReminders:
+		tasks, err := todoApp.PendingReminders()
+		if err != nil {
+			return failureResponse("Failed to get reminders", err)
+		}
+
+		tasksResp := protocol.TasksResponse{Tasks: tasks}
+		payload, _ := json.Marshal(tasksResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpAcknowledgeReminder:
+		var idReq protocol.IDRequest
+		if err := json.Unmarshal(request.Payload, &idReq); err != nil {
+			return failureResponse("Invalid acknowledge reminder request", err)
+		}
+
+		if err := todoApp.AcknowledgeReminder(idReq.ID); err != nil {
+			return failureResponse("Failed to acknowledge reminder", err)
+		}
+
+		response.Success = true
+
+	case protocol.OpSnoozeReminder:
+		var snoozeReq protocol.SnoozeReminderRequest
+		if err := json.Unmarshal(request.Payload, &snoozeReq); err != nil {
+			return failureResponse("Invalid snooze reminder request", err)
+		}
+
+		if err := todoApp.SnoozeReminder(snoozeReq.ID, snoozeReq.Until); err != nil {
+			return failureResponse("Failed to snooze reminder", err)
+		}
+
+		response.Success = true
+
+	case protocol.OpBackup:
+		var backupReq protocol.BackupRequest
+		if err := json.Unmarshal(request.Payload, &backupReq); err != nil {
+			return failureResponse("Invalid backup request", err)
+		}
+
+		filename, err := todoApp.BackupTasks()
+		if err != nil {
+			return failureResponse("Failed to backup tasks", err)
+		}
+
+		backupResp := protocol.BackupResponse{Filename: filename}
+		payload, _ := json.Marshal(backupResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpRestore:
+		var restoreReq protocol.RestoreRequest
+		if err := json.Unmarshal(request.Payload, &restoreReq); err != nil {
+			return failureResponse("Invalid restore request", err)
+		}
+
+		if err := todoApp.RestoreTasks(restoreReq.Filename); err != nil {
+			return failureResponse("Failed to restore tasks", err)
+		}
+
+		response.Success = true
+
+	case protocol.OpListBackups:
+		backups, err := todoApp.ListBackups()
+		if err != nil {
+			return failureResponse("Failed to list backups", err)
+		}
+
+		backupsResp := protocol.ListBackupsResponse{Backups: backups}
+		payload, _ := json.Marshal(backupsResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpFocusMode:
+		// Older clients send no payload and only want the best suggestion
+		focusReq := protocol.FocusModeRequest{Options: models.FocusOptions{Limit: 1}}
+		if len(request.Payload) > 0 {
+			if err := json.Unmarshal(request.Payload, &focusReq); err != nil {
+				return failureResponse("Invalid focus mode request", err)
+			}
+		}
+
+		suggestions, err := todoApp.FocusMode(focusReq.Options)
+		if err != nil {
+			return failureResponse("Failed to enter focus mode", err)
+		}
+
+		focusResp := protocol.FocusModeResponse{Suggestions: suggestions}
+		if len(suggestions) > 0 {
+			focusResp.Task = suggestions[0].Task
+		}
+		payload, _ := json.Marshal(focusResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpSkipFocus:
+		var skipReq protocol.SkipFocusRequest
+		if err := json.Unmarshal(request.Payload, &skipReq); err != nil {
+			return failureResponse("Invalid skip focus request", err)
+		}
+
+		task, err := todoApp.SkipFocusTask(skipReq.ID, skipReq.Reason, skipReq.Until)
+		if err != nil {
+			return failureResponse("Failed to skip task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpSnoozeFocus:
+		var snoozeReq protocol.SnoozeFocusRequest
+		if err := json.Unmarshal(request.Payload, &snoozeReq); err != nil {
+			return failureResponse("Invalid snooze focus request", err)
+		}
+
+		task, err := todoApp.SnoozeFocusTask(snoozeReq.ID, snoozeReq.Until)
+		if err != nil {
+			return failureResponse("Failed to snooze task", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpStartPomodoro:
+		var pomReq protocol.PomodoroRequest
+		if err := json.Unmarshal(request.Payload, &pomReq); err != nil {
+			return failureResponse("Invalid pomodoro request", err)
+		}
+
+		status, err := todoApp.StartPomodoro(pomReq.TaskID, pomReq.CustomDuration, pomReq.Cycles)
+		if err != nil {
+			return failureResponse("Failed to start pomodoro", err)
+		}
+
+		return pomodoroResponse(status)
+
+	case protocol.OpPomodoroStatus:
+		status, err := todoApp.PomodoroStatus()
+		if err != nil {
+			return failureResponse("Failed to get pomodoro status", err)
+		}
+
+		return pomodoroResponse(status)
+
+	case protocol.OpPausePomodoro:
+		status, err := todoApp.PausePomodoro()
+		if err != nil {
+			return failureResponse("Failed to pause pomodoro", err)
+		}
+
+		return pomodoroResponse(status)
+
+	case protocol.OpResumePomodoro:
+		status, err := todoApp.ResumePomodoro()
+		if err != nil {
+			return failureResponse("Failed to resume pomodoro", err)
+		}
+
+		return pomodoroResponse(status)
+
+	case protocol.OpSkipPomodoro:
+		status, err := todoApp.SkipPomodoroPhase()
+		if err != nil {
+			return failureResponse("Failed to skip pomodoro phase", err)
+		}
+
+		return pomodoroResponse(status)
+
+	case protocol.OpFinishPomodoro:
+		status, err := todoApp.FinishPomodoroCycle()
+		if err != nil {
+			return failureResponse("Failed to finish pomodoro", err)
+		}
+
+		return pomodoroResponse(status)
+
+	case protocol.OpLogInterruption:
+		var addReq protocol.AddTaskRequest
+		if err := json.Unmarshal(request.Payload, &addReq); err != nil {
+			return failureResponse("Invalid log interruption request", err)
+		}
+
+		if err := resolveDateExpressions(todoApp, &addReq); err != nil {
+			return failureResponse("Invalid log interruption request", err)
+		}
+
+		task, err := todoApp.LogInterruption(addReq.Spec())
+		if err != nil {
+			return failureResponse("Failed to log interruption", err)
+		}
+
+		taskResp := protocol.TaskResponse{Task: task}
+		payload, _ := json.Marshal(taskResp)
+		response.Success = true
+		response.Payload = payload
+
+	case protocol.OpStopPomodoro:
+		// Older clients send no payload
+		var stopReq protocol.StopPomodoroRequest
+		if len(request.Payload) > 0 {
+			if err := json.Unmarshal(request.Payload, &stopReq); err != nil {
+				return failureResponse("Invalid stop pomodoro request", err)
+			}
+		}
+
+		status, err := todoApp.StopPomodoro(stopReq.Reason)
+		if err != nil {
+			return failureResponse("Failed to stop pomodoro", err)
+		}
+
+		return pomodoroResponse(status)
+
+	case protocol.OpGetPomodoroHistory:
+		var historyReq protocol.PomodoroHistoryRequest
+		if err := json.Unmarshal(request.Payload, &historyReq); err != nil {
+			return failureResponse("Invalid pomodoro history request", err)
+		}
+
+		records, err := todoApp.GetPomodoroHistory(historyReq.Since)
+		if err != nil {
+			return failureResponse("Failed to get pomodoro history", err)
+		}
+
+		historyResp := protocol.PomodoroHistoryResponse{Records: records}
+		payload, _ := json.Marshal(historyResp)
+		response.Success = true
+		response.Payload = payload
+
+	default:
+		return errorResponse(fmt.Sprintf("Unknown operation: %s", request.Operation))
+	}
+
+	return response
+}
+
+// resolveDateExpressions fills in the due date and reminder from their
+// natural-language expressions when no explicit time was sent, relative to
+// the app's clock
+func resolveDateExpressions(todoApp *app.App, req *protocol.AddTaskRequest) error {
+	parser := dateparse.NewWithClock(todoApp.Clock.Now)
+
+	if req.DueExpr != "" && req.DueDate.IsZero() {
+		dueDate, err := parser.Parse(req.DueExpr)
+		if err != nil {
+			return models.Invalidf("invalid due date: %w", err)
+		}
+		req.DueDate = dueDate
+	}
+
+	if req.ReminderExpr != "" && req.ReminderAt.IsZero() {
+		reminderAt, err := parser.Parse(req.ReminderExpr)
+		if err != nil {
+			return models.Invalidf("invalid reminder time: %w", err)
+		}
+		req.ReminderAt = reminderAt
+	}
+
+	return nil
+}
+
+// pomodoroResponse creates a successful response carrying the pomodoro state
+func pomodoroResponse(status *models.PomodoroStatus) protocol.Response {
+	payload, _ := json.Marshal(protocol.PomodoroResponse{Status: status})
+	return protocol.Response{Success: true, Payload: payload}
+}
+
+// failureResponse returns an error response for err, with the code for the
+// kind of error it is
+func failureResponse(message string, err error) protocol.Response {
+	response := errorResponse(fmt.Sprintf("%s: %v", message, err))
+	switch {
+	case errors.As(err, new(storage.ErrTaskNotFound)):
+		response.Code = protocol.CodeTaskNotFound
+	case errors.As(err, new(storage.ErrProjectNotFound)):
+		response.Code = protocol.CodeProjectNotFound
+	case errors.As(err, new(*models.ValidationError)):
+		response.Code = protocol.CodeInvalidInput
+	}
+	return response
+}
+
+func errorResponse(message string) protocol.Response {
+	return protocol.Response{
+		Success: false,
+		Error:   message,
+	}
+}
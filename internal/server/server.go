@@ -0,0 +1,132 @@
+// Package server answers the requests of TodoList clients on TCP
+// connections. Each request is a JSON line as described in protocol.go and is
+// carried out by the app.
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/user/todolist/internal/app"
+	"github.com/user/todolist/internal/events"
+	"github.com/user/todolist/internal/protocol"
+	"github.com/user/todolist/internal/reminder"
+)
+
+// Serve accepts connections on the listener and handles each one until the
+// listener is closed
+func Serve(listener net.Listener, todoApp *app.App, scheduler *reminder.Scheduler) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			log.Printf("Error accepting connection: %v", err)
+			continue
+		}
+
+		go handleConnection(conn, todoApp, scheduler)
+	}
+}
+
+func handleConnection(conn net.Conn, todoApp *app.App, scheduler *reminder.Scheduler) {
+	defer conn.Close()
+
+	clientAddr := conn.RemoteAddr().String()
+	log.Printf("New connection from %s", clientAddr)
+
+	reader := bufio.NewReader(conn)
+	writer := &responseWriter{conn: conn}
+
+	// Ends the event subscription of this connection, if any
+	unsubscribe := func() {}
+	defer func() { unsubscribe() }()
+
+	for {
+		// Read request
+		requestData, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				log.Printf("Client %s disconnected", clientAddr)
+			} else {
+				log.Printf("Error reading from client %s: %v", clientAddr, err)
+			}
+			return
+		}
+
+		// Parse request
+		var request protocol.Request
+		if err := json.Unmarshal(requestData, &request); err != nil {
+			writer.send(errorResponse(fmt.Sprintf("Invalid request format: %v", err)))
+			continue
+		}
+
+		if request.Operation == protocol.OpSubscribe {
+			unsubscribe()
+			var eventCh <-chan events.Event
+			eventCh, unsubscribe = todoApp.Events.Subscribe()
+
+			// Acknowledge before forwarding, so the client sees no event
+			// ahead of the answer to its request
+			if err := writer.send(protocol.Response{ID: request.ID, Success: true}); err != nil {
+				log.Printf("Error sending response to client %s: %v", clientAddr, err)
+				return
+			}
+			go forwardEvents(eventCh, request.ID, writer)
+			log.Printf("Client %s subscribed to events", clientAddr)
+			continue
+		}
+
+		// Process request
+		response := processRequest(todoApp, request)
+		response.ID = request.ID
+
+		// Tasks may have changed, so let the scheduler pick up new reminder times
+		if response.Success {
+			scheduler.Wake()
+		}
+
+		// Send response
+		if err := writer.send(response); err != nil {
+			log.Printf("Error sending response to client %s: %v", clientAddr, err)
+			return
+		}
+	}
+}
+
+// responseWriter serializes responses and events written to one connection
+type responseWriter struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (w *responseWriter) send(response protocol.Response) error {
+	responseData, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	responseData = append(responseData, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.conn.Write(responseData)
+	return err
+}
+
+// forwardEvents sends events to a subscribed client until the subscription ends
+func forwardEvents(eventCh <-chan events.Event, subscriptionID string, writer *responseWriter) {
+	for event := range eventCh {
+		event := event
+		response := protocol.Response{ID: subscriptionID, Success: true, Event: &event}
+		if err := writer.send(response); err != nil {
+			return
+		}
+	}
+}
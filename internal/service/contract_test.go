@@ -0,0 +1,403 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/service"
+	"github.com/user/todolist/internal/storage"
+	"github.com/user/todolist/internal/testutil"
+)
+
+// newLocal returns an app working on a fresh data directory
+func newLocal(t *testing.T) service.TaskService {
+	t.Helper()
+	return testutil.NewApp(t)
+}
+
+// newRemote returns a client connected to an in-process server that works on
+// a fresh data directory
+func newRemote(t *testing.T) service.TaskService {
+	t.Helper()
+	return testutil.NewClient(t, testutil.NewApp(t))
+}
+
+// TestContract runs the same scenarios against the local and the remote
+// implementation of the service
+func TestContract(t *testing.T) {
+	implementations := []struct {
+		name string
+		new  func(t *testing.T) service.TaskService
+	}{
+		{"local", newLocal},
+		{"remote", newRemote},
+	}
+
+	scenarios := []struct {
+		name string
+		run  func(t *testing.T, svc service.TaskService)
+	}{
+		{"add and get", testAddAndGet},
+		{"list", testList},
+		{"complete", testComplete},
+		{"edit", testEdit},
+		{"delete", testDelete},
+		{"subtasks", testSubtasks},
+		{"unknown task", testUnknownTask},
+		{"backup and restore", testBackupRestore},
+		{"focus", testFocus},
+		{"pomodoro", testPomodoro},
+		{"error kinds", testErrorKinds},
+	}
+
+	for _, impl := range implementations {
+		for _, scenario := range scenarios {
+			t.Run(impl.name+"/"+scenario.name, func(t *testing.T) {
+				scenario.run(t, impl.new(t))
+			})
+		}
+	}
+}
+
+func mustAdd(t *testing.T, svc service.TaskService, spec models.TaskSpec) *models.Task {
+	t.Helper()
+	task, err := svc.AddTask(spec)
+	if err != nil {
+		t.Fatalf("AddTask(%q) failed: %v", spec.Title, err)
+	}
+	return task
+}
+
+func mustGet(t *testing.T, svc service.TaskService, id string) *models.Task {
+	t.Helper()
+	task, err := svc.GetTask(id)
+	if err != nil {
+		t.Fatalf("GetTask(%s) failed: %v", id, err)
+	}
+	return task
+}
+
+func testAddAndGet(t *testing.T, svc service.TaskService) {
+	added := mustAdd(t, svc, models.TaskSpec{
+		Title:       "Write report",
+		Description: "Quarterly numbers",
+		Priority:    models.PriorityHigh,
+		Category:    models.Category("work"),
+		Tags:        []string{"review"},
+	})
+	if added.ID == "" {
+		t.Fatal("added task has no ID")
+	}
+
+	task := mustGet(t, svc, added.ID)
+	if task.Title != "Write report" || task.Description != "Quarterly numbers" {
+		t.Errorf("got title %q and description %q", task.Title, task.Description)
+	}
+	if task.Priority != models.PriorityHigh || task.Category != "work" {
+		t.Errorf("got priority %s and category %s", task.Priority, task.Category)
+	}
+	if len(task.Tags) != 1 || task.Tags[0] != "review" {
+		t.Errorf("got tags %v", task.Tags)
+	}
+	if task.Completed || task.Status != models.StatusTodo {
+		t.Errorf("new task is %s (completed %v)", task.Status, task.Completed)
+	}
+}
+
+func testList(t *testing.T, svc service.TaskService) {
+	mustAdd(t, svc, models.TaskSpec{Title: "Buy milk", Priority: models.PriorityLow, Category: "personal"})
+	mustAdd(t, svc, models.TaskSpec{Title: "Fix bug", Priority: models.PriorityHigh, Category: "work"})
+	mustAdd(t, svc, models.TaskSpec{Title: "Send invoice", Priority: models.PriorityHigh, Category: "work"})
+
+	tests := []struct {
+		name string
+		list func() ([]*models.Task, error)
+		want int
+	}{
+		{"all", svc.GetAllTasks, 3},
+		{"category", func() ([]*models.Task, error) { return svc.GetTasksByCategory("work") }, 2},
+		{"priority", func() ([]*models.Task, error) { return svc.GetTasksByPriority(models.PriorityLow) }, 1},
+	}
+	for _, tt := range tests {
+		tasks, err := tt.list()
+		if err != nil {
+			t.Fatalf("%s: list failed: %v", tt.name, err)
+		}
+		if len(tasks) != tt.want {
+			t.Errorf("%s: got %d tasks, want %d", tt.name, len(tasks), tt.want)
+		}
+	}
+}
+
+func testComplete(t *testing.T, svc service.TaskService) {
+	added := mustAdd(t, svc, models.TaskSpec{Title: "Call dentist"})
+	if err := svc.CompleteTask(added.ID); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+
+	task := mustGet(t, svc, added.ID)
+	if !task.Completed || task.Status != models.StatusDone {
+		t.Errorf("completed task is %s (completed %v)", task.Status, task.Completed)
+	}
+	if task.CompletedAt.IsZero() {
+		t.Error("completed task has no completion time")
+	}
+
+	reopened, err := svc.ReopenTask(added.ID)
+	if err != nil {
+		t.Fatalf("ReopenTask failed: %v", err)
+	}
+	if reopened.Completed || reopened.Status != models.StatusTodo {
+		t.Errorf("reopened task is %s (completed %v)", reopened.Status, reopened.Completed)
+	}
+}
+
+func testEdit(t *testing.T, svc service.TaskService) {
+	added := mustAdd(t, svc, models.TaskSpec{Title: "Draft", Priority: models.PriorityLow})
+
+	title := "Final draft"
+	priority := models.PriorityHigh
+	patched, err := svc.PatchTask(added.ID, models.TaskPatch{Title: &title, Priority: &priority})
+	if err != nil {
+		t.Fatalf("PatchTask failed: %v", err)
+	}
+	if patched.Title != title || patched.Priority != priority {
+		t.Errorf("patch returned title %q and priority %s", patched.Title, patched.Priority)
+	}
+
+	task := mustGet(t, svc, added.ID)
+	if task.Title != title || task.Priority != priority {
+		t.Errorf("stored task has title %q and priority %s", task.Title, task.Priority)
+	}
+
+	empty := ""
+	if _, err := svc.PatchTask(added.ID, models.TaskPatch{Title: &empty}); err == nil {
+		t.Error("PatchTask with an empty title succeeded")
+	}
+	if task := mustGet(t, svc, added.ID); task.Title != title {
+		t.Errorf("failed patch changed the title to %q", task.Title)
+	}
+}
+
+func testDelete(t *testing.T, svc service.TaskService) {
+	kept := mustAdd(t, svc, models.TaskSpec{Title: "Keep"})
+	deleted := mustAdd(t, svc, models.TaskSpec{Title: "Delete"})
+
+	if err := svc.DeleteTask(deleted.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := svc.GetTask(deleted.ID); err == nil {
+		t.Error("deleted task can still be fetched")
+	}
+
+	tasks, err := svc.GetAllTasks()
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != kept.ID {
+		t.Errorf("got %d tasks after deleting, want only %s", len(tasks), kept.ID)
+	}
+}
+
+func testSubtasks(t *testing.T, svc service.TaskService) {
+	parent := mustAdd(t, svc, models.TaskSpec{Title: "Move house"})
+	child, err := svc.AddSubtask(parent.ID, models.TaskSpec{Title: "Pack books"})
+	if err != nil {
+		t.Fatalf("AddSubtask failed: %v", err)
+	}
+	if child.ParentID != parent.ID {
+		t.Errorf("subtask has parent %q, want %q", child.ParentID, parent.ID)
+	}
+
+	// Completing the parent completes its subtasks
+	if err := svc.CompleteTask(parent.ID); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if task := mustGet(t, svc, child.ID); !task.Completed {
+		t.Error("subtask is still open after completing its parent")
+	}
+
+	// Moving a subtask to the top level and back
+	if err := svc.MoveTask(child.ID, ""); err != nil {
+		t.Fatalf("MoveTask to the top level failed: %v", err)
+	}
+	if task := mustGet(t, svc, child.ID); task.ParentID != "" {
+		t.Errorf("moved task still has parent %q", task.ParentID)
+	}
+	if err := svc.MoveTask(child.ID, parent.ID); err != nil {
+		t.Fatalf("MoveTask under the parent failed: %v", err)
+	}
+	if err := svc.MoveTask(parent.ID, child.ID); err == nil {
+		t.Error("moving a task under its own subtask succeeded")
+	}
+
+	// Deleting the parent deletes its subtasks
+	if err := svc.DeleteTask(parent.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := svc.GetTask(child.ID); err == nil {
+		t.Error("subtask survived deleting its parent")
+	}
+}
+
+func testUnknownTask(t *testing.T, svc service.TaskService) {
+	const id = "does-not-exist"
+	title := "New title"
+	operations := []struct {
+		name string
+		run  func() error
+	}{
+		{"get", func() error { _, err := svc.GetTask(id); return err }},
+		{"complete", func() error { return svc.CompleteTask(id) }},
+		{"patch", func() error { _, err := svc.PatchTask(id, models.TaskPatch{Title: &title}); return err }},
+		{"delete", func() error { return svc.DeleteTask(id) }},
+		{"add subtask", func() error { _, err := svc.AddSubtask(id, models.TaskSpec{Title: "Orphan"}); return err }},
+	}
+	for _, op := range operations {
+		err := op.run()
+		if err == nil {
+			t.Errorf("%s of an unknown task succeeded", op.name)
+		} else if !errors.As(err, new(storage.ErrTaskNotFound)) {
+			t.Errorf("%s of an unknown task failed with %q, want a task not found error", op.name, err)
+		}
+	}
+}
+
+// testErrorKinds checks that errors keep their kind, so the CLI picks the
+// same exit code in both modes
+func testErrorKinds(t *testing.T, svc service.TaskService) {
+	if _, err := svc.ProjectSummary("no such project"); !errors.As(err, new(storage.ErrProjectNotFound)) {
+		t.Errorf("unknown project failed with %v, want a project not found error", err)
+	}
+
+	_, err := svc.FocusMode(models.FocusOptions{Limit: -1})
+	var validationErr *models.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("negative limit failed with %v, want a validation error", err)
+	}
+	if !strings.Contains(validationErr.Error(), "must not be negative") {
+		t.Errorf("validation error %q lost its message", validationErr)
+	}
+
+	if _, err := svc.ReopenTask(mustAdd(t, svc, models.TaskSpec{Title: "Open"}).ID); !errors.As(err, new(*models.ValidationError)) {
+		t.Errorf("reopening an open task failed with %v, want a validation error", err)
+	}
+}
+
+func testBackupRestore(t *testing.T, svc service.TaskService) {
+	kept := mustAdd(t, svc, models.TaskSpec{Title: "Before the backup"})
+	backup, err := svc.BackupTasks()
+	if err != nil {
+		t.Fatalf("BackupTasks failed: %v", err)
+	}
+
+	backups, err := svc.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 1 || backups[0] != backup {
+		t.Errorf("got backups %v, want only %s", backups, backup)
+	}
+
+	mustAdd(t, svc, models.TaskSpec{Title: "After the backup"})
+	if err := svc.DeleteTask(kept.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if err := svc.RestoreTasks(backup); err != nil {
+		t.Fatalf("RestoreTasks failed: %v", err)
+	}
+
+	tasks, err := svc.GetAllTasks()
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != kept.ID || tasks[0].Title != kept.Title {
+		t.Errorf("got %d tasks after restoring, want only %q", len(tasks), kept.Title)
+	}
+}
+
+func testFocus(t *testing.T, svc service.TaskService) {
+	high := mustAdd(t, svc, models.TaskSpec{Title: "Plan roadmap", Priority: models.PriorityHigh})
+	mustAdd(t, svc, models.TaskSpec{Title: "Tidy desk", Priority: models.PriorityLow})
+
+	titles := func() []string {
+		t.Helper()
+		suggestions, err := svc.FocusMode(models.FocusOptions{})
+		if err != nil {
+			t.Fatalf("FocusMode failed: %v", err)
+		}
+		var titles []string
+		for _, suggestion := range suggestions {
+			titles = append(titles, suggestion.Task.Title)
+			if len(suggestion.Factors) == 0 {
+				t.Errorf("suggestion %q has no score factors", suggestion.Task.Title)
+			}
+		}
+		return titles
+	}
+	if got := titles(); len(got) != 2 || got[0] != "Plan roadmap" {
+		t.Errorf("got suggestions %v, want Plan roadmap first", got)
+	}
+
+	skipped, err := svc.SkipFocusTask(high.ID, "too tired", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SkipFocusTask failed: %v", err)
+	}
+	if skipped.FocusSkipCount() != 1 {
+		t.Errorf("task was skipped %d times, want 1", skipped.FocusSkipCount())
+	}
+	if got := titles(); len(got) != 1 || got[0] != "Tidy desk" {
+		t.Errorf("after skipping got suggestions %v, want only Tidy desk", got)
+	}
+
+	if _, err := svc.SnoozeFocusTask(high.ID, time.Now().Add(-time.Hour)); !errors.As(err, new(*models.ValidationError)) {
+		t.Errorf("snoozing until a past time failed with %v, want a validation error", err)
+	}
+}
+
+func testPomodoro(t *testing.T, svc service.TaskService) {
+	task := mustAdd(t, svc, models.TaskSpec{Title: "Write chapter"})
+
+	if _, err := svc.StartPomodoro("does-not-exist", 0, 1); !errors.As(err, new(storage.ErrTaskNotFound)) {
+		t.Errorf("starting a session for an unknown task failed with %v, want a task not found error", err)
+	}
+
+	status, err := svc.StartPomodoro(task.ID, 0, 1)
+	if err != nil {
+		t.Fatalf("StartPomodoro failed: %v", err)
+	}
+	if !status.Active || status.TaskID != task.ID || status.Phase != models.PhaseWork {
+		t.Errorf("got status %+v, want a work phase for %s", status, task.ID)
+	}
+	if _, err := svc.StartPomodoro(task.ID, 0, 1); !errors.As(err, new(*models.ValidationError)) {
+		t.Errorf("starting a second session failed with %v, want a validation error", err)
+	}
+
+	if status, err = svc.PausePomodoro(); err != nil || !status.Paused {
+		t.Fatalf("PausePomodoro returned %+v, %v", status, err)
+	}
+	if status, err = svc.ResumePomodoro(); err != nil || status.Paused {
+		t.Fatalf("ResumePomodoro returned %+v, %v", status, err)
+	}
+	if status, err = svc.PomodoroStatus(); err != nil || !status.Active {
+		t.Fatalf("PomodoroStatus returned %+v, %v", status, err)
+	}
+
+	if status, err = svc.StopPomodoro("phone call"); err != nil || status.Active {
+		t.Fatalf("StopPomodoro returned %+v, %v", status, err)
+	}
+	if _, err := svc.PausePomodoro(); !errors.As(err, new(*models.ValidationError)) {
+		t.Errorf("pausing without a session failed with %v, want a validation error", err)
+	}
+
+	records, err := svc.GetPomodoroHistory(time.Time{})
+	if err != nil {
+		t.Fatalf("GetPomodoroHistory failed: %v", err)
+	}
+	if len(records) != 1 || records[0].TaskID != task.ID || records[0].Completed || records[0].Reason != "phone call" {
+		t.Errorf("got records %+v, want one interrupted record for %s", records, task.ID)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// TaskService defines every task operation the CLI relies on. It is implemented
+// by app.App for local mode and by client.Client for server mode, so commands
+// behave the same regardless of where the tasks live.
+type TaskService interface {
+	// Task operations
+	AddTask(spec models.TaskSpec) (*models.Task, error)
+	AddTasks(specs []models.TaskSpec) ([]*models.Task, error)
+	AddSubtask(parentID string, spec models.TaskSpec) (*models.Task, error)
+	MoveTask(id, parentID string) error
+	BlockTask(id, blockerID string) (*models.Task, error)
+	UnblockTask(id, blockerID string) (*models.Task, error)
+	GetTask(id string) (*models.Task, error)
+	GetAllTasks() ([]*models.Task, error)
+	GetTasksByCategory(category models.Category) ([]*models.Task, error)
+	GetTasksByPriority(priority models.Priority) ([]*models.Task, error)
+	QueryTasks(q models.TaskQuery) (*models.TaskPage, error)
+	SearchTasks(q models.SearchQuery) ([]*models.SearchResult, error)
+	UpdateTask(task *models.Task) error
+	PatchTask(id string, patch models.TaskPatch) (*models.Task, error)
+	DeleteTask(id string) error
+	CompleteTask(id string) error
+	SetTaskStatus(id string, status models.Status) (*models.Task, error)
+	ReopenTask(id string) (*models.Task, error)
+	AddNote(id, text string) (*models.Task, error)
+
+	// Tag operations
+	AddTags(id string, tags []string) (*models.Task, error)
+	RemoveTags(id string, tags []string) (*models.Task, error)
+	RenameTags(from []string, to string) (int, error)
+	TagCounts() ([]models.TagCount, error)
+
+	// Project operations
+	AddProject(project models.Project) (*models.Project, error)
+	UpdateProject(ref string, patch models.ProjectPatch) (*models.Project, error)
+	ArchiveProject(ref string, archived bool) (*models.Project, error)
+	DeleteProject(ref string) (int, error)
+	GetProjects(includeArchived bool) ([]*models.Project, error)
+	ProjectSummary(ref string) (*models.ProjectSummary, error)
+	ProjectSummaries(includeArchived bool) ([]models.ProjectSummary, error)
+
+	// Data operations
+	PendingReminders() ([]*models.Task, error)
+	AcknowledgeReminder(id string) error
+	SnoozeReminder(id string, until time.Time) error
+	BackupTasks() (string, error)
+	RestoreTasks(filename string) error
+	ListBackups() ([]string, error)
+
+	// Other operations
+	FocusMode(options models.FocusOptions) ([]models.TaskScore, error)
+	SkipFocusTask(id, reason string, until time.Time) (*models.Task, error)
+	SnoozeFocusTask(id string, until time.Time) (*models.Task, error)
+	StartPomodoro(taskID string, customDuration time.Duration, cycles int) (*models.PomodoroStatus, error)
+	PomodoroStatus() (*models.PomodoroStatus, error)
+	PausePomodoro() (*models.PomodoroStatus, error)
+	ResumePomodoro() (*models.PomodoroStatus, error)
+	SkipPomodoroPhase() (*models.PomodoroStatus, error)
+	FinishPomodoroCycle() (*models.PomodoroStatus, error)
+	LogInterruption(spec models.TaskSpec) (*models.Task, error)
+	StopPomodoro(reason string) (*models.PomodoroStatus, error)
+	GetPomodoroHistory(since time.Time) ([]*models.PomodoroRecord, error)
+
+	// Close releases any resources held by the service
+	Close() error
+}
@@ -1,20 +1,36 @@
 package storage
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/query"
+	"github.com/user/todolist/internal/search"
 )
 
-// JSONStorage implements the Storage interface using JSON files
+// JSONStorage implements the Storage interface using JSON files.
+//
+// Writes are atomic (write to a temporary file, fsync, rename) and every
+// read-modify-write cycle holds an advisory lock on a sibling ".lock" file, so
+// several processes can safely share the same tasks file. Changes made by
+// other processes are picked up automatically before each operation.
+//
+// Tasks are copied on the way in and out, so callers never share the cached
+// tasks. The search index is kept in memory and rebuilt whenever the file is
+// reloaded.
 type JSONStorage struct {
 	filePath string
 	tasks    map[string]*models.Task
+	index    *search.Index
+	fileInfo os.FileInfo // state of the file when it was last read or written
 	mu       sync.RWMutex
 }
 
@@ -23,6 +39,7 @@ func NewJSONStorage(filePath string) *JSONStorage {
 	return &JSONStorage{
 		filePath: filePath,
 		tasks:    make(map[string]*models.Task),
+		index:    search.NewIndex(),
 	}
 }
 
@@ -37,47 +54,147 @@ func (s *JSONStorage) Initialize() error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	lock, err := lockFile(s.lockPath())
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	// Check if file exists
 	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
 		// Create empty file
-		s.tasks = make(map[string]*models.Task)
+		s.setTasks(nil)
 		return s.saveToFile()
 	}
 
+	return s.loadFromFile()
+}
+
+// Close releases resources held by the storage
+func (s *JSONStorage) Close() error {
+	return nil
+}
+
+// lockPath returns the path of the lock file guarding the tasks file
+func (s *JSONStorage) lockPath() string {
+	return s.filePath + ".lock"
+}
+
+// loadFromFile reads tasks from the JSON file, replacing the in-memory state
+func (s *JSONStorage) loadFromFile() error {
 	// Read file
-	file, err := os.Open(s.filePath)
+	data, err := os.ReadFile(s.filePath)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	info, err := os.Stat(s.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
 	}
-	defer file.Close()
 
 	// Decode JSON
-	data, err := io.ReadAll(file)
+	var tasks []*models.Task
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return fmt.Errorf("failed to decode JSON: %w", err)
+		}
+	}
+
+	s.setTasks(tasks)
+	s.fileInfo = info
+
+	return nil
+}
+
+// setTasks replaces the in-memory tasks and rebuilds the search index,
+// filling in the status of tasks saved before statuses existed
+func (s *JSONStorage) setTasks(tasks []*models.Task) {
+	s.tasks = make(map[string]*models.Task, len(tasks))
+	s.index = search.NewIndex()
+	for _, task := range tasks {
+		task.MigrateStatus()
+		s.tasks[task.ID] = task
+		s.index.Add(task)
+	}
+}
+
+// changedOnDisk reports whether the file was modified since it was last read or written
+func (s *JSONStorage) changedOnDisk() (bool, error) {
+	info, err := os.Stat(s.filePath)
+	if os.IsNotExist(err) {
+		return s.fileInfo != nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return false, fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	if len(data) == 0 {
-		s.tasks = make(map[string]*models.Task)
+	if s.fileInfo == nil {
+		return true, nil
+	}
+
+	// Atomic writes replace the file, so a different inode means a new version
+	return !os.SameFile(s.fileInfo, info) ||
+		!info.ModTime().Equal(s.fileInfo.ModTime()) ||
+		info.Size() != s.fileInfo.Size(), nil
+}
+
+// reloadIfChanged reloads the tasks if another process changed the file.
+// The caller must hold the write lock.
+func (s *JSONStorage) reloadIfChanged() error {
+	changed, err := s.changedOnDisk()
+	if err != nil || !changed {
+		return err
+	}
+
+	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
+		s.setTasks(nil)
+		s.fileInfo = nil
 		return nil
 	}
 
-	var tasks []*models.Task
-	if err := json.Unmarshal(data, &tasks); err != nil {
-		return fmt.Errorf("failed to decode JSON: %w", err)
+	return s.loadFromFile()
+}
+
+// refresh picks up changes made by other processes before a read
+func (s *JSONStorage) refresh() error {
+	s.mu.RLock()
+	changed, err := s.changedOnDisk()
+	s.mu.RUnlock()
+	if err != nil || !changed {
+		return err
 	}
 
-	// Convert to map
-	s.tasks = make(map[string]*models.Task)
-	for _, task := range tasks {
-		s.tasks[task.ID] = task
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reloadIfChanged()
+}
+
+// modify runs a read-modify-write cycle: it takes the cross-process lock,
+// reloads the file if it changed, applies fn and saves the result
+func (s *JSONStorage) modify(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, err := lockFile(s.lockPath())
+	if err != nil {
+		return err
 	}
+	defer lock.Unlock()
 
-	return nil
+	if err := s.reloadIfChanged(); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	return s.saveToFile()
 }
 
-// saveToFile saves tasks to the JSON file
-func (s *JSONStorage) saveToFile() error {
+// encodeTasks encodes the in-memory tasks as a JSON array
+func (s *JSONStorage) encodeTasks() ([]byte, error) {
 	// Convert map to slice
 	tasks := make([]*models.Task, 0, len(s.tasks))
 	for _, task := range s.tasks {
@@ -87,28 +204,48 @@ func (s *JSONStorage) saveToFile() error {
 	// Encode JSON
 	data, err := json.MarshalIndent(tasks, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to encode JSON: %w", err)
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return data, nil
+}
+
+// saveToFile atomically saves tasks to the JSON file
+func (s *JSONStorage) saveToFile() error {
+	data, err := s.encodeTasks()
+	if err != nil {
+		return err
 	}
 
 	// Write to file
-	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+	if err := WriteFileAtomic(s.filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
+	info, err := os.Stat(s.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	s.fileInfo = info
+
 	return nil
 }
 
 // AddTask adds a new task
 func (s *JSONStorage) AddTask(task *models.Task) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	s.tasks[task.ID] = task
-	return s.saveToFile()
+	task = task.Clone()
+	return s.modify(func() error {
+		s.tasks[task.ID] = task
+		s.index.Add(task)
+		return nil
+	})
 }
 
 // GetTask retrieves a task by ID
 func (s *JSONStorage) GetTask(id string) (*models.Task, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -116,30 +253,67 @@ func (s *JSONStorage) GetTask(id string) (*models.Task, error) {
 	if !ok {
 		return nil, ErrTaskNotFound{ID: id}
 	}
-	return task, nil
+	return task.Clone(), nil
 }
 
 // GetAllTasks retrieves all tasks
 func (s *JSONStorage) GetAllTasks() ([]*models.Task, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	tasks := make([]*models.Task, 0, len(s.tasks))
 	for _, task := range s.tasks {
-		tasks = append(tasks, task)
+		tasks = append(tasks, task.Clone())
 	}
 	return tasks, nil
 }
 
+// QueryTasks returns the page of tasks selected by a query
+func (s *JSONStorage) QueryTasks(q query.Query) (*models.TaskPage, error) {
+	tasks, err := s.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	return q.Run(tasks), nil
+}
+
+// SearchTasks runs a full-text search against the in-memory index
+func (s *JSONStorage) SearchTasks(q models.SearchQuery) ([]*models.SearchResult, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return search.Run(s.index, q, func(ids []string) ([]*models.Task, error) {
+		tasks := make([]*models.Task, 0, len(ids))
+		for _, id := range ids {
+			if task, ok := s.tasks[id]; ok {
+				tasks = append(tasks, task.Clone())
+			}
+		}
+		return tasks, nil
+	})
+}
+
 // GetTasksByCategory retrieves tasks by category
 func (s *JSONStorage) GetTasksByCategory(category models.Category) ([]*models.Task, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var tasks []*models.Task
 	for _, task := range s.tasks {
 		if task.Category == category {
-			tasks = append(tasks, task)
+			tasks = append(tasks, task.Clone())
 		}
 	}
 	return tasks, nil
@@ -147,13 +321,17 @@ func (s *JSONStorage) GetTasksByCategory(category models.Category) ([]*models.Ta
 
 // GetTasksByPriority retrieves tasks by priority
 func (s *JSONStorage) GetTasksByPriority(priority models.Priority) ([]*models.Task, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var tasks []*models.Task
 	for _, task := range s.tasks {
 		if task.Priority == priority {
-			tasks = append(tasks, task)
+			tasks = append(tasks, task.Clone())
 		}
 	}
 	return tasks, nil
@@ -161,49 +339,72 @@ func (s *JSONStorage) GetTasksByPriority(priority models.Priority) ([]*models.Ta
 
 // UpdateTask updates an existing task
 func (s *JSONStorage) UpdateTask(task *models.Task) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	task = task.Clone()
+	return s.modify(func() error {
+		if _, ok := s.tasks[task.ID]; !ok {
+			return ErrTaskNotFound{ID: task.ID}
+		}
 
-	if _, ok := s.tasks[task.ID]; !ok {
-		return ErrTaskNotFound{ID: task.ID}
-	}
+		s.tasks[task.ID] = task
+		s.index.Add(task)
+		return nil
+	})
+}
 
-	s.tasks[task.ID] = task
-	return s.saveToFile()
+// ModifyTask applies fn to a copy of a task and saves the result, holding the
+// cross-process lock from reading the task to writing it back. Nothing is
+// saved if fn fails.
+func (s *JSONStorage) ModifyTask(id string, fn func(task *models.Task) error) (*models.Task, error) {
+	var updated *models.Task
+	err := s.modify(func() error {
+		task, ok := s.tasks[id]
+		if !ok {
+			return ErrTaskNotFound{ID: id}
+		}
+
+		updated = task.Clone()
+		if err := fn(updated); err != nil {
+			return err
+		}
+		s.tasks[id] = updated
+		s.index.Add(updated)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated.Clone(), nil
 }
 
 // DeleteTask deletes a task by ID
 func (s *JSONStorage) DeleteTask(id string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if _, ok := s.tasks[id]; !ok {
-		return ErrTaskNotFound{ID: id}
-	}
+	return s.modify(func() error {
+		if _, ok := s.tasks[id]; !ok {
+			return ErrTaskNotFound{ID: id}
+		}
 
-	delete(s.tasks, id)
-	return s.saveToFile()
+		delete(s.tasks, id)
+		s.index.Remove(id)
+		return nil
+	})
 }
 
 // Backup creates a backup of the tasks
 func (s *JSONStorage) Backup(filename string) error {
+	if err := s.refresh(); err != nil {
+		return err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Convert map to slice
-	tasks := make([]*models.Task, 0, len(s.tasks))
-	for _, task := range s.tasks {
-		tasks = append(tasks, task)
-	}
-
-	// Encode JSON
-	data, err := json.MarshalIndent(tasks, "", "  ")
+	data, err := s.encodeTasks()
 	if err != nil {
-		return fmt.Errorf("failed to encode JSON: %w", err)
+		return err
 	}
 
 	// Write to backup file
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := WriteFileAtomic(filename, data, 0644); err != nil {
 		return fmt.Errorf("failed to write backup file: %w", err)
 	}
 
@@ -212,9 +413,6 @@ func (s *JSONStorage) Backup(filename string) error {
 
 // Restore restores tasks from a backup
 func (s *JSONStorage) Restore(filename string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	// Read backup file
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -226,12 +424,197 @@ func (s *JSONStorage) Restore(filename string) error {
 		return fmt.Errorf("failed to decode JSON: %w", err)
 	}
 
-	// Convert to map
-	s.tasks = make(map[string]*models.Task)
-	for _, task := range tasks {
-		s.tasks[task.ID] = task
+	return s.modify(func() error {
+		s.setTasks(tasks)
+		return nil
+	})
+}
+
+// historyPath returns the path of the Pomodoro history file, which is kept
+// next to the tasks file as one JSON record per line
+func (s *JSONStorage) historyPath() string {
+	return filepath.Join(filepath.Dir(s.filePath), "pomodoros.jsonl")
+}
+
+// AddPomodoroRecord appends a work interval to the Pomodoro history
+func (s *JSONStorage) AddPomodoroRecord(record *models.PomodoroRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
 
-	// Save to file
-	return s.saveToFile()
+	lock, err := lockFile(s.lockPath())
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	file, err := os.OpenFile(s.historyPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync history file: %w", err)
+	}
+	return nil
+}
+
+// GetPomodoroRecords returns the work intervals that ended at or after since,
+// oldest first
+func (s *JSONStorage) GetPomodoroRecords(since time.Time) ([]*models.PomodoroRecord, error) {
+	file, err := os.Open(s.historyPath())
+	if os.IsNotExist(err) {
+		return []*models.PomodoroRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	records := make([]*models.PomodoroRecord, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		// A crash while appending can leave a partial last line; skip it
+		var record models.PomodoroRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			continue
+		}
+		if record.End.Before(since) {
+			continue
+		}
+		records = append(records, &record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Start.Before(records[j].Start)
+	})
+	return records, nil
+}
+
+// projectsPath returns the path of the projects file, which is kept next to
+// the tasks file
+func (s *JSONStorage) projectsPath() string {
+	return filepath.Join(filepath.Dir(s.filePath), "projects.json")
+}
+
+// loadProjects reads the projects file. A missing file means no projects.
+func (s *JSONStorage) loadProjects() ([]*models.Project, error) {
+	data, err := os.ReadFile(s.projectsPath())
+	if os.IsNotExist(err) {
+		return []*models.Project{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read projects file: %w", err)
+	}
+
+	projects := make([]*models.Project, 0)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return projects, nil
+	}
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, fmt.Errorf("failed to decode projects file: %w", err)
+	}
+	return projects, nil
+}
+
+// modifyProjects runs a read-modify-write cycle on the projects file under
+// the cross-process lock
+func (s *JSONStorage) modifyProjects(fn func(projects []*models.Project) ([]*models.Project, error)) error {
+	lock, err := lockFile(s.lockPath())
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	projects, err := s.loadProjects()
+	if err != nil {
+		return err
+	}
+
+	projects, err = fn(projects)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	if err := WriteFileAtomic(s.projectsPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write projects file: %w", err)
+	}
+	return nil
+}
+
+// AddProject adds a new project
+func (s *JSONStorage) AddProject(project *models.Project) error {
+	return s.modifyProjects(func(projects []*models.Project) ([]*models.Project, error) {
+		return append(projects, project), nil
+	})
+}
+
+// GetProject retrieves a project by ID
+func (s *JSONStorage) GetProject(id string) (*models.Project, error) {
+	projects, err := s.loadProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		if project.ID == id {
+			return project, nil
+		}
+	}
+	return nil, ErrProjectNotFound{ID: id}
+}
+
+// GetAllProjects retrieves all projects, oldest first
+func (s *JSONStorage) GetAllProjects() ([]*models.Project, error) {
+	projects, err := s.loadProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].CreatedAt.Before(projects[j].CreatedAt)
+	})
+	return projects, nil
+}
+
+// UpdateProject updates an existing project
+func (s *JSONStorage) UpdateProject(project *models.Project) error {
+	return s.modifyProjects(func(projects []*models.Project) ([]*models.Project, error) {
+		for i, existing := range projects {
+			if existing.ID == project.ID {
+				projects[i] = project
+				return projects, nil
+			}
+		}
+		return nil, ErrProjectNotFound{ID: project.ID}
+	})
+}
+
+// DeleteProject deletes a project by ID
+func (s *JSONStorage) DeleteProject(id string) error {
+	return s.modifyProjects(func(projects []*models.Project) ([]*models.Project, error) {
+		for i, existing := range projects {
+			if existing.ID == id {
+				return append(projects[:i], projects[i+1:]...), nil
+			}
+		}
+		return nil, ErrProjectNotFound{ID: id}
+	})
 }
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/user/todolist/internal/models"
+)
+
+func newJSONStorage(t *testing.T, path string) *JSONStorage {
+	t.Helper()
+	store := NewJSONStorage(path)
+	if err := store.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestJSONStorageReturnsCopies(t *testing.T) {
+	store := newJSONStorage(t, filepath.Join(t.TempDir(), "tasks.json"))
+
+	task := &models.Task{ID: "1", Title: "Original", Tags: []string{"a"}}
+	if err := store.AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+	task.Title = "Changed after adding"
+
+	got, err := store.GetTask("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Title = "Changed after getting"
+	got.Tags[0] = "b"
+
+	all, err := store.GetAllTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all[0].Title != "Original" || all[0].Tags[0] != "a" {
+		t.Errorf("stored task is %q with tags %v, want it unchanged", all[0].Title, all[0].Tags)
+	}
+}
+
+func TestJSONStorageModifyTask(t *testing.T) {
+	store := newJSONStorage(t, filepath.Join(t.TempDir(), "tasks.json"))
+	if err := store.AddTask(&models.Task{ID: "1", Title: "Task"}); err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("rejected")
+	_, err := store.ModifyTask("1", func(task *models.Task) error {
+		task.Title = "Half done"
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("got error %v, want %v", err, failure)
+	}
+	if task, _ := store.GetTask("1"); task.Title != "Task" {
+		t.Errorf("failed modification saved title %q", task.Title)
+	}
+
+	if _, err := store.ModifyTask("missing", func(*models.Task) error { return nil }); !errors.As(err, new(ErrTaskNotFound)) {
+		t.Errorf("got error %v for a missing task, want ErrTaskNotFound", err)
+	}
+}
+
+// TestJSONStorageConcurrentModify updates one task through two storages on
+// the same file, as two processes would, and checks no update is lost
+func TestJSONStorageConcurrentModify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	first := newJSONStorage(t, path)
+	second := newJSONStorage(t, path)
+	if err := first.AddTask(&models.Task{ID: "1", Title: "Counter"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const updates = 20
+	var wg sync.WaitGroup
+	for _, store := range []*JSONStorage{first, second} {
+		wg.Add(1)
+		go func(store *JSONStorage) {
+			defer wg.Done()
+			for i := 0; i < updates; i++ {
+				_, err := store.ModifyTask("1", func(task *models.Task) error {
+					task.Tags = append(task.Tags, fmt.Sprintf("t%d", len(task.Tags)))
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(store)
+	}
+	wg.Wait()
+
+	task, err := first.GetTask("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(task.Tags) != 2*updates {
+		t.Errorf("got %d updates, want %d", len(task.Tags), 2*updates)
+	}
+}
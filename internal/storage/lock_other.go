@@ -0,0 +1,17 @@
+//go:build !unix
+
+package storage
+
+// fileLock is a no-op on platforms without flock support. Writes are still
+// atomic, but concurrent processes are not serialized.
+type fileLock struct{}
+
+// lockFile returns a no-op lock
+func lockFile(path string) (*fileLock, error) {
+	return &fileLock{}, nil
+}
+
+// Unlock releases the lock
+func (l *fileLock) Unlock() error {
+	return nil
+}
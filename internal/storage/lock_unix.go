@@ -0,0 +1,35 @@
+//go:build unix
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// fileLock is an advisory lock shared between processes
+type fileLock struct {
+	file *os.File
+}
+
+// lockFile blocks until an exclusive advisory lock on the given path is acquired
+func lockFile(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	return &fileLock{file: file}, nil
+}
+
+// Unlock releases the lock
+func (l *fileLock) Unlock() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+)
+
+// ImportJSON copies all tasks from a JSON tasks file, and the projects and
+// Pomodoro history kept next to it, into the given storage. Tasks and projects
+// that already exist in the destination are overwritten. Tasks from before the
+// status lifecycle are migrated the same way as when JSONStorage loads them.
+// It returns the number of imported tasks, or zero if the file does not exist.
+func ImportJSON(dst Storage, jsonPath string) (int, error) {
+	data, err := os.ReadFile(jsonPath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	var tasks []*models.Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return 0, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	for _, task := range tasks {
+		task.MigrateStatus()
+		err := dst.UpdateTask(task)
+		if _, ok := err.(ErrTaskNotFound); ok {
+			err = dst.AddTask(task)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to import task %s: %w", task.ID, err)
+		}
+	}
+
+	source := NewJSONStorage(jsonPath)
+
+	projects, err := source.GetAllProjects()
+	if err != nil {
+		return 0, err
+	}
+	for _, project := range projects {
+		err := dst.UpdateProject(project)
+		if _, ok := err.(ErrProjectNotFound); ok {
+			err = dst.AddProject(project)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to import project %s: %w", project.ID, err)
+		}
+	}
+
+	records, err := source.GetPomodoroRecords(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	for _, record := range records {
+		if err := dst.AddPomodoroRecord(record); err != nil {
+			return 0, fmt.Errorf("failed to import pomodoro record %s: %w", record.ID, err)
+		}
+	}
+
+	return len(tasks), nil
+}
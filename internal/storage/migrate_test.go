@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/user/todolist/internal/models"
+)
+
+func TestImportJSONMigratesStatus(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "tasks.json")
+	legacy := `[
+		{"id": "1", "title": "Open task", "priority": "medium", "category": "inbox", "completed": false},
+		{"id": "2", "title": "Done task", "priority": "medium", "category": "inbox", "completed": true}
+	]`
+	if err := os.WriteFile(jsonPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewSQLiteStorage(filepath.Join(dir, "tasks.db"))
+	if err := store.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	count, err := ImportJSON(store, jsonPath)
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("imported %d tasks, want 2", count)
+	}
+
+	tests := []struct {
+		id        string
+		status    models.Status
+		completed bool
+	}{
+		{"1", models.StatusTodo, false},
+		{"2", models.StatusDone, true},
+	}
+	for _, tt := range tests {
+		// Check the stored row, since reads migrate tasks again
+		var data string
+		if err := store.db.QueryRow(`SELECT data FROM tasks WHERE id = ?`, tt.id).Scan(&data); err != nil {
+			t.Fatalf("failed to read task %s: %v", tt.id, err)
+		}
+		var task models.Task
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			t.Fatal(err)
+		}
+		if task.Status != tt.status || task.Completed != tt.completed {
+			t.Errorf("task %s is %q (completed %v), want %q (completed %v)",
+				tt.id, task.Status, task.Completed, tt.status, tt.completed)
+		}
+	}
+}
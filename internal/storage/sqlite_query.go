@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/query"
+)
+
+// QueryTasks returns the page of tasks selected by a query. Terms on indexed
+// columns are evaluated by SQLite; the full expression, sorting and paging are
+// then applied to the rows it returns.
+func (s *SQLiteStorage) QueryTasks(q query.Query) (*models.TaskPage, error) {
+	stmt := `SELECT data FROM tasks`
+	where, args, _ := sqlFilter(q.Where)
+	if where != "" {
+		stmt += ` WHERE ` + where
+	}
+
+	tasks, err := s.queryTasks(stmt+` ORDER BY created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	return q.Run(tasks), nil
+}
+
+// sqlFilter translates the parts of a query expression that map onto indexed
+// columns into a WHERE clause. An empty clause selects every row. The clause
+// may select more tasks than the expression, never fewer; exact reports
+// whether it selects exactly the matching tasks, which a NOT needs.
+func sqlFilter(expr query.Expr) (clause string, args []interface{}, exact bool) {
+	switch e := expr.(type) {
+	case nil:
+		return "", nil, true
+
+	case *query.And:
+		var clauses []string
+		exact = true
+		for _, child := range e.Exprs {
+			childClause, childArgs, childExact := sqlFilter(child)
+			exact = exact && childExact
+			if childClause != "" {
+				clauses = append(clauses, childClause)
+				args = append(args, childArgs...)
+			}
+		}
+		return joinClauses(clauses, " AND "), args, exact
+
+	case *query.Or:
+		var clauses []string
+		exact = true
+		for _, child := range e.Exprs {
+			childClause, childArgs, childExact := sqlFilter(child)
+			if childClause == "" {
+				// One unrestricted branch makes the whole OR unrestricted
+				return "", nil, false
+			}
+			exact = exact && childExact
+			clauses = append(clauses, childClause)
+			args = append(args, childArgs...)
+		}
+		return joinClauses(clauses, " OR "), args, exact
+
+	case *query.Not:
+		childClause, childArgs, childExact := sqlFilter(e.Expr)
+		if childClause == "" || !childExact {
+			return "", nil, false
+		}
+		return "NOT " + childClause, childArgs, true
+
+	case *query.Term:
+		return sqlTerm(e)
+	}
+	return "", nil, false
+}
+
+// sqlTerm translates a single term, or returns an empty clause if the field
+// has no column
+func sqlTerm(term *query.Term) (string, []interface{}, bool) {
+	switch term.Field {
+	case query.FieldPriority:
+		priorities := term.Priorities()
+		if len(priorities) == 0 {
+			return "(0)", nil, true
+		}
+		args := make([]interface{}, len(priorities))
+		for i, priority := range priorities {
+			args[i] = string(priority)
+		}
+		return "(priority IN (" + placeholders(len(args)) + "))", args, true
+
+	case query.FieldCategory:
+		// NOCASE only folds ASCII letters
+		if strings.IndexFunc(term.Value, func(r rune) bool { return r > unicode.MaxASCII }) >= 0 {
+			return "", nil, false
+		}
+		return "(category = ? COLLATE NOCASE)", []interface{}{term.Value}, true
+
+	case query.FieldStatus:
+		switch term.Value {
+		case "open":
+			return "(completed = 0)", nil, true
+		case "closed":
+			return "(completed = 1)", nil, true
+		}
+		// The completed flag narrows down the status
+		return "(completed = ?)", []interface{}{models.Status(term.Value).IsClosed()}, false
+
+	case query.FieldDue:
+		return sqlTimeRange("due_date", term)
+
+	case query.FieldCreated:
+		return sqlTimeRange("created_at", term)
+
+	case query.FieldIs:
+		if term.Value == "subtask" {
+			return "(parent_id IS NOT NULL)", nil, true
+		}
+	}
+	return "", nil, false
+}
+
+// sqlTimeRange translates a date term on a column holding Unix seconds, which
+// is NULL for unset dates. The column drops fractions of a second, so the
+// range is widened to whole seconds and is not exact.
+func sqlTimeRange(column string, term *query.Term) (string, []interface{}, bool) {
+	if term.IsNone() {
+		return "(" + column + " IS NULL)", nil, true
+	}
+
+	clauses := []string{column + " IS NOT NULL"}
+	var args []interface{}
+	if !term.From.IsZero() {
+		clauses = append(clauses, column+" >= ?")
+		args = append(args, term.From.Unix())
+	}
+	if !term.To.IsZero() {
+		clauses = append(clauses, column+" <= ?")
+		args = append(args, term.To.Unix())
+	}
+	return joinClauses(clauses, " AND "), args, false
+}
+
+// joinClauses joins SQL conditions, wrapping the result in parentheses
+func joinClauses(clauses []string, sep string) string {
+	if len(clauses) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(clauses, sep) + ")"
+}
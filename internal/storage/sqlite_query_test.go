@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/query"
+)
+
+// TestQueryTasksSubsecondDates checks date ranges whose bounds fall inside
+// the second a task is due, which the stored Unix seconds cannot tell apart
+func TestQueryTasksSubsecondDates(t *testing.T) {
+	store := NewSQLiteStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	if err := store.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	second := time.Date(2025, time.March, 12, 10, 0, 0, 0, time.UTC)
+	due := second.Add(500 * time.Millisecond)
+	task := &models.Task{ID: "1", Title: "Call", Priority: models.PriorityMedium, Category: "inbox", Status: models.StatusTodo, DueDate: due, CreatedAt: second}
+	if err := store.AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	before := &query.Term{Field: query.FieldDue, Value: "range", To: second.Add(700 * time.Millisecond)}
+	after := &query.Term{Field: query.FieldDue, Value: "range", From: second.Add(700 * time.Millisecond)}
+	tests := []struct {
+		name  string
+		where query.Expr
+		want  int
+	}{
+		{"due before a later time in the same second", before, 1},
+		{"due after a later time in the same second", after, 0},
+		{"not due after a later time in the same second", &query.Not{Expr: after}, 1},
+		{"not due before a later time in the same second", &query.Not{Expr: before}, 0},
+	}
+	for _, tt := range tests {
+		page, err := store.QueryTasks(query.Query{Where: tt.where})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(page.Tasks) != tt.want {
+			t.Errorf("%s: got %d tasks, want %d", tt.name, len(page.Tasks), tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/search"
+)
+
+// indexTask replaces the search terms of a task
+func indexTask(db taskExecer, task *models.Task) error {
+	if _, err := db.Exec(`DELETE FROM search_terms WHERE task_id = ?`, task.ID); err != nil {
+		return fmt.Errorf("failed to delete search terms: %w", err)
+	}
+
+	for term, postings := range search.TaskPostings(task) {
+		for _, posting := range postings {
+			_, err := db.Exec(`INSERT INTO search_terms (term, task_id, field, count) VALUES (?, ?, ?, ?)`,
+				term, posting.TaskID, posting.Field, posting.Count)
+			if err != nil {
+				return fmt.Errorf("failed to write search terms: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// searchIndexVersion is the schema version, kept in SQLite's user_version,
+// from which every task is in the search index
+const searchIndexVersion = 1
+
+// migrateSearchIndex adds the tasks of databases created before the search
+// index existed to it. It runs once per database; afterwards the schema
+// version says the index is complete.
+func (s *SQLiteStorage) migrateSearchIndex() error {
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version >= searchIndexVersion {
+		return nil
+	}
+
+	tasks, err := s.queryTasks(`SELECT data FROM tasks`)
+	if err != nil {
+		return err
+	}
+
+	return s.inTx(func(tx *sql.Tx) error {
+		for _, task := range tasks {
+			if err := indexTask(tx, task); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, searchIndexVersion)); err != nil {
+			return fmt.Errorf("failed to write schema version: %w", err)
+		}
+		return nil
+	})
+}
+
+// SearchTasks runs a full-text search against the search_terms table
+func (s *SQLiteStorage) SearchTasks(q models.SearchQuery) ([]*models.SearchResult, error) {
+	return search.Run(sqliteIndex{s}, q, func(ids []string) ([]*models.Task, error) {
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		return s.queryTasks(`SELECT data FROM tasks WHERE id IN (`+placeholders(len(ids))+`)`, stringArgs(ids)...)
+	})
+}
+
+// sqliteIndex reads the search index of a SQLite database
+type sqliteIndex struct {
+	s *SQLiteStorage
+}
+
+// DocumentCount returns the number of tasks
+func (idx sqliteIndex) DocumentCount() (int, error) {
+	var count int
+	if err := idx.s.db.QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return count, nil
+}
+
+// Terms returns the indexed terms starting with prefix
+func (idx sqliteIndex) Terms(prefix string) ([]string, error) {
+	stmt := `SELECT DISTINCT term FROM search_terms`
+	var args []interface{}
+	if prefix != "" {
+		// A range instead of LIKE, so the index on term is used and "%" or
+		// "_" in the prefix have no special meaning
+		stmt += ` WHERE term >= ? AND term < ?`
+		args = append(args, prefix, prefix+"\U0010FFFF")
+	}
+
+	rows, err := idx.s.db.Query(stmt+` ORDER BY term`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search terms: %w", err)
+	}
+	defer rows.Close()
+
+	var terms []string
+	for rows.Next() {
+		var term string
+		if err := rows.Scan(&term); err != nil {
+			return nil, fmt.Errorf("failed to read search term: %w", err)
+		}
+		terms = append(terms, term)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search terms: %w", err)
+	}
+	return terms, nil
+}
+
+// Postings returns the postings of each of the given terms
+func (idx sqliteIndex) Postings(terms []string) (map[string][]search.Posting, error) {
+	result := make(map[string][]search.Posting, len(terms))
+	if len(terms) == 0 {
+		return result, nil
+	}
+
+	rows, err := idx.s.db.Query(`SELECT term, task_id, field, count FROM search_terms WHERE term IN (`+placeholders(len(terms))+`)`,
+		stringArgs(terms)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search terms: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var term string
+		var posting search.Posting
+		if err := rows.Scan(&term, &posting.TaskID, &posting.Field, &posting.Count); err != nil {
+			return nil, fmt.Errorf("failed to read search term: %w", err)
+		}
+		result[term] = append(result[term], posting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search terms: %w", err)
+	}
+	return result, nil
+}
+
+// placeholders returns n comma-separated query parameters
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// stringArgs converts strings to query arguments
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return args
+}
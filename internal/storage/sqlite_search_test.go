@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateSearchIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	// A database from before the search index, holding one task
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE tasks (
+		id TEXT PRIMARY KEY, category TEXT NOT NULL, priority TEXT NOT NULL, due_date INTEGER,
+		completed INTEGER NOT NULL DEFAULT 0, parent_id TEXT, created_at INTEGER NOT NULL, data TEXT NOT NULL);
+		INSERT INTO tasks (id, category, priority, created_at, data)
+		VALUES ('1', 'inbox', 'medium', 0, '{"id": "1", "title": "Renew passport", "priority": "medium", "category": "inbox", "status": "todo"}')`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() *SQLiteStorage {
+		t.Helper()
+		store := NewSQLiteStorage(path)
+		if err := store.Initialize(); err != nil {
+			t.Fatal(err)
+		}
+		return store
+	}
+	terms := func(store *SQLiteStorage) int {
+		t.Helper()
+		var count int
+		if err := store.db.QueryRow(`SELECT COUNT(*) FROM search_terms`).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	store := open()
+	if terms(store) == 0 {
+		t.Fatal("existing task was not indexed")
+	}
+	// Clear the index behind the storage's back; the next start must not
+	// scan the tasks again
+	if _, err := store.db.Exec(`DELETE FROM search_terms`); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store = open()
+	defer store.Close()
+	if count := terms(store); count != 0 {
+		t.Errorf("tasks were indexed again on the second start (%d terms)", count)
+	}
+}
@@ -0,0 +1,518 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/user/todolist/internal/models"
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+// sqliteSchema creates the tasks, projects and Pomodoro history tables. The full task or
+// record is stored as JSON in the data column, while the columns used for
+// filtering are duplicated so they can be indexed. search_terms holds the
+// inverted index used by SearchTasks.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tasks (
+	id         TEXT PRIMARY KEY,
+	category   TEXT NOT NULL,
+	priority   TEXT NOT NULL,
+	due_date   INTEGER,
+	completed  INTEGER NOT NULL DEFAULT 0,
+	parent_id  TEXT,
+	created_at INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_tasks_category ON tasks(category);
+CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks(due_date);
+CREATE INDEX IF NOT EXISTS idx_tasks_completed ON tasks(completed);
+CREATE TABLE IF NOT EXISTS pomodoros (
+	id       TEXT PRIMARY KEY,
+	task_id  TEXT NOT NULL,
+	start    INTEGER NOT NULL,
+	end_time INTEGER NOT NULL,
+	data     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_pomodoros_end_time ON pomodoros(end_time);
+CREATE TABLE IF NOT EXISTS projects (
+	id         TEXT PRIMARY KEY,
+	created_at INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS search_terms (
+	term    TEXT NOT NULL,
+	task_id TEXT NOT NULL,
+	field   TEXT NOT NULL,
+	count   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_search_terms_term ON search_terms(term);
+CREATE INDEX IF NOT EXISTS idx_search_terms_task_id ON search_terms(task_id);
+`
+
+// SQLiteStorage implements the Storage interface using a SQLite database
+type SQLiteStorage struct {
+	filePath string
+	db       *sql.DB
+}
+
+// NewSQLiteStorage creates a new SQLite storage with the given database path
+func NewSQLiteStorage(filePath string) *SQLiteStorage {
+	return &SQLiteStorage{
+		filePath: filePath,
+	}
+}
+
+// Initialize opens the database and creates the schema if needed
+func (s *SQLiteStorage) Initialize() error {
+	// Create directory if it doesn't exist
+	dir := filepath.Dir(s.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", s.filePath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite only supports a single writer, so serialize access through one connection
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	s.db = db
+	if err := s.migrateSearchIndex(); err != nil {
+		db.Close()
+		s.db = nil
+		return err
+	}
+	return nil
+}
+
+// Close closes the database
+func (s *SQLiteStorage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// taskExecer is implemented by both *sql.DB and *sql.Tx
+type taskExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertTask inserts or replaces a task row together with its search terms
+func insertTask(db taskExecer, task *models.Task, replace bool) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	var dueDate interface{}
+	if !task.DueDate.IsZero() {
+		dueDate = task.DueDate.Unix()
+	}
+
+	var parentID interface{}
+	if task.ParentID != "" {
+		parentID = task.ParentID
+	}
+
+	verb := "INSERT"
+	if replace {
+		verb = "INSERT OR REPLACE"
+	}
+
+	_, err = db.Exec(verb+` INTO tasks (id, category, priority, due_date, completed, parent_id, created_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		task.ID, string(task.Category), string(task.Priority), dueDate, task.Completed, parentID, task.CreatedAt.Unix(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+
+	return indexTask(db, task)
+}
+
+// queryTasks runs a query selecting the data column and decodes the tasks
+func (s *SQLiteStorage) queryTasks(query string, args ...interface{}) ([]*models.Task, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]*models.Task, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read task: %w", err)
+		}
+
+		var task models.Task
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		task.MigrateStatus()
+		tasks = append(tasks, &task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// AddTask adds a new task
+func (s *SQLiteStorage) AddTask(task *models.Task) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		return insertTask(tx, task, false)
+	})
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds
+func (s *SQLiteStorage) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetTask retrieves a task by ID
+func (s *SQLiteStorage) GetTask(id string) (*models.Task, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM tasks WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTaskNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task: %w", err)
+	}
+
+	var task models.Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	task.MigrateStatus()
+	return &task, nil
+}
+
+// GetAllTasks retrieves all tasks
+func (s *SQLiteStorage) GetAllTasks() ([]*models.Task, error) {
+	return s.queryTasks(`SELECT data FROM tasks ORDER BY created_at`)
+}
+
+// GetTasksByCategory retrieves tasks by category
+func (s *SQLiteStorage) GetTasksByCategory(category models.Category) ([]*models.Task, error) {
+	return s.queryTasks(`SELECT data FROM tasks WHERE category = ? ORDER BY created_at`, string(category))
+}
+
+// GetTasksByPriority retrieves tasks by priority
+func (s *SQLiteStorage) GetTasksByPriority(priority models.Priority) ([]*models.Task, error) {
+	return s.queryTasks(`SELECT data FROM tasks WHERE priority = ? ORDER BY created_at`, string(priority))
+}
+
+// UpdateTask updates an existing task
+func (s *SQLiteStorage) UpdateTask(task *models.Task) error {
+	var exists int
+	err := s.db.QueryRow(`SELECT 1 FROM tasks WHERE id = ?`, task.ID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound{ID: task.ID}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query task: %w", err)
+	}
+
+	return s.inTx(func(tx *sql.Tx) error {
+		return insertTask(tx, task, true)
+	})
+}
+
+// ModifyTask applies fn to a task and saves the result in one transaction.
+// Nothing is saved if fn fails.
+func (s *SQLiteStorage) ModifyTask(id string, fn func(task *models.Task) error) (*models.Task, error) {
+	var task models.Task
+	err := s.inTx(func(tx *sql.Tx) error {
+		// Write first so the transaction holds the write lock before reading,
+		// keeping other processes from changing the task in between
+		result, err := tx.Exec(`UPDATE tasks SET id = id WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("failed to lock task: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to lock task: %w", err)
+		}
+		if affected == 0 {
+			return ErrTaskNotFound{ID: id}
+		}
+
+		var data string
+		if err := tx.QueryRow(`SELECT data FROM tasks WHERE id = ?`, id).Scan(&data); err != nil {
+			return fmt.Errorf("failed to query task: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			return fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		task.MigrateStatus()
+
+		if err := fn(&task); err != nil {
+			return err
+		}
+		return insertTask(tx, &task, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask deletes a task by ID
+func (s *SQLiteStorage) DeleteTask(id string) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+		if affected == 0 {
+			return ErrTaskNotFound{ID: id}
+		}
+
+		if _, err := tx.Exec(`DELETE FROM search_terms WHERE task_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete search terms: %w", err)
+		}
+		return nil
+	})
+}
+
+// Backup creates a JSON backup of the tasks, compatible with JSONStorage backups
+func (s *SQLiteStorage) Backup(filename string) error {
+	tasks, err := s.GetAllTasks()
+	if err != nil {
+		return err
+	}
+
+	// Encode JSON
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	// Write to backup file
+	if err := WriteFileAtomic(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	return nil
+}
+
+// Restore replaces all tasks with the ones in a JSON backup
+func (s *SQLiteStorage) Restore(filename string) error {
+	// Read backup file
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read backup file: %w", err)
+	}
+
+	var tasks []*models.Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM tasks`); err != nil {
+		return fmt.Errorf("failed to clear tasks: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM search_terms`); err != nil {
+		return fmt.Errorf("failed to clear search terms: %w", err)
+	}
+
+	for _, task := range tasks {
+		if err := insertTask(tx, task, true); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	return nil
+}
+
+// AddPomodoroRecord stores a work interval in the Pomodoro history
+func (s *SQLiteStorage) AddPomodoroRecord(record *models.PomodoroRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO pomodoros (id, task_id, start, end_time, data) VALUES (?, ?, ?, ?, ?)`,
+		record.ID, record.TaskID, record.Start.UnixNano(), record.End.UnixNano(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write pomodoro record: %w", err)
+	}
+	return nil
+}
+
+// GetPomodoroRecords returns the work intervals that ended at or after since,
+// oldest first
+func (s *SQLiteStorage) GetPomodoroRecords(since time.Time) ([]*models.PomodoroRecord, error) {
+	rows, err := s.db.Query(`SELECT data FROM pomodoros WHERE end_time >= ? ORDER BY start`, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pomodoro records: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*models.PomodoroRecord, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read pomodoro record: %w", err)
+		}
+
+		var record models.PomodoroRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pomodoro records: %w", err)
+	}
+	return records, nil
+}
+
+// writeProject inserts or replaces a project row
+func (s *SQLiteStorage) writeProject(project *models.Project, replace bool) error {
+	data, err := json.Marshal(project)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	verb := "INSERT"
+	if replace {
+		verb = "INSERT OR REPLACE"
+	}
+
+	_, err = s.db.Exec(verb+` INTO projects (id, created_at, data) VALUES (?, ?, ?)`,
+		project.ID, project.CreatedAt.UnixNano(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write project: %w", err)
+	}
+	return nil
+}
+
+// AddProject adds a new project
+func (s *SQLiteStorage) AddProject(project *models.Project) error {
+	return s.writeProject(project, false)
+}
+
+// GetProject retrieves a project by ID
+func (s *SQLiteStorage) GetProject(id string) (*models.Project, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM projects WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProjectNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project: %w", err)
+	}
+
+	var project models.Project
+	if err := json.Unmarshal([]byte(data), &project); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return &project, nil
+}
+
+// GetAllProjects retrieves all projects, oldest first
+func (s *SQLiteStorage) GetAllProjects() ([]*models.Project, error) {
+	rows, err := s.db.Query(`SELECT data FROM projects ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := make([]*models.Project, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read project: %w", err)
+		}
+
+		var project models.Project
+		if err := json.Unmarshal([]byte(data), &project); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		projects = append(projects, &project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read projects: %w", err)
+	}
+	return projects, nil
+}
+
+// UpdateProject updates an existing project
+func (s *SQLiteStorage) UpdateProject(project *models.Project) error {
+	var exists int
+	err := s.db.QueryRow(`SELECT 1 FROM projects WHERE id = ?`, project.ID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrProjectNotFound{ID: project.ID}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query project: %w", err)
+	}
+
+	return s.writeProject(project, true)
+}
+
+// DeleteProject deletes a project by ID
+func (s *SQLiteStorage) DeleteProject(id string) error {
+	result, err := s.db.Exec(`DELETE FROM projects WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	if affected == 0 {
+		return ErrProjectNotFound{ID: id}
+	}
+
+	return nil
+}
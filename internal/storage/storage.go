@@ -1,7 +1,10 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/user/todolist/internal/models"
+	"github.com/user/todolist/internal/query"
 )
 
 // Storage defines the interface for task persistence
@@ -13,7 +16,21 @@ type Storage interface {
 	GetTasksByCategory(category models.Category) ([]*models.Task, error)
 	GetTasksByPriority(priority models.Priority) ([]*models.Task, error)
 	UpdateTask(task *models.Task) error
+	ModifyTask(id string, fn func(task *models.Task) error) (*models.Task, error)
 	DeleteTask(id string) error
+	QueryTasks(q query.Query) (*models.TaskPage, error)
+	SearchTasks(q models.SearchQuery) ([]*models.SearchResult, error)
+
+	// Project operations
+	AddProject(project *models.Project) error
+	GetProject(id string) (*models.Project, error)
+	GetAllProjects() ([]*models.Project, error)
+	UpdateProject(project *models.Project) error
+	DeleteProject(id string) error
+
+	// Pomodoro history
+	AddPomodoroRecord(record *models.PomodoroRecord) error
+	GetPomodoroRecords(since time.Time) ([]*models.PomodoroRecord, error)
 
 	// Data operations
 	Backup(filename string) error
@@ -21,8 +38,17 @@ type Storage interface {
 
 	// Initialize storage
 	Initialize() error
+
+	// Close releases any resources held by the storage
+	Close() error
 }
 
+// Supported storage backends
+const (
+	TypeJSON   = "json"
+	TypeSQLite = "sqlite"
+)
+
 // ErrTaskNotFound is returned when a task with the specified ID is not found
 type ErrTaskNotFound struct {
 	ID string
@@ -31,3 +57,12 @@ type ErrTaskNotFound struct {
 func (e ErrTaskNotFound) Error() string {
 	return "task not found: " + e.ID
 }
+
+// ErrProjectNotFound is returned when a project with the specified ID is not found
+type ErrProjectNotFound struct {
+	ID string
+}
+
+func (e ErrProjectNotFound) Error() string {
+	return "project not found: " + e.ID
+}
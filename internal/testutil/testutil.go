@@ -0,0 +1,53 @@
+// Package testutil provides the fixtures shared by the tests of several
+// packages
+package testutil
+
+import (
+	"net"
+	"testing"
+
+	"github.com/user/todolist/internal/app"
+	"github.com/user/todolist/internal/client"
+	"github.com/user/todolist/internal/reminder"
+	"github.com/user/todolist/internal/server"
+)
+
+// NewApp returns an app working on a fresh data directory. It is closed when
+// the test ends.
+func NewApp(t testing.TB) *app.App {
+	t.Helper()
+	config := app.DefaultConfig()
+	config.SetDataDir(t.TempDir())
+	todoApp, err := app.NewApp(config)
+	if err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	t.Cleanup(func() { todoApp.Close() })
+	return todoApp
+}
+
+// NewClient starts an in-process server for the app and returns a client
+// connected to it. Both are shut down when the test ends.
+func NewClient(t testing.TB, todoApp *app.App) *client.Client {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		server.Serve(listener, todoApp, reminder.NewScheduler(todoApp))
+		close(done)
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		<-done
+	})
+
+	todoClient, err := client.NewClient(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { todoClient.Close() })
+	return todoClient
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// KeyInput switches the terminal on stdin to reading single key presses
+// without echoing them. Ctrl+C still sends an interrupt. It returns a
+// function that restores the previous mode, and fails when stdin is not a
+// terminal.
+func KeyInput() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal mode: %w", err)
+	}
+
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, fmt.Errorf("failed to set terminal mode: %w", err)
+	}
+
+	return func() { stty(strings.TrimSpace(state)) }, nil
+}
+
+// stty runs the stty command on the terminal attached to stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -2,13 +2,24 @@ package ui
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/user/todolist/internal/clock"
 	"github.com/user/todolist/internal/models"
 )
 
+// Clock decides e.g. which tasks are shown as overdue. It can be replaced to
+// render output as of a fixed time.
+var Clock clock.Clock = clock.System
+
+// Messages receives the error, success, warning and info messages. It is
+// switched to standard error when the output is meant for other programs.
+var Messages io.Writer = os.Stdout
+
 var (
 	// Color functions
 	titleColor          = color.New(color.FgHiWhite, color.Bold).SprintFunc()
@@ -24,16 +35,19 @@ var (
 	successColor        = color.New(color.FgGreen, color.Bold).SprintFunc()
 	warningColor        = color.New(color.FgYellow, color.Bold).SprintFunc()
 	infoColor           = color.New(color.FgCyan, color.Bold).SprintFunc()
+	highlightColor      = color.New(color.FgHiYellow, color.Bold, color.Underline).SprintFunc()
 )
 
-// PrintTask prints a task with colors
-func PrintTask(task *models.Task) {
-	status := "[ ]"
-	if task.Completed {
-		status = completedColor("[✓]")
-	} else if task.IsOverdue() {
-		status = errorColor("[!]")
-	}
+// PrintTask prints a task with colors. With deps, the tasks it is blocked by
+// and the tasks it blocks are shown by title; without, only its blockers' IDs.
+func PrintTask(task *models.Task, deps *models.Dependencies) {
+	printTask(task, "", "", deps)
+}
+
+// printTask prints a task with colors, indented by the given prefix and
+// followed by an optional subtask progress summary
+func printTask(task *models.Task, indent, progress string, deps *models.Dependencies) {
+	status := statusMarker(task)
 
 	priorityStr := ""
 	switch task.Priority {
@@ -55,22 +69,67 @@ func PrintTask(task *models.Task) {
 		reminderStr = fmt.Sprintf(" (Reminder: %s)", dateColor(task.ReminderAt.Format("2006-01-02 15:04")))
 	}
 
-	fmt.Printf("%s %s %s\n", status, titleColor(task.Title), idColor(fmt.Sprintf("(ID: %s)", task.ID)))
+	progressStr := ""
+	if progress != "" {
+		progressStr = " " + infoColor(fmt.Sprintf("[%s]", progress))
+	}
+
+	fmt.Printf("%s%s %s%s %s\n", indent, status, titleColor(task.Title), progressStr, idColor(fmt.Sprintf("(ID: %s)", task.ID)))
 
 	if task.Description != "" {
-		fmt.Printf("   %s\n", descriptionColor(task.Description))
+		fmt.Printf("%s   %s\n", indent, descriptionColor(task.Description))
+	}
+
+	statusStr := ""
+	if task.Status != models.StatusTodo && task.Status != "" {
+		statusStr = fmt.Sprintf(" | Status: %s", StatusColor(task.Status)(task.Status.String()))
 	}
 
-	fmt.Printf("   Priority: %s | Category: %s | Due: %s%s\n",
+	fmt.Printf("%s   Priority: %s | Category: %s%s | Due: %s%s\n",
+		indent,
 		priorityStr,
 		categoryColor(string(task.Category)),
+		statusStr,
 		dueStr,
 		reminderStr)
 
+	if task.Recurrence != nil {
+		fmt.Printf("%s   Repeats: %s\n", indent, dateColor(task.Recurrence.String()))
+	}
+
+	if extras := taskExtras(task.Tags, task.Context, task.Estimate, task.Energy); extras != "" {
+		fmt.Printf("%s   %s\n", indent, extras)
+	}
+
+	if task.TimeSpent > 0 {
+		fmt.Printf("%s   Time spent: %s\n", indent, dateColor(FormatEstimate(task.TimeSpent)))
+	}
+
+	if focus := FocusSummary(task); focus != "" {
+		fmt.Printf("%s   Focus: %s\n", indent, dateColor(focus))
+	}
+
+	for _, note := range task.Notes {
+		fmt.Printf("%s   Note (%s): %s\n", indent, dateColor(note.CreatedAt.Format("2006-01-02 15:04")), note.Text)
+	}
+
+	if deps == nil {
+		if len(task.BlockedBy) > 0 && !task.Completed {
+			fmt.Printf("%s   %s %s\n", indent, warningColor("Blocked by:"), idColor(strings.Join(task.BlockedBy, ", ")))
+		}
+	} else {
+		if blockers := deps.Blockers(task); len(blockers) > 0 && !task.Completed {
+			fmt.Printf("%s   %s %s\n", indent, warningColor("Blocked by:"), TaskRefs(blockers))
+		}
+		if dependents := deps.Blocking(task); len(dependents) > 0 {
+			fmt.Printf("%s   Blocking: %s\n", indent, TaskRefs(dependents))
+		}
+	}
+
 	fmt.Println()
 }
 
-// PrintTaskList prints a list of tasks with colors
+// PrintTaskList prints a list of tasks with colors, nesting subtasks under their parents
 func PrintTaskList(tasks []*models.Task, title string) {
 	if len(tasks) == 0 {
 		fmt.Println(infoColor("No tasks found."))
@@ -81,31 +140,200 @@ func PrintTaskList(tasks []*models.Task, title string) {
 	fmt.Println(strings.Repeat("-", len(title)))
 	fmt.Println()
 
-	for _, task := range tasks {
-		PrintTask(task)
+	deps := models.NewDependencies(tasks)
+
+	var printNodes func(nodes []*models.TaskNode, depth int)
+	printNodes = func(nodes []*models.TaskNode, depth int) {
+		for _, node := range nodes {
+			printTask(node.Task, strings.Repeat("    ", depth), node.ProgressString(), deps)
+			printNodes(node.Children, depth+1)
+		}
 	}
+	printNodes(models.BuildTaskTree(tasks), 0)
 
 	fmt.Printf(infoColor("Total: %d tasks\n\n"), len(tasks))
 }
 
+// PrintTaskTree prints tasks as a compact tree, one line per task.
+// Completed tasks are hidden unless showCompleted is set, but they still
+// count towards their parent's progress. With deps, open tasks are marked
+// with the number of tasks they are blocked by and blocking.
+func PrintTaskTree(tasks []*models.Task, showCompleted bool, deps *models.Dependencies) {
+	visible := func(nodes []*models.TaskNode) []*models.TaskNode {
+		var result []*models.TaskNode
+		for _, node := range nodes {
+			if showCompleted || !node.Task.Completed {
+				result = append(result, node)
+			}
+		}
+		return result
+	}
+
+	var printNodes func(nodes []*models.TaskNode, prefix string)
+	printNodes = func(nodes []*models.TaskNode, prefix string) {
+		nodes = visible(nodes)
+		for i, node := range nodes {
+			connector, childPrefix := "├── ", "│   "
+			if i == len(nodes)-1 {
+				connector, childPrefix = "└── ", "    "
+			}
+
+			fmt.Printf("%s%s%s\n", prefix, connector, treeLine(node, deps))
+			printNodes(node.Children, prefix+childPrefix)
+		}
+	}
+
+	roots := visible(models.BuildTaskTree(tasks))
+	if len(roots) == 0 {
+		fmt.Println(infoColor("No tasks found."))
+		return
+	}
+
+	for _, root := range roots {
+		fmt.Println(treeLine(root, deps))
+		printNodes(root.Children, "")
+	}
+}
+
+// treeLine formats a single task line for the compact tree view
+func treeLine(node *models.TaskNode, deps *models.Dependencies) string {
+	status := statusMarker(node.Task)
+
+	progressStr := ""
+	if progress := node.ProgressString(); progress != "" {
+		progressStr = " " + infoColor(fmt.Sprintf("(%s)", progress))
+	}
+
+	depsStr := ""
+	if deps != nil {
+		if blockers := deps.Blockers(node.Task); len(blockers) > 0 && !node.Task.Completed {
+			depsStr += " " + warningColor(fmt.Sprintf("[blocked by %d]", len(blockers)))
+		}
+		if dependents := deps.Blocking(node.Task); len(dependents) > 0 {
+			depsStr += " " + infoColor(fmt.Sprintf("[blocking %d]", len(dependents)))
+		}
+	}
+
+	return fmt.Sprintf("%s %s%s%s %s", status, titleColor(node.Task.Title), progressStr, depsStr,
+		idColor(fmt.Sprintf("(ID: %s)", node.Task.ID)))
+}
+
+// statusMarker returns the colored checkbox for a task; open tasks that are
+// overdue are flagged with "[!]"
+func statusMarker(task *models.Task) string {
+	if task.IsOverdue(Clock.Now()) {
+		return errorColor("[!]")
+	}
+	return StatusColor(task.Status)(task.Status.Marker())
+}
+
+// StatusColor returns the color function used for a status
+func StatusColor(status models.Status) func(a ...interface{}) string {
+	switch status {
+	case models.StatusInProgress:
+		return infoColor
+	case models.StatusWaiting, models.StatusBlocked:
+		return warningColor
+	case models.StatusDone:
+		return completedColor
+	case models.StatusCancelled:
+		return idColor
+	}
+	return fmt.Sprint
+}
+
+// taskExtras formats tags, context, estimate and energy on one line, or
+// returns an empty string if none are set
+func taskExtras(tags []string, context string, estimate time.Duration, energy models.Energy) string {
+	var parts []string
+	if len(tags) > 0 {
+		parts = append(parts, "Tags: "+categoryColor(strings.Join(tags, ", ")))
+	}
+	if context != "" {
+		parts = append(parts, "Context: "+categoryColor("@"+context))
+	}
+	if estimate > 0 {
+		parts = append(parts, "Estimate: "+dateColor(FormatEstimate(estimate)))
+	}
+	if energy != "" {
+		parts = append(parts, "Energy: "+categoryColor(string(energy)))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// TaskRefs formats tasks as a comma-separated list of titles with their IDs
+func TaskRefs(tasks []*models.Task) string {
+	refs := make([]string, len(tasks))
+	for i, task := range tasks {
+		refs[i] = fmt.Sprintf("%s %s", task.Title, idColor(fmt.Sprintf("(ID: %s)", task.ID)))
+	}
+	return strings.Join(refs, ", ")
+}
+
+// FocusSummary describes whether a task is snoozed in focus mode and how
+// often it was skipped, or returns an empty string if neither applies
+func FocusSummary(task *models.Task) string {
+	var parts []string
+	if task.IsFocusSnoozed(Clock.Now()) {
+		parts = append(parts, "snoozed until "+task.Focus.SnoozedUntil.Format("2006-01-02 15:04"))
+	}
+	if skips := task.FocusSkipCount(); skips > 0 {
+		parts = append(parts, fmt.Sprintf("skipped %d×", skips))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatEstimate formats a time estimate compactly, e.g. "15m" or "1h30m"
+func FormatEstimate(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+// PrintTaskPreview prints the fields parsed from a quick-add line before the
+// task is saved, along with the messages
+func PrintTaskPreview(spec models.TaskSpec) {
+	parts := []string{
+		"Priority: " + string(spec.Priority),
+		"Category: " + categoryColor(string(spec.Category)),
+	}
+	if !spec.DueDate.IsZero() {
+		parts = append(parts, "Due: "+dateColor(spec.DueDate.Format("Mon 2006-01-02 15:04")))
+	}
+	if extras := taskExtras(spec.Tags, spec.Context, spec.Estimate, spec.Energy); extras != "" {
+		parts = append(parts, extras)
+	}
+
+	fmt.Fprintf(Messages, "  %s %s\n", titleColor(spec.Title), idColor("→ "+strings.Join(parts, " | ")))
+}
+
 // PrintError prints an error message
 func PrintError(format string, a ...interface{}) {
-	fmt.Println(errorColor(fmt.Sprintf(format, a...)))
+	fmt.Fprintln(Messages, errorColor(fmt.Sprintf(format, a...)))
 }
 
 // PrintSuccess prints a success message
 func PrintSuccess(format string, a ...interface{}) {
-	fmt.Println(successColor(fmt.Sprintf(format, a...)))
+	fmt.Fprintln(Messages, successColor(fmt.Sprintf(format, a...)))
 }
 
 // PrintWarning prints a warning message
 func PrintWarning(format string, a ...interface{}) {
-	fmt.Println(warningColor(fmt.Sprintf(format, a...)))
+	fmt.Fprintln(Messages, warningColor(fmt.Sprintf(format, a...)))
 }
 
 // PrintInfo prints an info message
 func PrintInfo(format string, a ...interface{}) {
-	fmt.Println(infoColor(fmt.Sprintf(format, a...)))
+	fmt.Fprintln(Messages, infoColor(fmt.Sprintf(format, a...)))
 }
 
 // ProgressBar displays a simple progress bar for the Pomodoro timer
@@ -119,11 +347,11 @@ func ProgressBar(current, total int, width int) {
 
 // CountdownTimer displays a countdown timer for the Pomodoro timer
 func CountdownTimer(duration time.Duration, onTick func(remaining time.Duration)) {
-	start := time.Now()
+	start := Clock.Now()
 	end := start.Add(duration)
 
 	for {
-		remaining := end.Sub(time.Now())
+		remaining := end.Sub(Clock.Now())
 		if remaining <= 0 {
 			break
 		}
@@ -132,6 +360,51 @@ func CountdownTimer(duration time.Duration, onTick func(remaining time.Duration)
 			onTick(remaining)
 		}
 
-		time.Sleep(time.Second)
+		Clock.Sleep(time.Second)
+	}
+}
+
+// ClearScreen clears the terminal and moves the cursor to the top left
+func ClearScreen() {
+	fmt.Print("\033[H\033[2J")
+}
+
+// ProjectColor returns the color function for a project color, or plain
+// output if the project has no color
+func ProjectColor(name string) func(a ...interface{}) string {
+	attributes := map[string]color.Attribute{
+		"red":     color.FgRed,
+		"green":   color.FgGreen,
+		"yellow":  color.FgYellow,
+		"blue":    color.FgBlue,
+		"magenta": color.FgMagenta,
+		"cyan":    color.FgCyan,
+		"white":   color.FgHiWhite,
+	}
+	if attribute, ok := attributes[name]; ok {
+		return color.New(attribute, color.Bold).SprintFunc()
+	}
+	return fmt.Sprint
+}
+
+// Highlight returns the text of a search snippet with its matching words
+// marked, in color or, when colors are off, in square brackets
+func Highlight(snippet models.Snippet) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range snippet.Matches {
+		start, end := match[0], match[1]
+		if start < last || end > len(snippet.Text) || start > end {
+			continue
+		}
+		b.WriteString(snippet.Text[last:start])
+		if color.NoColor {
+			b.WriteString("[" + snippet.Text[start:end] + "]")
+		} else {
+			b.WriteString(highlightColor(snippet.Text[start:end]))
+		}
+		last = end
 	}
+	b.WriteString(snippet.Text[last:])
+	return b.String()
 }
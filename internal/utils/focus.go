@@ -1,70 +1,112 @@
 package utils
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
 	"github.com/user/todolist/internal/models"
 )
 
-// TaskScore represents a task with its calculated score for focus mode
-type TaskScore struct {
-	Task  *models.Task
-	Score float64
+// FocusWeights holds the points each factor adds to a task's focus score
+type FocusWeights struct {
+	PriorityHigh   float64 `json:"priority_high"`
+	PriorityMedium float64 `json:"priority_medium"`
+	PriorityLow    float64 `json:"priority_low"`
+	Overdue        float64 `json:"overdue"`
+	DueWithinDay   float64 `json:"due_within_day"`
+	DueWithin2Days float64 `json:"due_within_2_days"`
+	DueWithin3Days float64 `json:"due_within_3_days"`
+	DueWithinWeek  float64 `json:"due_within_week"`
+	DueLater       float64 `json:"due_later"`
+	ReminderDue    float64 `json:"reminder_due"`
+	AgePerDay      float64 `json:"age_per_day"`
+	EnergyMatch    float64 `json:"energy_match"`
+	FitsTime       float64 `json:"fits_time"`
 }
 
-// FocusMode suggests the next task to focus on based on priority, due date, and other factors
-func FocusMode(tasks []*models.Task) []*models.Task {
-	// Filter out completed tasks
-	var incompleteTasks []*models.Task
-	for _, task := range tasks {
-		if !task.Completed {
-			incompleteTasks = append(incompleteTasks, task)
-		}
-	}
-
-	if len(incompleteTasks) == 0 {
-		return nil
+// DefaultFocusWeights returns the default focus scoring weights
+func DefaultFocusWeights() FocusWeights {
+	return FocusWeights{
+		PriorityHigh:   100,
+		PriorityMedium: 50,
+		PriorityLow:    25,
+		Overdue:        200,
+		DueWithinDay:   150,
+		DueWithin2Days: 100,
+		DueWithin3Days: 75,
+		DueWithinWeek:  50,
+		DueLater:       25,
+		ReminderDue:    50,
+		AgePerDay:      2,
+		EnergyMatch:    30,
+		FitsTime:       30,
 	}
+}
 
-	// Calculate scores for each task
-	var scoredTasks []TaskScore
-	now := time.Now()
+// FocusMode ranks the incomplete tasks that fit the options by their focus
+// score as of the given time, highest first. Tasks that are on hold, blocked
+// or snoozed, and tasks that take more energy or time than available, are
+// left out; tasks without an energy level or estimate are kept.
+func FocusMode(tasks []*models.Task, now time.Time, weights FocusWeights, options models.FocusOptions) []models.TaskScore {
+	deps := models.NewDependencies(tasks)
 
-	for _, task := range incompleteTasks {
-		score := calculateTaskScore(task, now)
-		scoredTasks = append(scoredTasks, TaskScore{
-			Task:  task,
-			Score: score,
-		})
+	var scoredTasks []models.TaskScore
+	for _, task := range tasks {
+		if !task.Completed && !task.Status.IsOnHold() && !deps.IsBlocked(task) && !task.IsFocusSnoozed(now) && fitsFocusOptions(task, options) {
+			scoredTasks = append(scoredTasks, ScoreTask(task, now, weights, options))
+		}
 	}
 
 	// Sort tasks by score (descending)
-	sort.Slice(scoredTasks, func(i, j int) bool {
+	sort.SliceStable(scoredTasks, func(i, j int) bool {
 		return scoredTasks[i].Score > scoredTasks[j].Score
 	})
 
-	// Return tasks in order of priority
-	result := make([]*models.Task, len(scoredTasks))
-	for i, scoredTask := range scoredTasks {
-		result[i] = scoredTask.Task
+	if options.Limit > 0 && len(scoredTasks) > options.Limit {
+		scoredTasks = scoredTasks[:options.Limit]
 	}
+	return scoredTasks
+}
 
-	return result
+// fitsFocusOptions reports whether a task matches the tag and project filters
+// and can be done with the energy and time available
+func fitsFocusOptions(task *models.Task, options models.FocusOptions) bool {
+	if !options.Tags.Matches(task) {
+		return false
+	}
+	if options.Project != "" && task.Project != options.Project {
+		return false
+	}
+	if options.Energy != "" && task.Energy.Level() > options.Energy.Level() {
+		return false
+	}
+	if options.Available > 0 && task.Estimate > options.Available {
+		return false
+	}
+	return true
 }
 
-// calculateTaskScore calculates a score for a task based on various factors
-func calculateTaskScore(task *models.Task, now time.Time) float64 {
-	var score float64
+// ScoreTask calculates a task's focus score and the contribution of each
+// factor. Tasks that match the energy level in the options, or are known to
+// fit in the time available, score higher.
+func ScoreTask(task *models.Task, now time.Time, weights FocusWeights, options models.FocusOptions) models.TaskScore {
+	result := models.TaskScore{Task: task}
+	add := func(name string, points float64) {
+		if points != 0 {
+			result.Factors = append(result.Factors, models.ScoreFactor{Name: name, Points: points})
+			result.Score += points
+		}
+	}
 
-	// Priority factor (0-100)
+	// Priority factor
 	switch task.Priority {
 	case models.PriorityHigh:
-		score += 100
+		add("high priority", weights.PriorityHigh)
 	case models.PriorityMedium:
-		score += 50
+		add("medium priority", weights.PriorityMedium)
 	case models.PriorityLow:
-		score += 25
+		add("low priority", weights.PriorityLow)
 	}
 
 	// Due date factor
@@ -72,46 +114,48 @@ func calculateTaskScore(task *models.Task, now time.Time) float64 {
 		// Calculate hours until due
 		hoursUntilDue := task.DueDate.Sub(now).Hours()
 
-		if hoursUntilDue < 0 {
+		switch {
+		case hoursUntilDue < 0:
 			// Overdue tasks get highest priority
-			score += 200
-		} else if hoursUntilDue < 24 {
-			// Due within 24 hours
-			score += 150
-		} else if hoursUntilDue < 48 {
-			// Due within 48 hours
-			score += 100
-		} else if hoursUntilDue < 72 {
-			// Due within 72 hours
-			score += 75
-		} else if hoursUntilDue < 168 {
-			// Due within a week
-			score += 50
-		} else {
-			// Due later
-			score += 25
+			add("overdue", weights.Overdue)
+		case hoursUntilDue < 24:
+			add("due within a day", weights.DueWithinDay)
+		case hoursUntilDue < 48:
+			add("due within 2 days", weights.DueWithin2Days)
+		case hoursUntilDue < 72:
+			add("due within 3 days", weights.DueWithin3Days)
+		case hoursUntilDue < 168:
+			add("due within a week", weights.DueWithinWeek)
+		default:
+			add("due later", weights.DueLater)
 		}
 	}
 
 	// Reminder factor
-	if !task.ReminderAt.IsZero() && now.After(task.ReminderAt) {
-		// Reminder is due
-		score += 50
+	if task.IsReminderDue(now) {
+		add("reminder is due", weights.ReminderDue)
 	}
 
 	// Age factor - older tasks get a slight boost to prevent them from being forgotten
-	ageHours := now.Sub(task.CreatedAt).Hours()
-	ageFactor := ageHours / 24 // Days old
-	score += ageFactor * 2     // 2 points per day old
+	days := now.Sub(task.CreatedAt).Hours() / 24
+	add(fmt.Sprintf("age (%.1f days)", days), days*weights.AgePerDay)
 
-	return score
+	// Fit with the current state
+	if options.Energy != "" && task.Energy == options.Energy {
+		add(fmt.Sprintf("matches your energy (%s)", options.Energy), weights.EnergyMatch)
+	}
+	if options.Available > 0 && task.Estimate > 0 && task.Estimate <= options.Available {
+		add(fmt.Sprintf("fits in your time (%s of %s)", FormatDuration(task.Estimate), FormatDuration(options.Available)), weights.FitsTime)
+	}
+
+	return result
 }
 
 // GetNextFocusTask returns the single highest priority task to focus on
-func GetNextFocusTask(tasks []*models.Task) *models.Task {
-	prioritizedTasks := FocusMode(tasks)
+func GetNextFocusTask(tasks []*models.Task, now time.Time, weights FocusWeights) *models.Task {
+	prioritizedTasks := FocusMode(tasks, now, weights, models.FocusOptions{Limit: 1})
 	if len(prioritizedTasks) == 0 {
 		return nil
 	}
-	return prioritizedTasks[0]
+	return prioritizedTasks[0].Task
 }
@@ -2,12 +2,11 @@ package utils
 
 import (
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync"
 	"time"
 
-	"github.com/user/todolist/internal/ui"
+	"github.com/user/todolist/internal/clock"
+	"github.com/user/todolist/internal/models"
 )
 
 // PomodoroConfig holds configuration for a Pomodoro session
@@ -16,6 +15,8 @@ type PomodoroConfig struct {
 	ShortBreakDuration time.Duration
 	LongBreakDuration  time.Duration
 	LongBreakInterval  int
+	Cycles             int // number of work cycles before the session ends, 0 for no limit
+	TaskID             string
 	TaskName           string
 }
 
@@ -30,90 +31,316 @@ func DefaultPomodoroConfig() PomodoroConfig {
 	}
 }
 
-// PomodoroSession represents a Pomodoro session
+// PomodoroSession represents a Pomodoro session. It is a state machine that
+// alternates between work and break phases; Run drives it in real time and
+// the other methods may be called concurrently to control it.
 type PomodoroSession struct {
-	Config       PomodoroConfig
-	CurrentCycle int
-	IsWorking    bool
-	StartTime    time.Time
-	EndTime      time.Time
+	Config          PomodoroConfig
+	CurrentCycle    int
+	Phase           models.PomodoroPhase
+	CompletedCycles int
+	SessionStart    time.Time
+	StartTime       time.Time
+	EndTime         time.Time
+	PausedAt        time.Time
+	PausedFor       time.Duration // time spent paused in the current phase
+	StopAfterPhase  bool          // end the session when the current phase is over
+	Stopped         bool
+	EndedAt         time.Time
+
+	// Summary of the session so far
+	WorkTime      time.Duration // time worked in finished work phases
+	Interrupted   int           // work phases that were skipped or stopped early
+	Interruptions int           // interruptions logged while the session ran
+
+	// OnWorkEnd is called with the record of every work phase that ends,
+	// whether it ran to the end, was skipped or the session was stopped
+	OnWorkEnd func(record models.PomodoroRecord)
+
+	clock clock.Clock
+	mu    sync.Mutex
 }
 
-// NewPomodoroSession creates a new Pomodoro session with the given configuration
-func NewPomodoroSession(config PomodoroConfig) *PomodoroSession {
+// NewPomodoroSession creates a new Pomodoro session with the given
+// configuration, starting now on the given clock
+func NewPomodoroSession(config PomodoroConfig, clk clock.Clock) *PomodoroSession {
+	if config.LongBreakInterval <= 0 {
+		config.LongBreakInterval = 4
+	}
+
+	now := clk.Now()
 	return &PomodoroSession{
 		Config:       config,
+		clock:        clk,
 		CurrentCycle: 1,
-		IsWorking:    true,
-		StartTime:    time.Now(),
-		EndTime:      time.Now().Add(config.WorkDuration),
+		Phase:        models.PhaseWork,
+		SessionStart: now,
+		StartTime:    now,
+		EndTime:      now.Add(config.WorkDuration),
 	}
 }
 
-// Start starts the Pomodoro session
-func (p *PomodoroSession) Start() {
-	// Handle Ctrl+C
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-sigCh
-		fmt.Println("\nPomodoro session interrupted.")
-		os.Exit(0)
-	}()
+// Run advances the session through its phases until it is stopped or stop
+// is closed. onTick is called every second with the current status, onPhase
+// whenever a new phase begins and onEnd when the session ends by itself,
+// i.e. after its last cycle.
+func (p *PomodoroSession) Run(stop <-chan struct{}, onTick, onPhase, onEnd func(status models.PomodoroStatus)) {
+	ticker := p.clock.NewTicker(time.Second)
+	defer ticker.Stop()
 
 	for {
-		if p.IsWorking {
-			ui.PrintInfo("🍅 WORK CYCLE %d: Focus on '%s' for %s",
-				p.CurrentCycle, p.Config.TaskName, formatDuration(p.Config.WorkDuration))
-		} else {
-			breakType := "SHORT BREAK"
-			breakDuration := p.Config.ShortBreakDuration
-
-			if p.CurrentCycle%p.Config.LongBreakInterval == 0 {
-				breakType = "LONG BREAK"
-				breakDuration = p.Config.LongBreakDuration
-			}
-
-			ui.PrintInfo("☕ %s: Take a break for %s", breakType, formatDuration(breakDuration))
+		select {
+		case <-stop:
+			return
+		case <-ticker.C():
 		}
 
-		duration := p.Config.WorkDuration
-		if !p.IsWorking {
-			if p.CurrentCycle%p.Config.LongBreakInterval == 0 {
-				duration = p.Config.LongBreakDuration
-			} else {
-				duration = p.Config.ShortBreakDuration
+		now := p.clock.Now()
+		changed := p.Advance(now)
+		status := p.Status(now)
+		if !status.Active {
+			if changed && onEnd != nil {
+				onEnd(status)
 			}
+			return
 		}
 
-		// Run the timer
-		ui.CountdownTimer(duration, func(remaining time.Duration) {
-			minutes := int(remaining.Minutes())
-			seconds := int(remaining.Seconds()) % 60
-			fmt.Printf("\r%02d:%02d remaining", minutes, seconds)
-		})
-
-		fmt.Println()
-
-		// Cycle completed
-		if p.IsWorking {
-			ui.PrintSuccess("✅ Work cycle completed!")
-		} else {
-			ui.PrintSuccess("✅ Break completed!")
-			p.CurrentCycle++
+		if changed && onPhase != nil {
+			onPhase(status)
 		}
+		if onTick != nil {
+			onTick(status)
+		}
+	}
+}
+
+// Advance moves the session to the next phase once the current one is over
+// and reports whether it did. The session ends instead after its last work
+// cycle or when asked to stop after the current phase.
+func (p *PomodoroSession) Advance(now time.Time) bool {
+	p.mu.Lock()
+	if p.Stopped || !p.PausedAt.IsZero() || now.Before(p.EndTime) {
+		p.mu.Unlock()
+		return false
+	}
+
+	var record *models.PomodoroRecord
+	if p.Phase == models.PhaseWork {
+		p.CompletedCycles++
+		record = p.endWork(p.EndTime, true, "")
+	}
+
+	lastCycle := p.Config.Cycles > 0 && p.CompletedCycles >= p.Config.Cycles
+	if p.StopAfterPhase || lastCycle {
+		p.Stopped = true
+		p.EndedAt = p.EndTime
+	} else {
+		p.nextPhase(now)
+	}
+	p.mu.Unlock()
+
+	p.recordWork(record)
+	return true
+}
+
+// nextPhase starts the phase that follows the current one
+func (p *PomodoroSession) nextPhase(now time.Time) {
+	if p.Phase == models.PhaseWork {
+		p.Phase = models.PhaseShortBreak
+		if p.CurrentCycle%p.Config.LongBreakInterval == 0 {
+			p.Phase = models.PhaseLongBreak
+		}
+	} else {
+		p.Phase = models.PhaseWork
+		p.CurrentCycle++
+	}
+
+	p.StartTime = now
+	p.EndTime = now.Add(p.phaseDuration(p.Phase))
+	p.PausedFor = 0
+}
+
+// endWork ends the current phase at end, adds it to the session summary and
+// returns its record, or nil if it is not a work phase. The caller must hold
+// the lock.
+func (p *PomodoroSession) endWork(end time.Time, completed bool, reason string) *models.PomodoroRecord {
+	if p.Phase != models.PhaseWork {
+		return nil
+	}
+
+	paused := p.PausedFor
+	if !p.PausedAt.IsZero() {
+		paused += end.Sub(p.PausedAt)
+	}
+
+	record := &models.PomodoroRecord{
+		ID:        fmt.Sprintf("%d", p.StartTime.UnixNano()),
+		TaskID:    p.Config.TaskID,
+		Start:     p.StartTime,
+		End:       end,
+		Paused:    paused,
+		Completed: completed,
+		Reason:    reason,
+	}
+
+	p.WorkTime += record.Duration()
+	if !completed {
+		p.Interrupted++
+	}
+	return record
+}
+
+// recordWork passes a finished work phase to OnWorkEnd. It must be called
+// without holding the lock.
+func (p *PomodoroSession) recordWork(record *models.PomodoroRecord) {
+	if record != nil && p.OnWorkEnd != nil {
+		p.OnWorkEnd(*record)
+	}
+}
+
+// phaseDuration returns how long the given phase lasts
+func (p *PomodoroSession) phaseDuration(phase models.PomodoroPhase) time.Duration {
+	switch phase {
+	case models.PhaseShortBreak:
+		return p.Config.ShortBreakDuration
+	case models.PhaseLongBreak:
+		return p.Config.LongBreakDuration
+	default:
+		return p.Config.WorkDuration
+	}
+}
+
+// Pause pauses the countdown of the current phase
+func (p *PomodoroSession) Pause(now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Stopped {
+		return models.Invalidf("the pomodoro session has ended")
+	}
+	if !p.PausedAt.IsZero() {
+		return models.Invalidf("the pomodoro session is already paused")
+	}
+
+	p.PausedAt = now
+	return nil
+}
+
+// Resume continues a paused countdown where it left off
+func (p *PomodoroSession) Resume(now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Stopped {
+		return models.Invalidf("the pomodoro session has ended")
+	}
+	if p.PausedAt.IsZero() {
+		return models.Invalidf("the pomodoro session is not paused")
+	}
+
+	p.EndTime = p.EndTime.Add(now.Sub(p.PausedAt))
+	p.PausedFor += now.Sub(p.PausedAt)
+	p.PausedAt = time.Time{}
+	return nil
+}
+
+// Skip ends the current phase early and starts the next one. Skipping a
+// work phase does not count it as completed.
+func (p *PomodoroSession) Skip(now time.Time) error {
+	p.mu.Lock()
+	if p.Stopped {
+		p.mu.Unlock()
+		return models.Invalidf("the pomodoro session has ended")
+	}
+
+	record := p.endWork(now, false, "skipped")
+	p.PausedAt = time.Time{}
+	p.nextPhase(now)
+	p.mu.Unlock()
+
+	p.recordWork(record)
+	return nil
+}
 
-		// Toggle between work and break
-		p.IsWorking = !p.IsWorking
+// Stop ends the session. A work phase in progress is recorded as interrupted
+// for the given reason.
+func (p *PomodoroSession) Stop(now time.Time, reason string) {
+	p.mu.Lock()
+	if p.Stopped {
+		p.mu.Unlock()
+		return
+	}
+
+	p.Stopped = true
+	p.EndedAt = now
+	record := p.endWork(now, false, reason)
+	p.mu.Unlock()
+
+	p.recordWork(record)
+}
+
+// FinishAfterPhase lets the current phase run to its end and then ends the
+// session, e.g. to stop after the work cycle in progress
+func (p *PomodoroSession) FinishAfterPhase(now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Stopped {
+		return models.Invalidf("the pomodoro session has ended")
+	}
+
+	p.StopAfterPhase = true
+	return nil
+}
+
+// LogInterruption counts an interruption that was noted without stopping the timer
+func (p *PomodoroSession) LogInterruption(now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Stopped {
+		return models.Invalidf("the pomodoro session has ended")
+	}
+
+	p.Interruptions++
+	return nil
+}
+
+// Status returns a snapshot of the session
+func (p *PomodoroSession) Status(now time.Time) models.PomodoroStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	remaining := p.EndTime.Sub(now)
+	if !p.PausedAt.IsZero() {
+		remaining = p.EndTime.Sub(p.PausedAt)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
 
-		fmt.Println()
-		time.Sleep(2 * time.Second)
+	return models.PomodoroStatus{
+		Active:          !p.Stopped,
+		TaskID:          p.Config.TaskID,
+		TaskTitle:       p.Config.TaskName,
+		Phase:           p.Phase,
+		Cycle:           p.CurrentCycle,
+		Cycles:          p.Config.Cycles,
+		CompletedCycles: p.CompletedCycles,
+		Paused:          !p.PausedAt.IsZero(),
+		StopAfterPhase:  p.StopAfterPhase,
+		Remaining:       remaining.Round(time.Second),
+		PhaseEnd:        p.EndTime,
+		StartedAt:       p.SessionStart,
+		EndedAt:         p.EndedAt,
+		WorkTime:        p.WorkTime,
+		Interrupted:     p.Interrupted,
+		Interruptions:   p.Interruptions,
 	}
 }
 
-// formatDuration formats a duration in a human-readable format
-func formatDuration(d time.Duration) string {
+// FormatDuration formats a duration in a human-readable format
+func FormatDuration(d time.Duration) string {
 	minutes := int(d.Minutes())
 	if minutes == 1 {
 		return "1 minute"
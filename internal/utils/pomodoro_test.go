@@ -0,0 +1,233 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/todolist/internal/clock"
+	"github.com/user/todolist/internal/models"
+)
+
+var start = time.Date(2025, time.March, 12, 9, 0, 0, 0, time.UTC)
+
+// newTestSession returns a session on a fake clock that collects the records
+// of its work phases
+func newTestSession(config PomodoroConfig) (*PomodoroSession, *clock.Fake, *[]models.PomodoroRecord) {
+	clk := clock.NewFake(start)
+	session := NewPomodoroSession(config, clk)
+	var records []models.PomodoroRecord
+	session.OnWorkEnd = func(record models.PomodoroRecord) {
+		records = append(records, record)
+	}
+	return session, clk, &records
+}
+
+func testConfig() PomodoroConfig {
+	config := DefaultPomodoroConfig()
+	config.LongBreakInterval = 2
+	config.Cycles = 3
+	config.TaskID = "42"
+	return config
+}
+
+func TestPomodoroPhases(t *testing.T) {
+	session, clk, records := newTestSession(testConfig())
+
+	steps := []struct {
+		after time.Duration
+		phase models.PomodoroPhase
+		cycle int
+	}{
+		{25 * time.Minute, models.PhaseShortBreak, 1},
+		{5 * time.Minute, models.PhaseWork, 2},
+		{25 * time.Minute, models.PhaseLongBreak, 2},
+		{15 * time.Minute, models.PhaseWork, 3},
+	}
+	for _, step := range steps {
+		// Nothing happens before the phase is over
+		clk.Advance(step.after - time.Second)
+		if session.Advance(clk.Now()) {
+			t.Fatalf("phase %s ended a second early", session.Phase)
+		}
+
+		clk.Advance(time.Second)
+		if !session.Advance(clk.Now()) {
+			t.Fatalf("phase %s did not end on time", session.Phase)
+		}
+		status := session.Status(clk.Now())
+		if status.Phase != step.phase || status.Cycle != step.cycle {
+			t.Fatalf("at %s got %s in cycle %d, want %s in cycle %d",
+				clk.Now().Sub(start), status.Phase, status.Cycle, step.phase, step.cycle)
+		}
+		if status.Remaining != session.phaseDuration(step.phase) {
+			t.Errorf("%s starts with %s remaining", status.Phase, status.Remaining)
+		}
+	}
+
+	// The last cycle ends the session
+	clk.Advance(25 * time.Minute)
+	session.Advance(clk.Now())
+	status := session.Status(clk.Now())
+	if status.Active {
+		t.Fatal("session is still active after its last cycle")
+	}
+	if !status.EndedAt.Equal(start.Add(95*time.Minute)) || status.CompletedCycles != 3 {
+		t.Errorf("session ended at %s after %d cycles", status.EndedAt, status.CompletedCycles)
+	}
+	if status.WorkTime != 75*time.Minute {
+		t.Errorf("worked %s, want 75m", status.WorkTime)
+	}
+
+	if len(*records) != 3 {
+		t.Fatalf("got %d work records, want 3", len(*records))
+	}
+	for _, record := range *records {
+		if !record.Completed || record.TaskID != "42" || record.Duration() != 25*time.Minute {
+			t.Errorf("got record %+v, want a completed 25m work phase", record)
+		}
+	}
+}
+
+func TestPomodoroPauseResume(t *testing.T) {
+	session, clk, records := newTestSession(testConfig())
+
+	clk.Advance(10 * time.Minute)
+	if err := session.Pause(clk.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Pause(clk.Now()); err == nil {
+		t.Error("pausing twice succeeded")
+	}
+
+	// The countdown stands still while paused
+	clk.Advance(5 * time.Minute)
+	if status := session.Status(clk.Now()); !status.Paused || status.Remaining != 15*time.Minute {
+		t.Errorf("paused session has %s remaining (paused %v), want 15m", status.Remaining, status.Paused)
+	}
+	if session.Advance(clk.Now().Add(time.Hour)) {
+		t.Error("paused phase ended")
+	}
+
+	if err := session.Resume(clk.Now()); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(15 * time.Minute)
+	if !session.Advance(clk.Now()) {
+		t.Fatal("phase did not end 15m after resuming")
+	}
+
+	if len(*records) != 1 {
+		t.Fatalf("got %d work records, want 1", len(*records))
+	}
+	record := (*records)[0]
+	if record.Paused != 5*time.Minute || record.Duration() != 25*time.Minute {
+		t.Errorf("record paused %s and lasted %s, want 5m and 25m", record.Paused, record.Duration())
+	}
+}
+
+func TestPomodoroSkipAndStop(t *testing.T) {
+	session, clk, records := newTestSession(testConfig())
+
+	clk.Advance(10 * time.Minute)
+	if err := session.Skip(clk.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if session.Phase != models.PhaseShortBreak {
+		t.Errorf("skipping work started %s, want a short break", session.Phase)
+	}
+
+	// Skipping a break does not record anything
+	clk.Advance(time.Minute)
+	if err := session.Skip(clk.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.Advance(3 * time.Minute)
+	session.Stop(clk.Now(), "phone call")
+	if err := session.Skip(clk.Now()); err == nil {
+		t.Error("skipping in a stopped session succeeded")
+	}
+
+	status := session.Status(clk.Now())
+	if status.Active || status.Interrupted != 2 || status.WorkTime != 13*time.Minute {
+		t.Errorf("got active %v, %d interrupted and %s worked; want a stopped session with 2 interrupted and 13m worked",
+			status.Active, status.Interrupted, status.WorkTime)
+	}
+
+	want := []struct {
+		duration time.Duration
+		reason   string
+	}{
+		{10 * time.Minute, "skipped"},
+		{3 * time.Minute, "phone call"},
+	}
+	if len(*records) != len(want) {
+		t.Fatalf("got %d work records, want %d", len(*records), len(want))
+	}
+	for i, record := range *records {
+		if record.Completed || record.Duration() != want[i].duration || record.Reason != want[i].reason {
+			t.Errorf("record %d lasted %s for %q (completed %v), want an interrupted %s for %q",
+				i, record.Duration(), record.Reason, record.Completed, want[i].duration, want[i].reason)
+		}
+	}
+}
+
+func TestPomodoroFinishAfterPhase(t *testing.T) {
+	session, clk, _ := newTestSession(testConfig())
+
+	clk.Advance(10 * time.Minute)
+	if err := session.FinishAfterPhase(clk.Now()); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(15 * time.Minute)
+	session.Advance(clk.Now())
+
+	if status := session.Status(clk.Now()); status.Active || status.CompletedCycles != 1 {
+		t.Errorf("got active %v after %d cycles, want the session to end after the first", status.Active, status.CompletedCycles)
+	}
+}
+
+// TestPomodoroRun drives a whole session through Run by moving the fake clock
+func TestPomodoroRun(t *testing.T) {
+	config := testConfig()
+	config.Cycles = 2
+	session, clk, records := newTestSession(config)
+
+	phases := make(chan models.PomodoroPhase, 10)
+	ended := make(chan models.PomodoroStatus, 1)
+	done := make(chan struct{})
+	go func() {
+		session.Run(make(chan struct{}),
+			nil,
+			func(status models.PomodoroStatus) { phases <- status.Phase },
+			func(status models.PomodoroStatus) { ended <- status })
+		close(done)
+	}()
+
+	// Each step fires one tick, once Run has its ticker
+	var status models.PomodoroStatus
+	deadline := time.After(5 * time.Second)
+	for running := true; running; {
+		clk.Advance(time.Minute)
+		select {
+		case status = <-ended:
+			running = false
+		case <-time.After(time.Millisecond):
+		case <-deadline:
+			t.Fatal("session did not end")
+		}
+	}
+	<-done
+
+	if status.CompletedCycles != 2 || len(*records) != 2 {
+		t.Errorf("session ended after %d cycles with %d records, want 2", status.CompletedCycles, len(*records))
+	}
+	close(phases)
+	var got []models.PomodoroPhase
+	for phase := range phases {
+		got = append(got, phase)
+	}
+	if len(got) != 2 || got[0] != models.PhaseShortBreak || got[1] != models.PhaseWork {
+		t.Errorf("got phases %v, want a short break and a work phase", got)
+	}
+}